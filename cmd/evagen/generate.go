@@ -0,0 +1,438 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+const (
+
+	// ErrNameCollision defines the error for two idents, or an ident and a generated name, that make the same Go name.
+	ErrNameCollision = edn.ErrorMessage("Name collision")
+
+	// evaImport is the import path of the eva package.
+	evaImport = "github.com/Workiva/eva-client-go/eva"
+)
+
+// goType is the Go type the values of a value type are held in.
+type goType struct {
+
+	// name of the type, i.e. `*big.Int`.
+	name string
+
+	// importPath of the package of the type, empty for the builtin types.
+	importPath string
+
+	// isSet is the format of the condition on a value of the type to assert it, the zero values are left out.
+	isSet string
+}
+
+// goTypes are the types of the values, the other value types are kept as edn elements.
+var goTypes = map[eva.ValueType]goType{
+	eva.String:  {name: "string", isSet: `%s != ""`},
+	eva.Keyword: {name: "string", isSet: `%s != ""`},
+	eva.URI:     {name: "string", isSet: `%s != ""`},
+	eva.Boolean: {name: "bool", isSet: `%s`},
+	eva.Long:    {name: "int64", isSet: `%s != 0`},
+	eva.Float:   {name: "float64", isSet: `%s != 0`},
+	eva.Double:  {name: "float64", isSet: `%s != 0`},
+	eva.BigInt:  {name: "*big.Int", importPath: "math/big", isSet: `%s != nil`},
+	eva.BigDec:  {name: "*big.Float", importPath: "math/big", isSet: `%s != nil`},
+	eva.Ref:     {name: "*eva.EntityRef", importPath: evaImport, isSet: `%s != nil`},
+	eva.Instant: {name: "time.Time", importPath: "time", isSet: `!%s.IsZero()`},
+	eva.UUID:    {name: "uuid.UUID", importPath: "github.com/mattrobenolt/gocql/uuid", isSet: `%s != (uuid.UUID{})`},
+}
+
+// elementType holds the values of the value types without a Go type.
+var elementType = goType{name: "edn.Element", importPath: "github.com/Workiva/eva-client-go/edn", isSet: `%s != nil`}
+
+// reservedFields are the names of the generated fields and methods of the entities.
+var reservedFields = map[string]bool{"Id": true, "Entity": true}
+
+// constView is a generated constant.
+type constView struct {
+	Name    string
+	Ident   string
+	Comment string
+}
+
+// enumsView is a block of enum constants of the same namespace.
+type enumsView struct {
+	Namespace string
+	Comment   string
+	Consts    []constView
+}
+
+// fieldView is a field of an entity, holding the values of an attribute.
+type fieldView struct {
+	Name  string
+	Type  string
+	Tag   string
+	Const string
+	Many  bool
+	IsSet string
+	Value string
+}
+
+// entityView is the struct of the attributes of a namespace.
+type entityView struct {
+	Name      string
+	Namespace string
+	Fields    []fieldView
+}
+
+// fileView is the generated file.
+type fileView struct {
+	Package    string
+	Imports    []string
+	Attributes []constView
+	Enums      []enumsView
+	Entities   []entityView
+}
+
+// generator builds the view of the file, checking that the names it makes are all different.
+type generator struct {
+	names   map[string]string
+	imports map[string]bool
+}
+
+// generate writes the source of the package of the schema. The attributes and enums are sorted by ident so that the
+// same schema always generates the same source.
+func generate(pkg string, model schemaModel) (src []byte, err error) {
+
+	gen := &generator{
+		names:   map[string]string{},
+		imports: map[string]bool{},
+	}
+
+	view := fileView{
+		Package: pkg,
+	}
+
+	if !isIdentifier(pkg) {
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Invalid package name: %#v", pkg)
+	}
+
+	if err == nil {
+		view.Attributes, err = gen.attributes(model.attributes)
+	}
+
+	if err == nil {
+		view.Enums, err = gen.enums(model.enums, model.attributes)
+	}
+
+	if err == nil {
+		view.Entities, err = gen.entities(model.attributes)
+	}
+
+	var buffer bytes.Buffer
+	if err == nil {
+		view.Imports = gen.importPaths()
+
+		if err = fileTemplate.Execute(&buffer, view); err == nil {
+			src, err = format.Source(buffer.Bytes())
+		}
+	}
+
+	return src, err
+}
+
+// attributes makes the constants of the attributes.
+func (gen *generator) attributes(defs []eva.AttributeDef) (consts []constView, err error) {
+	for _, def := range defs {
+		var name string
+		if name, err = gen.declare(def.Ident, identName(def.Ident)); err != nil {
+			break
+		}
+
+		comment := fmt.Sprintf("%s is %s, %s %s.", name, def.Ident, cardinalityName(def.Cardinality), def.ValueType)
+		if doc := strings.Join(strings.Fields(def.Doc), " "); doc != "" {
+			comment += " " + doc
+			if !strings.ContainsAny(doc[len(doc)-1:], ".!?") {
+				comment += "."
+			}
+		}
+
+		consts = append(consts, constView{
+			Name:    name,
+			Ident:   def.Ident,
+			Comment: comment,
+		})
+	}
+	return consts, err
+}
+
+// enums makes the constants of the enums, a block per namespace. The enums of the `:book.genre` namespace are those of
+// the `:book/genre` reference attribute.
+func (gen *generator) enums(idents []string, defs []eva.AttributeDef) (blocks []enumsView, err error) {
+
+	refs := map[string]bool{}
+	for _, def := range defs {
+		if def.ValueType == eva.Ref {
+			refs[def.Ident] = true
+		}
+	}
+
+	for _, ident := range idents {
+		var name string
+		if name, err = gen.declare(ident, identName(ident)); err != nil {
+			break
+		}
+
+		namespace := namespaceOf(ident)
+		if len(blocks) == 0 || blocks[len(blocks)-1].Namespace != namespace {
+			comment := fmt.Sprintf("Idents of the :%s namespace.", namespace)
+			if dot := strings.LastIndex(namespace, "."); dot >= 0 {
+				if attr := ":" + namespace[:dot] + "/" + namespace[dot+1:]; refs[attr] {
+					comment = fmt.Sprintf("Enums of %s.", attr)
+				}
+			}
+			blocks = append(blocks, enumsView{
+				Namespace: namespace,
+				Comment:   comment,
+			})
+		}
+
+		block := &blocks[len(blocks)-1]
+		block.Consts = append(block.Consts, constView{
+			Name:  name,
+			Ident: ident,
+		})
+	}
+
+	return blocks, err
+}
+
+// entities makes a struct per namespace of the attributes, with a field per attribute.
+func (gen *generator) entities(defs []eva.AttributeDef) (entities []entityView, err error) {
+
+	for _, def := range defs {
+		namespace := namespaceOf(def.Ident)
+		if len(entities) == 0 || entities[len(entities)-1].Namespace != namespace {
+			var name string
+			if name, err = gen.declare(":"+namespace, goName(namespace)); err != nil {
+				break
+			}
+			gen.imports[evaImport] = true
+			entities = append(entities, entityView{
+				Name:      name,
+				Namespace: namespace,
+			})
+		}
+
+		entity := &entities[len(entities)-1]
+		field := fieldView{
+			Name:  goName(nameOf(def.Ident)),
+			Tag:   def.Ident[1:],
+			Const: identName(def.Ident),
+			Many:  def.Cardinality == eva.Many,
+		}
+
+		for _, other := range entity.Fields {
+			if field.Name == other.Name {
+				err = edn.MakeErrorWithFormat(ErrNameCollision, ":%s and %s both make the field %s.%s", other.Tag, def.Ident, entity.Name, field.Name)
+			}
+		}
+		if err == nil && (reservedFields[field.Name] || field.Name == "") {
+			err = edn.MakeErrorWithFormat(ErrNameCollision, "%s makes the field %s.%#v", def.Ident, entity.Name, field.Name)
+		}
+		if err != nil {
+			break
+		}
+
+		typ, has := goTypes[def.ValueType]
+		if !has {
+			typ = elementType
+		}
+		if typ.importPath != "" {
+			gen.imports[typ.importPath] = true
+		}
+
+		field.Type, field.IsSet, field.Value = typ.name, fmt.Sprintf(typ.isSet, "entity."+field.Name), "entity."+field.Name
+		if field.Many {
+			field.Type = "[]" + typ.name
+			if def.ValueType == eva.Ref {
+				field.Type = "[]eva.EntityRef"
+			}
+			field.IsSet, field.Value = fmt.Sprintf("len(entity.%s) > 0", field.Name), "value"
+		}
+		if def.ValueType == eva.Keyword {
+			field.Value = "eva.Ident(" + field.Value + ")"
+		}
+
+		entity.Fields = append(entity.Fields, field)
+	}
+
+	return entities, err
+}
+
+// importPaths returns the paths of the imports, the standard packages first and a blank line before the others.
+func (gen *generator) importPaths() (paths []string) {
+	var others []string
+	for path := range gen.imports {
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			others = append(others, path)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	sort.Strings(others)
+
+	if len(paths) > 0 && len(others) > 0 {
+		paths = append(paths, "")
+	}
+	return append(paths, others...)
+}
+
+// declare records the name generated for the ident, which no other ident may have.
+func (gen *generator) declare(ident string, name string) (string, error) {
+	var err error
+	if other, has := gen.names[name]; has {
+		err = edn.MakeErrorWithFormat(ErrNameCollision, "%s and %s both make %s", other, ident, name)
+	} else if name == "" {
+		err = edn.MakeErrorWithFormat(ErrNameCollision, "%s makes no name", ident)
+	}
+	gen.names[name] = ident
+	return name, err
+}
+
+// cardinalityName is the name of the cardinality in the comments.
+func cardinalityName(cardinality eva.Cardinality) string {
+	if cardinality == eva.Many {
+		return "many"
+	}
+	return "one"
+}
+
+// namespaceOf returns the namespace of the ident, `book.genre` for `:book.genre/fiction`.
+func namespaceOf(ident string) string {
+	if slash := strings.Index(ident, "/"); slash > 0 {
+		return strings.TrimPrefix(ident[:slash], ":")
+	}
+	return ""
+}
+
+// nameOf returns the name of the ident without its namespace, `fiction` for `:book.genre/fiction`.
+func nameOf(ident string) string {
+	return ident[strings.Index(ident, "/")+1:]
+}
+
+// identName returns the Go name of the constant of the ident, `BookGenreFiction` for `:book.genre/fiction`.
+func identName(ident string) string {
+	return goName(namespaceOf(ident) + "/" + nameOf(ident))
+}
+
+// goName makes an exported Go name of the words of the name, the runes other than letters and digits separating the
+// words, `YearPublished` for `year_published`.
+func goName(name string) string {
+	var builder strings.Builder
+	capitalize := true
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if builder.Len() == 0 && unicode.IsDigit(r) {
+				builder.WriteRune('N')
+			}
+			if capitalize {
+				r = unicode.ToUpper(r)
+			}
+			builder.WriteRune(r)
+			capitalize = false
+		} else {
+			capitalize = true
+		}
+	}
+	return builder.String()
+}
+
+// isIdentifier checks if the name is a Go identifier.
+func isIdentifier(name string) bool {
+	is := name != ""
+	for i, r := range name {
+		is = is && (unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r)))
+	}
+	return is
+}
+
+// fileTemplate writes the file, which is formatted afterwards.
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by evagen. DO NOT EDIT.
+
+package {{ .Package }}
+{{ if .Imports }}
+import (
+{{- range .Imports }}
+	{{ if . }}"{{ . }}"{{ end }}
+{{- end }}
+)
+{{ end }}
+{{- if .Attributes }}
+// Attributes.
+const (
+{{- range .Attributes }}
+	// {{ .Comment }}
+	{{ .Name }} = "{{ .Ident }}"
+{{ end -}}
+)
+{{ end }}
+{{- range .Enums }}
+// {{ .Comment }}
+const (
+{{- range .Consts }}
+	{{ .Name }} = "{{ .Ident }}"
+{{- end }}
+)
+{{ end }}
+{{- range .Entities }}
+// {{ .Name }} is an entity with the :{{ .Namespace }}/* attributes, pulled with eva.PatternOf and transacted with Entity.
+type {{ .Name }} struct {
+	Id int64 ` + "`" + `edn:"db/id"` + "`" + `
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `edn:"{{ .Tag }}"` + "`" + `
+{{- end }}
+}
+
+// Entity returns the entity map of the attributes that are set, for eva.Tx.Entity, under the id, which is a TempID
+// for a new entity. The zero values are left out.
+func (entity *{{ .Name }}) Entity(id interface{}) map[string]interface{} {
+	attributes := map[string]interface{}{
+		eva.EntityIdAttribute: id,
+	}
+{{- range .Fields }}
+	if {{ .IsSet }} {
+	{{- if .Many }}
+		values := make([]interface{}, 0, len(entity.{{ .Name }}))
+		for _, value := range entity.{{ .Name }} {
+			values = append(values, {{ .Value }})
+		}
+		attributes[{{ .Const }}] = values
+	{{- else }}
+		attributes[{{ .Const }}] = {{ .Value }}
+	{{- end }}
+	}
+{{- end }}
+	return attributes
+}
+{{ end -}}
+`))
@@ -0,0 +1,163 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("generate test", func() {
+
+	var model schemaModel
+
+	BeforeEach(func() {
+		var err error
+		model, err = readSchema(strings.NewReader(bookSchema))
+		Ω(err).Should(BeNil())
+	})
+
+	generated := func(model schemaModel) string {
+		src, err := generate("books", model)
+		Ω(err).Should(BeNil())
+		return string(src)
+	}
+
+	attribute := func(ident string, valueType eva.ValueType, cardinality eva.Cardinality) eva.AttributeDef {
+		return eva.AttributeDef{Ident: ident, ValueType: valueType, Cardinality: cardinality}
+	}
+
+	Context("generating the package", func() {
+		It("should write formatted go", func() {
+			src := generated(model)
+			Ω(src).Should(HavePrefix("// Code generated by evagen. DO NOT EDIT.\n\npackage books\n"))
+
+			_, err := parser.ParseFile(token.NewFileSet(), "schema.go", src, parser.AllErrors)
+			Ω(err).Should(BeNil())
+		})
+
+		It("should write the constants of the attributes", func() {
+			src := generated(model)
+			Ω(src).Should(ContainSubstring("// BookTitle is :book/title, one :db.type/string. Title of a book.\n\tBookTitle = \":book/title\""))
+			Ω(src).Should(ContainSubstring("// BookTags is :book/tags, many :db.type/keyword.\n\tBookTags = \":book/tags\""))
+			Ω(src).Should(ContainSubstring(`BookYearPublished = ":book/year_published"`))
+			Ω(src).Should(ContainSubstring(`PersonName = ":person/name"`))
+		})
+
+		It("should write the constants of the enums", func() {
+			src := generated(model)
+			Ω(src).Should(ContainSubstring("// Enums of :book/genre.\nconst ("))
+			Ω(src).Should(MatchRegexp(`BookGenreFiction\s+= ":book.genre/fiction"`))
+			Ω(src).Should(MatchRegexp(`BookGenreHistory\s+= ":book.genre/history"`))
+
+			model.enums = append(model.enums, ":status/active")
+			Ω(generated(model)).Should(ContainSubstring("// Idents of the :status namespace.\nconst (\n\tStatusActive = \":status/active\"\n)"))
+		})
+
+		It("should write a struct per namespace", func() {
+			src := generated(model)
+			Ω(src).Should(ContainSubstring("type Book struct {"))
+			Ω(src).Should(MatchRegexp("Id\\s+int64\\s+`edn:\"db/id\"`"))
+			Ω(src).Should(MatchRegexp("Genre\\s+\\*eva.EntityRef\\s+`edn:\"book/genre\"`"))
+			Ω(src).Should(MatchRegexp("Tags\\s+\\[\\]string\\s+`edn:\"book/tags\"`"))
+			Ω(src).Should(MatchRegexp("Title\\s+string\\s+`edn:\"book/title\"`"))
+			Ω(src).Should(MatchRegexp("YearPublished\\s+int64\\s+`edn:\"book/year_published\"`"))
+			Ω(src).Should(ContainSubstring("type Person struct {"))
+		})
+
+		It("should write the entity maps of the structs", func() {
+			src := generated(model)
+			Ω(src).Should(ContainSubstring("func (entity *Book) Entity(id interface{}) map[string]interface{} {"))
+			Ω(src).Should(ContainSubstring("if entity.Title != \"\" {\n\t\tattributes[BookTitle] = entity.Title\n\t}"))
+			Ω(src).Should(ContainSubstring("if entity.Genre != nil {\n\t\tattributes[BookGenre] = entity.Genre\n\t}"))
+			Ω(src).Should(ContainSubstring("values = append(values, eva.Ident(value))"))
+		})
+
+		It("should hold the values in the go types", func() {
+			model.attributes = append(model.attributes,
+				attribute(":book/published", eva.Instant, eva.One),
+				attribute(":book/isbn", eva.UUID, eva.One),
+				attribute(":book/price", eva.BigDec, eva.One),
+				attribute(":book/authors", eva.Ref, eva.Many),
+				attribute(":book/cover", eva.Bytes, eva.One))
+			model = newSchemaModel(model.attributes, model.enums)
+
+			src := generated(model)
+			Ω(src).Should(ContainSubstring("import (\n\t\"math/big\"\n\t\"time\"\n\n\t\"github.com/Workiva/eva-client-go/edn\""))
+			Ω(src).Should(MatchRegexp(`Published\s+time.Time`))
+			Ω(src).Should(MatchRegexp(`Isbn\s+uuid.UUID`))
+			Ω(src).Should(MatchRegexp(`Price\s+\*big.Float`))
+			Ω(src).Should(MatchRegexp(`Authors\s+\[\]eva.EntityRef`))
+			Ω(src).Should(MatchRegexp(`Cover\s+edn.Element`))
+			Ω(src).Should(ContainSubstring("if entity.Isbn != (uuid.UUID{}) {"))
+			Ω(src).Should(ContainSubstring("if !entity.Published.IsZero() {"))
+		})
+
+		It("should write the same source for the same schema", func() {
+			expected := generated(model)
+
+			reversed := schemaModel{enums: model.enums}
+			for i := len(model.attributes) - 1; i >= 0; i-- {
+				reversed.attributes = append(reversed.attributes, model.attributes[i])
+			}
+			Ω(generated(newSchemaModel(reversed.attributes, reversed.enums))).Should(BeEquivalentTo(expected))
+			Ω(generated(model)).Should(BeEquivalentTo(expected))
+		})
+
+		It("should write only the package of an empty schema", func() {
+			Ω(generated(schemaModel{})).Should(BeEquivalentTo("// Code generated by evagen. DO NOT EDIT.\n\npackage books\n"))
+		})
+	})
+
+	Context("with names that collide", func() {
+		It("should reject the idents that make the same name", func() {
+			for _, defs := range [][]eva.AttributeDef{
+				{attribute(":book/year-published", eva.Long, eva.One), attribute(":book/year_published", eva.Long, eva.One)},
+				{attribute(":book/genre", eva.Ref, eva.One), attribute(":book.genre/label", eva.String, eva.One)},
+				{attribute(":book/id", eva.String, eva.One)},
+				{attribute(":book/entity", eva.Ref, eva.One)},
+				{attribute(":title", eva.String, eva.One)},
+			} {
+				_, err := generate("books", newSchemaModel(defs, nil))
+				Ω(err).Should(test.HaveMessage(ErrNameCollision))
+			}
+		})
+
+		It("should reject the invalid package names", func() {
+			for _, pkg := range []string{"", "my-books", "1books"} {
+				_, err := generate(pkg, model)
+				Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+			}
+		})
+	})
+
+	Context("naming", func() {
+		It("should make exported go names", func() {
+			Ω(goName("year_published")).Should(BeEquivalentTo("YearPublished"))
+			Ω(goName("book.genre")).Should(BeEquivalentTo("BookGenre"))
+			Ω(goName("isComponent")).Should(BeEquivalentTo("IsComponent"))
+			Ω(goName("active?")).Should(BeEquivalentTo("Active"))
+			Ω(goName("3d-model")).Should(BeEquivalentTo("N3dModel"))
+			Ω(identName(":book.genre/fiction")).Should(BeEquivalentTo("BookGenreFiction"))
+		})
+	})
+})
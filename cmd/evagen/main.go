@@ -0,0 +1,128 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command evagen generates a Go package of the idents of an eva schema: a constant per attribute and per enum, and a
+// struct per namespace of attributes to pull and transact the entities with. The schema is read from the `.edn` file
+// of the transaction installing it, or from the latest snapshot of a database:
+//
+//	evagen -schema schema.edn -package books -out books/schema.go
+//	evagen -config eva.json -tenant my-tenant -label my-database -package books -out books/schema.go
+//
+// The same schema always generates the same source, so that the generated files only change along with the schema.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	_ "github.com/Workiva/eva-client-go/eva/http"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil && err != flag.ErrHelp {
+		fmt.Fprintln(os.Stderr, "evagen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the package the arguments ask for, and writes it to the output file or else to stdout.
+func run(args []string, stdout io.Writer) (err error) {
+
+	flags := flag.NewFlagSet("evagen", flag.ContinueOnError)
+	schemaFile := flags.String("schema", "", "the .edn file of the transaction installing the schema")
+	configFile := flags.String("config", "", "the eva configuration to read the schema of the latest snapshot with")
+	tenant := flags.String("tenant", "", "the tenant of the database, with -config")
+	label := flags.String("label", "", "the label of the database, with -config")
+	timeout := flags.Duration("timeout", 30*time.Second, "the time to read the schema of the snapshot in, with -config")
+	pkg := flags.String("package", "schema", "the name of the generated package")
+	out := flags.String("out", "", "the file to write, stdout when empty")
+
+	if err = flags.Parse(args); err == nil && (*schemaFile == "") == (*configFile == "") {
+		err = edn.MakeError(edn.ErrInvalidInput, "Expected either -schema or -config")
+	}
+
+	var model schemaModel
+	if err == nil {
+		if *schemaFile != "" {
+			model, err = readSchemaFile(*schemaFile)
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+			model, err = readSnapshotSchema(ctx, *configFile, *tenant, *label)
+			cancel()
+		}
+	}
+
+	var src []byte
+	if err == nil {
+		src, err = generate(*pkg, model)
+	}
+
+	if err == nil {
+		if *out != "" {
+			err = ioutil.WriteFile(*out, src, 0644)
+		} else {
+			_, err = stdout.Write(src)
+		}
+	}
+
+	return err
+}
+
+// readSchemaFile reads the schema of the transaction in the file.
+func readSchemaFile(path string) (model schemaModel, err error) {
+	var file *os.File
+	if file, err = os.Open(path); err == nil {
+		defer file.Close()
+		model, err = readSchema(file)
+	}
+	return model, err
+}
+
+// readSnapshotSchema reads the schema of the latest snapshot of the database, which the configuration connects to.
+func readSnapshotSchema(ctx context.Context, configFile string, tenantName string, label string) (model schemaModel, err error) {
+
+	if tenantName == "" || label == "" {
+		err = edn.MakeError(edn.ErrInvalidInput, "Expected the -tenant and the -label of the database")
+	}
+
+	var data []byte
+	var config eva.Configuration
+	var tenant eva.Tenant
+	var source eva.Source
+	var snap eva.SnapshotChannel
+	if err == nil {
+		if data, err = ioutil.ReadFile(configFile); err == nil {
+			if config, err = eva.NewConfiguration(string(data)); err == nil {
+				if tenant, err = eva.NewTenant(tenantName); err == nil {
+					source, err = eva.NewSource(config, tenant)
+				}
+			}
+		}
+	}
+
+	if err == nil {
+		if snap, err = source.LatestSnapshot(label); err == nil {
+			model, err = snapshotSchema(ctx, snap)
+		}
+	}
+
+	return model, err
+}
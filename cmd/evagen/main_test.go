@@ -0,0 +1,74 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("main test", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "evagen")
+		Ω(err).Should(BeNil())
+		Ω(ioutil.WriteFile(filepath.Join(dir, "schema.edn"), []byte(bookSchema), 0644)).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		Ω(os.RemoveAll(dir)).Should(BeNil())
+	})
+
+	Context("running the command", func() {
+		It("should write the package of the schema file", func() {
+			out := filepath.Join(dir, "schema.go")
+			Ω(run([]string{"-schema", filepath.Join(dir, "schema.edn"), "-package", "books", "-out", out}, nil)).Should(BeNil())
+
+			model, err := readSchemaFile(filepath.Join(dir, "schema.edn"))
+			Ω(err).Should(BeNil())
+			expected, err := generate("books", model)
+			Ω(err).Should(BeNil())
+			Ω(ioutil.ReadFile(out)).Should(BeEquivalentTo(expected))
+		})
+
+		It("should write to stdout without an output file", func() {
+			var stdout bytes.Buffer
+			Ω(run([]string{"-schema", filepath.Join(dir, "schema.edn")}, &stdout)).Should(BeNil())
+			Ω(stdout.String()).Should(HavePrefix("// Code generated by evagen. DO NOT EDIT.\n\npackage schema\n"))
+		})
+
+		It("should read the schema from a file or a snapshot", func() {
+			for _, args := range [][]string{
+				{},
+				{"-schema", filepath.Join(dir, "schema.edn"), "-config", "eva.json"},
+				{"-config", "eva.json", "-label", "books"},
+			} {
+				Ω(run(args, nil)).Should(test.HaveMessage(edn.ErrInvalidInput))
+			}
+
+			Ω(run([]string{"-schema", filepath.Join(dir, "missing.edn")}, nil)).ShouldNot(BeNil())
+		})
+	})
+})
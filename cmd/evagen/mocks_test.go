@@ -0,0 +1,109 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+// bookSchema is the schema transaction of the books.
+const bookSchema = `[{:db/id #db/id [:db.part/db] :db/ident :book/title :db/valueType :db.type/string
+                      :db/cardinality :db.cardinality/one :db/doc "Title of a book" :db.install/_attribute :db.part/db}
+                     {:db/id #db/id [:db.part/db] :db/ident :book/year_published :db/valueType :db.type/long
+                      :db.install/_attribute :db.part/db}
+                     {:db/id #db/id [:db.part/db] :db/ident :book/genre :db/valueType :db.type/ref
+                      :db/cardinality :db.cardinality/one :db.install/_attribute :db.part/db}
+                     {:db/id #db/id [:db.part/db] :db/ident :book/tags :db/valueType :db.type/keyword
+                      :db/cardinality :db.cardinality/many :db.install/_attribute :db.part/db}
+                     {:db/id #db/id [:db.part/db] :db/ident :person/name :db/valueType :db.type/string
+                      :db/cardinality :db.cardinality/one :db/unique :db.unique/identity :db.install/_attribute :db.part/db}
+                     {:db/id #db/id [:db.part/user] :db/ident :book.genre/history}
+                     [:db/add #db/id [:db.part/user] :db/ident :book.genre/fiction]
+                     [:db/add 42 :book/title "First Book"]]`
+
+// installedBooks is what the pull of the attributes installed finds, along with a system attribute.
+const installedBooks = `[{:db/id 10 :db/ident :book/title :db/valueType {:db/ident :db.type/string}
+                          :db/cardinality {:db/ident :db.cardinality/one} :db/doc "Title of a book"}
+                         {:db/id 11 :db/ident :book/year_published :db/valueType {:db/ident :db.type/long}
+                          :db/cardinality {:db/ident :db.cardinality/one}}
+                         {:db/id 12 :db/ident :book/genre :db/valueType {:db/ident :db.type/ref}
+                          :db/cardinality {:db/ident :db.cardinality/one}}
+                         {:db/id 13 :db/ident :book/tags :db/valueType {:db/ident :db.type/keyword}
+                          :db/cardinality {:db/ident :db.cardinality/many}}
+                         {:db/id 14 :db/ident :person/name :db/valueType {:db/ident :db.type/string}
+                          :db/cardinality {:db/ident :db.cardinality/one} :db/unique {:db/ident :db.unique/identity}}
+                         {:db/id 3 :db/ident :db/doc :db/valueType {:db/ident :db.type/string}
+                          :db/cardinality {:db/ident :db.cardinality/one}}]`
+
+type fakeResult struct {
+	*eva.BaseResult
+}
+
+// Error from the call.
+func (result *fakeResult) Error() (error, bool) {
+	return nil, false
+}
+
+// fakeSource answers the queries of the schema of the books.
+type fakeSource struct {
+	*eva.BaseSource
+	queries []string
+}
+
+func newFakeSource() (source *fakeSource) {
+	source = &fakeSource{}
+	config, err := eva.NewConfiguration(`{"category": "books"}`)
+	if err == nil {
+		source.BaseSource, err = eva.NewBaseSource(config, nil, source, source.connection, source.query)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return source
+}
+
+// connection makes the connection to the database.
+func (source *fakeSource) connection(label edn.Serializable, src eva.Source) (eva.ConnectionChannel, error) {
+	return eva.NewBaseConnectionChannel(
+		label,
+		src,
+		func(context.Context, edn.Serializable) (eva.Result, error) {
+			return nil, edn.MakeError(edn.ErrInvalidInput, "read only")
+		},
+		func(asOf edn.Serializable, options ...eva.SnapshotOption) (eva.SnapshotChannel, error) {
+			return eva.NewBaseSnapshotChannel(label, src, nil, nil, asOf, options...)
+		})
+}
+
+// query finds the attributes installed, or the idents of the books.
+func (source *fakeSource) query(_ context.Context, query interface{}, _ ...interface{}) (eva.Result, error) {
+	str := fmt.Sprint(query)
+	source.queries = append(source.queries, str)
+
+	payload := `[:db/doc :book/title :book/year_published :book/genre :book/tags :person/name :book.genre/fiction
+	             :book.genre/history :db.part/db]`
+	if strings.Contains(str, eva.InstallAttribute) {
+		payload = installedBooks
+	}
+
+	return &fakeResult{
+		BaseResult: eva.NewBaseResult([]byte(payload)),
+	}, nil
+}
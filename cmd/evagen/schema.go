@@ -0,0 +1,207 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+func init() {
+	eva.PanicOnError(func() (err error) {
+		schemaParser, err = edn.NewParser(edn.WithLimits(edn.DefaultLimits))
+		return err
+	})
+}
+
+// schemaParser reads the schema files, the limits guard against broken or hostile files.
+var schemaParser edn.Parser
+
+// schemaModel is what the code is generated from, the attributes and the enum idents of a schema sorted by ident.
+type schemaModel struct {
+	attributes []eva.AttributeDef
+	enums      []string
+}
+
+// newSchemaModel makes the model of the attributes and idents, leaving out the system ones, the idents which are also
+// attributes and the duplicates.
+func newSchemaModel(defs []eva.AttributeDef, idents []string) (model schemaModel) {
+
+	attributes := map[string]bool{}
+	for _, def := range eva.NewInstalledSchema(defs...).Attributes() {
+		if !isSystemIdent(def.Ident) {
+			model.attributes = append(model.attributes, def)
+		}
+		attributes[def.Ident] = true
+	}
+
+	seen := map[string]bool{}
+	for _, ident := range idents {
+		if !attributes[ident] && !seen[ident] && !isSystemIdent(ident) {
+			model.enums = append(model.enums, ident)
+		}
+		seen[ident] = true
+	}
+	sort.Strings(model.enums)
+
+	return model
+}
+
+// isSystemIdent checks if the ident belongs to eva, such as `:db/ident` or `:db.type/string`.
+func isSystemIdent(ident string) bool {
+	return strings.HasPrefix(ident, ":db/") || strings.HasPrefix(ident, ":db.") || strings.HasPrefix(ident, ":fressian/")
+}
+
+// schemaEntity is an entity map of a schema transaction, an attribute when it has a type and an enum otherwise.
+type schemaEntity struct {
+	Ident       string `edn:"db/ident"`
+	ValueType   string `edn:"db/valueType"`
+	Cardinality string `edn:"db/cardinality"`
+	Doc         string `edn:"db/doc"`
+	Unique      string `edn:"db/unique"`
+	Index       bool   `edn:"db/index"`
+	Fulltext    bool   `edn:"db/fulltext"`
+	IsComponent bool   `edn:"db/isComponent"`
+	NoHistory   bool   `edn:"db/noHistory"`
+}
+
+// def returns the definition of the attribute the entity installs, once checked.
+func (entity schemaEntity) def() (eva.AttributeDef, error) {
+	attr := eva.Attribute(entity.Ident).
+		Type(eva.ValueType(entity.ValueType)).
+		Doc(entity.Doc).
+		Unique(eva.Uniqueness(entity.Unique))
+
+	if entity.Cardinality != "" {
+		attr.Cardinality(eva.Cardinality(entity.Cardinality))
+	}
+	if entity.Index {
+		attr.Index()
+	}
+	if entity.Fulltext {
+		attr.Fulltext()
+	}
+	if entity.IsComponent {
+		attr.IsComponent()
+	}
+	if entity.NoHistory {
+		attr.NoHistory()
+	}
+
+	return attr.Def()
+}
+
+// readSchema reads the schema a transaction installs, such as a migration file. The attributes are the entity maps
+// with a `:db/valueType`, and the enums the other entity maps with a `:db/ident` and the `[:db/add e :db/ident ident]`
+// operations. The other operations are ignored.
+func readSchema(reader io.Reader) (model schemaModel, err error) {
+
+	var data []byte
+	var elem edn.Element
+	if data, err = ioutil.ReadAll(reader); err == nil {
+		if elem, err = schemaParser.Parse(string(data)); err == nil {
+			if elemType := elem.ElementType(); elemType != edn.VectorType && elemType != edn.ListType {
+				err = edn.MakeErrorWithFormat(eva.ErrInvalidSchema, "Expected a vector of transaction data, got: %s", elemType)
+			}
+		}
+	}
+
+	var defs []eva.AttributeDef
+	var idents []string
+	if err == nil {
+		err = elem.(edn.CollectionElement).IterateChildren(func(_ edn.Element, item edn.Element) (e error) {
+			switch item.ElementType() {
+			case edn.MapType:
+				var entity schemaEntity
+				var def eva.AttributeDef
+				if e = edn.Decode(item, &entity); e != nil {
+					e = edn.MakeErrorWithFormat(eva.ErrInvalidSchema, "Expected an attribute or an enum, got: %s", item.String())
+				} else {
+					switch {
+					case entity.Ident == "":
+					case entity.ValueType == "":
+						idents = append(idents, entity.Ident)
+					default:
+						if def, e = entity.def(); e == nil {
+							defs = append(defs, def)
+						}
+					}
+				}
+
+			case edn.VectorType, edn.ListType:
+				if ident, is := addedIdent(item); is {
+					idents = append(idents, ident)
+				}
+			}
+			return e
+		})
+	}
+
+	if err == nil {
+		model = newSchemaModel(defs, idents)
+	}
+
+	return model, err
+}
+
+// addedIdent returns the ident of a `[:db/add e :db/ident ident]` operation, other than the rename of an ident.
+func addedIdent(op edn.Element) (ident string, is bool) {
+
+	var parts []edn.Element
+	_ = op.(edn.CollectionElement).IterateChildren(func(_ edn.Element, part edn.Element) error {
+		parts = append(parts, part)
+		return nil
+	})
+
+	if len(parts) == 4 && parts[1].ElementType() != edn.KeywordType && parts[3].ElementType() == edn.KeywordType {
+		is = parts[0].String() == ":db/add" && parts[2].String() == eva.IdentAttribute
+		ident = parts[3].String()
+	}
+
+	return ident, is
+}
+
+// snapshotSchema reads the schema installed in the snapshot, the enums being the idents of the entities that are not
+// attributes.
+func snapshotSchema(ctx context.Context, snap eva.SnapshotChannel) (model schemaModel, err error) {
+
+	query := eva.Find("?ident").Collection().
+		Where(eva.Clause("?e", eva.IdentAttribute, "?ident"))
+
+	var schema *eva.InstalledSchema
+	var result eva.QueryResult
+	var values []edn.Element
+	if schema, err = snap.SchemaContext(ctx); err == nil {
+		if result, err = snap.QueryContext(ctx, query); err == nil {
+			values, err = result.Collection()
+		}
+	}
+
+	if err == nil {
+		idents := make([]string, 0, len(values))
+		for _, value := range values {
+			idents = append(idents, value.String())
+		}
+		model = newSchemaModel(schema.Attributes(), idents)
+	}
+
+	return model, err
+}
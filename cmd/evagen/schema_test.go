@@ -0,0 +1,117 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("schema test", func() {
+
+	idents := func(model schemaModel) (idents []string) {
+		for _, def := range model.attributes {
+			idents = append(idents, def.Ident)
+		}
+		return idents
+	}
+
+	Context("reading a schema file", func() {
+		It("should read the attributes and the enums", func() {
+			model, err := readSchema(strings.NewReader(bookSchema))
+			Ω(err).Should(BeNil())
+			Ω(idents(model)).Should(BeEquivalentTo([]string{
+				":book/genre", ":book/tags", ":book/title", ":book/year_published", ":person/name"}))
+			Ω(model.enums).Should(BeEquivalentTo([]string{":book.genre/fiction", ":book.genre/history"}))
+
+			title, _ := eva.NewInstalledSchema(model.attributes...).Attribute(":book/title")
+			Ω(title).Should(BeEquivalentTo(eva.AttributeDef{
+				Ident:       ":book/title",
+				ValueType:   eva.String,
+				Cardinality: eva.One,
+				Doc:         "Title of a book",
+			}))
+
+			name, _ := eva.NewInstalledSchema(model.attributes...).Attribute(":person/name")
+			Ω(name.Unique).Should(BeEquivalentTo(eva.UniqueIdentity))
+		})
+
+		It("should default to a cardinality of one", func() {
+			model, err := readSchema(strings.NewReader(bookSchema))
+			Ω(err).Should(BeNil())
+
+			year, _ := eva.NewInstalledSchema(model.attributes...).Attribute(":book/year_published")
+			Ω(year.Cardinality).Should(BeEquivalentTo(eva.One))
+		})
+
+		It("should not take the renamed idents for enums", func() {
+			model, err := readSchema(strings.NewReader(`[[:db/add :book/title :db/ident :book/name]]`))
+			Ω(err).Should(BeNil())
+			Ω(model.enums).Should(BeEmpty())
+		})
+
+		It("should reject what is not a schema", func() {
+			for _, data := range []string{
+				`{:db/ident :book/title}`,
+				`[{:db/ident :book/title :db/valueType :db.type/text}]`,
+				`[{:db/ident :book/title :db/valueType {:db/ident :db.type/string}}]`,
+			} {
+				_, err := readSchema(strings.NewReader(data))
+				Ω(err).Should(test.HaveMessage(eva.ErrInvalidSchema))
+			}
+
+			_, err := readSchema(strings.NewReader(`[unbalanced`))
+			Ω(err).ShouldNot(BeNil())
+
+			_, err = readSchema(strings.NewReader(strings.Repeat("[", edn.DefaultLimits.MaxDepth+1)))
+			Ω(err).Should(test.HaveMessage(edn.ErrLimitExceeded))
+		})
+	})
+
+	Context("reading the schema of a snapshot", func() {
+		It("should read the attributes and the enums", func() {
+			source := newFakeSource()
+			snap, err := source.LatestSnapshot("books")
+			Ω(err).Should(BeNil())
+
+			model, err := snapshotSchema(context.Background(), snap)
+			Ω(err).Should(BeNil())
+			Ω(idents(model)).Should(BeEquivalentTo([]string{
+				":book/genre", ":book/tags", ":book/title", ":book/year_published", ":person/name"}))
+			Ω(model.enums).Should(BeEquivalentTo([]string{":book.genre/fiction", ":book.genre/history"}))
+			Ω(source.queries).Should(HaveLen(2))
+		})
+
+		It("should generate what the schema file generates", func() {
+			fromFile, err := readSchema(strings.NewReader(bookSchema))
+			Ω(err).Should(BeNil())
+
+			snap, err := newFakeSource().LatestSnapshot("books")
+			Ω(err).Should(BeNil())
+			fromSnapshot, err := snapshotSchema(context.Background(), snap)
+			Ω(err).Should(BeNil())
+
+			expected, err := generate("books", fromFile)
+			Ω(err).Should(BeNil())
+			Ω(generate("books", fromSnapshot)).Should(BeEquivalentTo(expected))
+		})
+	})
+})
@@ -0,0 +1,97 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+
+	// BigDecSuffix defines the suffix that marks an arbitrary precision decimal.
+	BigDecSuffix = "M"
+)
+
+// initBigDec will add the element factory to the collection of factories
+func initBigDec(_ Lexer) error {
+	return addElementTypeFactory(BigDecType, func(input interface{}) (elem Element, err error) {
+		if v, ok := input.(*big.Float); ok && v != nil {
+			elem = NewBigDecElement(v)
+		} else {
+			err = MakeError(ErrInvalidInput, input)
+		}
+		return elem, err
+	})
+}
+
+// parseExactFloat parses the floating point token keeping its precision. Tokens with the M suffix become big decimals,
+// everything else is a normal float.
+func parseExactFloat(tokenValue string) (elem Element, err error) {
+
+	if strings.HasSuffix(tokenValue, BigDecSuffix) {
+		tokenValue = strings.TrimSuffix(tokenValue, BigDecSuffix)
+
+		// keep enough mantissa bits to represent every digit written in the token.
+		prec := uint(math.Ceil(float64(len(tokenValue))*math.Log2(10))) + 64
+
+		var v *big.Float
+		var ok bool
+		if v, ok = new(big.Float).SetPrec(prec).SetString(tokenValue); ok {
+			elem = NewBigDecElement(v)
+		} else {
+			err = MakeErrorWithFormat(ErrParserError, "Invalid decimal: %s", tokenValue)
+		}
+	} else {
+		var v float64
+		if v, err = strconv.ParseFloat(tokenValue, 64); err == nil {
+			elem = NewFloatElement(v)
+		}
+	}
+
+	return elem, err
+}
+
+// NewBigDecElement creates a new arbitrary precision decimal element.
+func NewBigDecElement(value *big.Float) (elem Element) {
+
+	var err error
+	if elem, err = baseFactory().make(value, BigDecType, func(serializer Serializer, tag string, value interface{}) (out string, e error) {
+		switch serializer.MimeType() {
+		case EvaEdnMimeType:
+			if len(tag) > 0 {
+				out = TagPrefix + tag + " "
+			}
+			out += value.(*big.Float).Text('g', -1) + BigDecSuffix
+		default:
+			e = MakeError(ErrUnknownMimeType, serializer.MimeType())
+		}
+
+		return out, e
+	}); err != nil {
+		panic(err)
+	}
+
+	// big decimals are compared by value, not by their internal representation.
+	elem.(*baseElemImpl).equality = func(left, right Element) bool {
+		l, lok := left.Value().(*big.Float)
+		r, rok := right.Value().(*big.Float)
+		return lok && rok && l.Cmp(r) == 0
+	}
+
+	return elem
+}
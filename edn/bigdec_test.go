@@ -0,0 +1,94 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"math/big"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Big decimals in EDN", func() {
+	It("should initialize without issue", func() {
+		lexer, err := newLexer()
+		Ω(err).Should(BeNil())
+
+		delete(typeFactories, BigDecType)
+		err = initBigDec(lexer)
+		Ω(err).Should(BeNil())
+		_, has := typeFactories[BigDecType]
+		Ω(has).Should(BeTrue())
+
+		err = initBigDec(lexer)
+		Ω(err).ShouldNot(BeNil())
+		Ω(err).Should(test.HaveMessage(ErrInvalidFactory))
+	})
+
+	It("should create elements from the factory", func() {
+		v := big.NewFloat(1.5)
+
+		elem, err := typeFactories[BigDecType](v)
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigDecType))
+		Ω(elem.Value()).Should(BeIdenticalTo(v))
+		Ω(elem.String()).Should(BeEquivalentTo("1.5M"))
+	})
+
+	It("should not create elements from the factory if the input is not a the right type", func() {
+		elem, err := typeFactories[BigDecType](1.5)
+		Ω(err).ShouldNot(BeNil())
+		Ω(err).Should(test.HaveMessage(ErrInvalidInput))
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should stereotype big decimals", func() {
+		elem, err := NewPrimitiveElement(big.NewFloat(4.2))
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigDecType))
+	})
+
+	It("should parse exact floats", func() {
+		elem, err := parseExactFloat("4.2")
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(FloatType))
+
+		elem, err = parseExactFloat("3.14159265358979323846264338327950288M")
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigDecType))
+		Ω(elem.String()).Should(BeEquivalentTo("3.14159265358979323846264338327950288M"))
+
+		elem, err = parseExactFloat("1.5M")
+		Ω(err).Should(BeNil())
+		Ω(elem.Equals(NewBigDecElement(big.NewFloat(1.5)))).Should(BeTrue())
+
+		elem, err = parseExactFloat("fooM")
+		Ω(err).Should(test.HaveMessage(ErrParserError))
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should panic if the base factory errors.", func() {
+		origFac := baseFactory
+		baseFactory = func() elementFactory { return &breakerFactory{} }
+
+		wrapper := func() {
+			NewBigDecElement(big.NewFloat(1))
+		}
+
+		Ω(wrapper).Should(Panic())
+		baseFactory = origFac
+	})
+})
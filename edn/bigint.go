@@ -0,0 +1,88 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+
+	// BigIntSuffix defines the suffix that marks an arbitrary precision integer.
+	BigIntSuffix = "N"
+)
+
+// initBigInt will add the element factory to the collection of factories
+func initBigInt(_ Lexer) error {
+	return addElementTypeFactory(BigIntType, func(input interface{}) (elem Element, err error) {
+		if v, ok := input.(*big.Int); ok && v != nil {
+			elem = NewBigIntElement(v)
+		} else {
+			err = MakeError(ErrInvalidInput, input)
+		}
+		return elem, err
+	})
+}
+
+// parseExactInteger parses the integer token keeping its precision. Tokens with the N suffix or that do not fit in an
+// int64 become big integers, everything else is a normal integer.
+func parseExactInteger(tokenValue string) (elem Element, err error) {
+
+	bigInt := strings.HasSuffix(tokenValue, BigIntSuffix)
+	tokenValue = strings.TrimSuffix(tokenValue, BigIntSuffix)
+
+	v, ok := new(big.Int).SetString(tokenValue, 10)
+	switch {
+	case !ok:
+		err = MakeErrorWithFormat(ErrParserError, "Invalid integer: %s", tokenValue)
+	case bigInt || !v.IsInt64():
+		elem = NewBigIntElement(v)
+	default:
+		elem = NewIntegerElement(v.Int64())
+	}
+
+	return elem, err
+}
+
+// NewBigIntElement creates a new arbitrary precision integer element.
+func NewBigIntElement(value *big.Int) (elem Element) {
+
+	var err error
+	if elem, err = baseFactory().make(value, BigIntType, func(serializer Serializer, tag string, value interface{}) (out string, e error) {
+		switch serializer.MimeType() {
+		case EvaEdnMimeType:
+			if len(tag) > 0 {
+				out = TagPrefix + tag + " "
+			}
+			out += value.(*big.Int).String() + BigIntSuffix
+		default:
+			e = MakeError(ErrUnknownMimeType, serializer.MimeType())
+		}
+
+		return out, e
+	}); err != nil {
+		panic(err)
+	}
+
+	// big integers are compared by value, not by their internal representation.
+	elem.(*baseElemImpl).equality = func(left, right Element) bool {
+		l, lok := left.Value().(*big.Int)
+		r, rok := right.Value().(*big.Int)
+		return lok && rok && l.Cmp(r) == 0
+	}
+
+	return elem
+}
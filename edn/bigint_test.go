@@ -0,0 +1,102 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"math/big"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Big integers in EDN", func() {
+	It("should initialize without issue", func() {
+		lexer, err := newLexer()
+		Ω(err).Should(BeNil())
+
+		delete(typeFactories, BigIntType)
+		err = initBigInt(lexer)
+		Ω(err).Should(BeNil())
+		_, has := typeFactories[BigIntType]
+		Ω(has).Should(BeTrue())
+
+		err = initBigInt(lexer)
+		Ω(err).ShouldNot(BeNil())
+		Ω(err).Should(test.HaveMessage(ErrInvalidFactory))
+	})
+
+	It("should create elements from the factory", func() {
+		v := big.NewInt(123)
+
+		elem, err := typeFactories[BigIntType](v)
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigIntType))
+		Ω(elem.Value()).Should(BeIdenticalTo(v))
+		Ω(elem.String()).Should(BeEquivalentTo("123N"))
+	})
+
+	It("should not create elements from the factory if the input is not a the right type", func() {
+		elem, err := typeFactories[BigIntType]("foo")
+		Ω(err).ShouldNot(BeNil())
+		Ω(err).Should(test.HaveMessage(ErrInvalidInput))
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should stereotype big integers", func() {
+		elem, err := NewPrimitiveElement(big.NewInt(42))
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigIntType))
+	})
+
+	It("should compare by value", func() {
+		left := NewBigIntElement(big.NewInt(42))
+		right := NewBigIntElement(new(big.Int).Add(big.NewInt(40), big.NewInt(2)))
+		Ω(left.Equals(right)).Should(BeTrue())
+		Ω(left.Equals(NewBigIntElement(big.NewInt(41)))).Should(BeFalse())
+		Ω(left.Equals(NewIntegerElement(42))).Should(BeFalse())
+	})
+
+	It("should parse exact integers", func() {
+		elem, err := parseExactInteger("42")
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(IntegerType))
+
+		elem, err = parseExactInteger("42N")
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigIntType))
+
+		elem, err = parseExactInteger("-92233720368547758080")
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(BigIntType))
+		Ω(elem.String()).Should(BeEquivalentTo("-92233720368547758080N"))
+
+		elem, err = parseExactInteger("foo")
+		Ω(err).Should(test.HaveMessage(ErrParserError))
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should panic if the base factory errors.", func() {
+		origFac := baseFactory
+		baseFactory = func() elementFactory { return &breakerFactory{} }
+
+		wrapper := func() {
+			NewBigIntElement(big.NewInt(1))
+		}
+
+		Ω(wrapper).Should(Panic())
+		baseFactory = origFac
+	})
+})
@@ -51,7 +51,7 @@ func initCharacter(lexer Lexer) (err error) {
 			})
 		}
 
-		lexer.AddPattern(CharacterPrimitive, "\\\\u[0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f]", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(CharacterPrimitive, "\\\\u[0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f]", isUnicodeCharacterToken, func(tag string, tokenValue string) (el Element, e error) {
 			tokenValue = strings.TrimPrefix(tokenValue, CharacterPrefix+"u")
 			var v int64
 
@@ -64,7 +64,7 @@ func initCharacter(lexer Lexer) (err error) {
 			return el, e
 		})
 
-		lexer.AddPattern(CharacterPrimitive, "\\\\\\w", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(CharacterPrimitive, "\\\\\\w", isWordCharacterToken, func(tag string, tokenValue string) (el Element, e error) {
 
 			tokenValue = strings.TrimPrefix(tokenValue, CharacterPrefix)
 			runes := []rune(tokenValue)
@@ -80,6 +80,16 @@ func initCharacter(lexer Lexer) (err error) {
 	return err
 }
 
+// isUnicodeCharacterToken is the hand-written matcher of the unicode character pattern.
+func isUnicodeCharacterToken(token string) bool {
+	return strings.HasPrefix(token, CharacterPrefix+"u") && isHexQuad(token[2:])
+}
+
+// isWordCharacterToken is the hand-written matcher of the word character pattern.
+func isWordCharacterToken(token string) bool {
+	return len(token) == 2 && strings.HasPrefix(token, CharacterPrefix) && isWordByte(token[1])
+}
+
 // NewCharacterElement creates a new character element or an error.
 func NewCharacterElement(value rune) (elem Element) {
 
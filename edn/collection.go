@@ -23,6 +23,9 @@ const (
 
 	// ErrNoValue is returned when no value is found in the collection.
 	ErrNoValue = ErrorMessage("No value found")
+
+	// ErrImmutableCollection is returned when a persistent collection is modified.
+	ErrImmutableCollection = ErrorMessage("Collection is immutable")
 )
 
 // ChildIterator is the iterator for children elements, if this is a list based item, the key will be an index of the
@@ -71,6 +74,15 @@ type collectionElemImpl struct {
 
 	// collection of elements
 	collection interface{}
+
+	// order holds the keys of a hashed collection in insertion order, it is only kept for ordered collections.
+	order []string
+
+	// ordered marks that the hashed collection iterates in insertion order.
+	ordered bool
+
+	// immutable marks a persistent collection that can no longer be modified.
+	immutable bool
 }
 
 // Len return the quantity of items in this collection.
@@ -95,10 +107,19 @@ func (elem *collectionElemImpl) IterateChildren(iterator ChildIterator) (err err
 			}
 		}
 	case map[string][2]Element:
-		for _, c := range v {
-			err = iterator(c[0], c[1])
-			if err != nil {
-				break
+		if elem.ordered {
+			for _, k := range elem.order {
+				c := v[k]
+				if err = iterator(c[0], c[1]); err != nil {
+					break
+				}
+			}
+		} else {
+			for _, c := range v {
+				err = iterator(c[0], c[1])
+				if err != nil {
+					break
+				}
 			}
 		}
 	}
@@ -195,12 +216,29 @@ func (elem *collectionElemImpl) Equals(e Element) (result bool) {
 	return result
 }
 
-func (elem *collectionElemImpl) add(addFunc func([]Element, []Element) []Element, children []Element) (err error) {
+// collectionKey returns the key used to hash the child within a hashed collection.
+func collectionKey(serializer Serializer, child Element) (k string, err error) {
+	if str, is := child.(*baseElemImpl); is && str.elemType == StringType {
+		k = str.value.(string)
+	} else {
+		k, err = child.Serialize(serializer)
+	}
+
+	return k, err
+}
+
+func (elem *collectionElemImpl) add(prepend bool, children []Element) (err error) {
 
-	if len(children) != 0 {
+	if elem.immutable {
+		err = MakeError(ErrImmutableCollection, elem.ElementType())
+	} else if len(children) != 0 {
 		switch v := elem.collection.(type) {
 		case []Element:
-			elem.collection = addFunc(v, children)
+			if prepend {
+				elem.collection = append(children, v...)
+			} else {
+				elem.collection = append(v, children...)
+			}
 		case map[string][2]Element:
 
 			var serializer Serializer
@@ -213,17 +251,13 @@ func (elem *collectionElemImpl) add(addFunc func([]Element, []Element) []Element
 				if len(children)%setSize == 0 {
 					childOffset := setSize - 1
 
+					var keys []string
 					for i := 0; i < len(children); i += setSize {
 						var k string
-						if str, is := children[i].(*baseElemImpl); is && str.elemType == StringType {
-							k = str.value.(string)
-						} else {
-							k, err = children[i].Serialize(serializer)
-						}
-
-						if err == nil {
+						if k, err = collectionKey(serializer, children[i]); err == nil {
 							if _, has := v[k]; !has {
 								v[k] = [2]Element{children[i], children[i+childOffset]}
+								keys = append(keys, k)
 							} else {
 								err = MakeErrorWithFormat(ErrDuplicateKey, "Key: %s", k)
 							}
@@ -233,6 +267,14 @@ func (elem *collectionElemImpl) add(addFunc func([]Element, []Element) []Element
 							break
 						}
 					}
+
+					if elem.ordered {
+						if prepend {
+							elem.order = append(keys, elem.order...)
+						} else {
+							elem.order = append(elem.order, keys...)
+						}
+					}
 				} else {
 					err = MakeError(ErrInvalidInput, "must have an even number of inputs.")
 				}
@@ -248,16 +290,12 @@ func (elem *collectionElemImpl) add(addFunc func([]Element, []Element) []Element
 
 // Append will add the appropriate children. Note that a map must have 2 parameters.
 func (elem *collectionElemImpl) Append(children ...Element) error {
-	return elem.add(func(in []Element, c []Element) []Element {
-		return append(in, c...)
-	}, children)
+	return elem.add(false, children)
 }
 
 // Prepend will add the appropriate children. Note that a map must have 2 parameters.
 func (elem *collectionElemImpl) Prepend(children ...Element) error {
-	return elem.add(func(in []Element, c []Element) []Element {
-		return append(c, in...)
-	}, children)
+	return elem.add(true, children)
 }
 
 // Get the value from the collection.
@@ -323,3 +361,30 @@ func (elem *collectionElemImpl) Merge(child CollectionElement) (err error) {
 
 	return err
 }
+
+// SetTag sets the tag to the incoming value, persistent collections can not be re-tagged.
+func (elem *collectionElemImpl) SetTag(value string) (err error) {
+	if elem.immutable {
+		err = MakeError(ErrImmutableCollection, elem.ElementType())
+	} else {
+		err = elem.baseElemImpl.SetTag(value)
+	}
+
+	return err
+}
+
+// restyle switches the parsed collection to the style requested, the children are in the order of the source.
+func (elem *collectionElemImpl) restyle(style CollectionStyle, children []Element) (err error) {
+	switch style {
+	case OrderedCollections:
+		if hashed, is := elem.collection.(map[string][2]Element); is && !elem.ordered {
+			elem.ordered = true
+			elem.collection = make(map[string][2]Element, len(hashed))
+			err = elem.Append(children...)
+		}
+	case PersistentCollections:
+		elem.immutable = true
+	}
+
+	return err
+}
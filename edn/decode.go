@@ -0,0 +1,438 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattrobenolt/gocql/uuid"
+)
+
+const (
+
+	// ErrInvalidTarget defines the error for a decode target that can not be set.
+	ErrInvalidTarget = ErrorMessage("Invalid decode target")
+
+	// ErrTypeMismatch defines the error for an element that does not fit the decode target.
+	ErrTypeMismatch = ErrorMessage("Type mismatch")
+
+	// DecodeTag is the struct tag naming the map key a field is decoded from, i.e. `edn:"book/title"`.
+	DecodeTag = "edn"
+)
+
+// Unmarshaler is implemented by the types that decode themselves from an element.
+type Unmarshaler interface {
+
+	// UnmarshalEDN decodes the element into the receiver.
+	UnmarshalEDN(elem Element) error
+}
+
+var (
+	elementInterface     = reflect.TypeOf((*Element)(nil)).Elem()
+	unmarshalerInterface = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	timeType             = reflect.TypeOf(time.Time{})
+	uuidType             = reflect.TypeOf(uuid.UUID{})
+	bigIntType           = reflect.TypeOf(big.Int{})
+	bigFloatType         = reflect.TypeOf(big.Float{})
+)
+
+// structField is a field a map key can be decoded into.
+type structField struct {
+	index  int
+	name   string
+	tagged bool
+}
+
+// structFields caches the decodable fields per struct type.
+var structFields sync.Map
+
+// Decode the element into the value pointed to by v. Maps decode into maps and structs, lists, vectors and sets decode
+// into slices and arrays, and the primitives into the matching Go types. Struct fields are matched to the map keys by
+// their `edn` tag, which holds the key with or without the leading colon, or else by their name compared to the name of
+// the key without the namespace, ignoring case, dashes and underscores. Keys without a field are ignored. Fields of the
+// type edn.Element (or a narrower element interface) receive the element as is, and an empty interface receives plain
+// Go values.
+func Decode(elem Element, v interface{}) (err error) {
+	target := reflect.ValueOf(v)
+	if target.Kind() == reflect.Ptr && !target.IsNil() {
+		err = decodeValue(elem, target.Elem(), "$")
+	} else {
+		err = MakeErrorWithFormat(ErrInvalidTarget, "Expected a non nil pointer, got: %T", v)
+	}
+
+	return err
+}
+
+// Unmarshal parses the data and decodes it into the value pointed to by v.
+func Unmarshal(data string, v interface{}) (err error) {
+	var elem Element
+	if elem, err = Parse(data); err == nil {
+		err = Decode(elem, v)
+	}
+
+	return err
+}
+
+// mismatch creates the error for an element that does not fit the target.
+func mismatch(elem Element, target reflect.Value, path string) error {
+	return MakeErrorWithFormat(ErrTypeMismatch, "Can not decode %s into %s at %s", elem.ElementType(), target.Type(), path)
+}
+
+// decodeValue decodes the element into the target.
+func decodeValue(elem Element, target reflect.Value, path string) (err error) {
+
+	isNil := elem == nil || elem.ElementType() == NilType
+
+	switch {
+	case target.Kind() == reflect.Interface && target.NumMethod() > 0:
+		if isNil {
+			target.Set(reflect.Zero(target.Type()))
+		} else if reflect.TypeOf(elem).AssignableTo(target.Type()) {
+			target.Set(reflect.ValueOf(elem))
+		} else {
+			err = mismatch(elem, target, path)
+		}
+
+	case target.Kind() == reflect.Ptr:
+		if isNil {
+			target.Set(reflect.Zero(target.Type()))
+		} else {
+			if target.IsNil() {
+				target.Set(reflect.New(target.Type().Elem()))
+			}
+			err = decodeValue(elem, target.Elem(), path)
+		}
+
+	case target.CanAddr() && target.Addr().Type().Implements(unmarshalerInterface):
+		err = target.Addr().Interface().(Unmarshaler).UnmarshalEDN(elem)
+
+	case isNil:
+		target.Set(reflect.Zero(target.Type()))
+
+	default:
+		err = decodeNonNil(elem, target, path)
+	}
+
+	return err
+}
+
+// decodeNonNil decodes the non nil element into a target that is not a pointer.
+func decodeNonNil(elem Element, target reflect.Value, path string) (err error) {
+
+	elemType := elem.ElementType()
+	matched := true
+
+	switch target.Type() {
+	case timeType:
+		if matched = elemType == InstantType; matched {
+			target.Set(reflect.ValueOf(elem.Value()))
+		}
+	case uuidType:
+		if matched = elemType == UUIDType; matched {
+			target.Set(reflect.ValueOf(elem.Value()))
+		}
+	case bigIntType:
+		switch elemType {
+		case BigIntType:
+			target.Set(reflect.ValueOf(elem.Value()).Elem())
+		case IntegerType:
+			target.Set(reflect.ValueOf(big.NewInt(elem.Value().(int64))).Elem())
+		default:
+			matched = false
+		}
+	case bigFloatType:
+		switch elemType {
+		case BigDecType:
+			target.Set(reflect.ValueOf(elem.Value()).Elem())
+		case FloatType:
+			target.Set(reflect.ValueOf(big.NewFloat(elem.Value().(float64))).Elem())
+		case IntegerType:
+			target.Set(reflect.ValueOf(new(big.Float).SetInt64(elem.Value().(int64))).Elem())
+		default:
+			matched = false
+		}
+	default:
+		matched, err = decodeKind(elem, target, path)
+	}
+
+	if err == nil && !matched {
+		err = mismatch(elem, target, path)
+	}
+
+	return err
+}
+
+// decodeKind decodes the element by the kind of the target.
+func decodeKind(elem Element, target reflect.Value, path string) (matched bool, err error) {
+
+	elemType := elem.ElementType()
+	matched = true
+
+	switch target.Kind() {
+	case reflect.Interface:
+		var value interface{}
+		if value, err = naturalValue(elem); err == nil {
+			target.Set(reflect.ValueOf(&value).Elem())
+		}
+
+	case reflect.Bool:
+		if matched = elemType == BooleanType; matched {
+			target.SetBool(elem.Value().(bool))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var value int64
+		switch elemType {
+		case IntegerType:
+			value = elem.Value().(int64)
+		case BigIntType:
+			matched = elem.Value().(*big.Int).IsInt64()
+			value = elem.Value().(*big.Int).Int64()
+		default:
+			matched = false
+		}
+
+		if matched {
+			if target.OverflowInt(value) {
+				err = MakeErrorWithFormat(ErrTypeMismatch, "%d overflows %s at %s", value, target.Type(), path)
+			} else {
+				target.SetInt(value)
+			}
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if matched = elemType == IntegerType; matched {
+			if value := elem.Value().(int64); value < 0 || target.OverflowUint(uint64(value)) {
+				err = MakeErrorWithFormat(ErrTypeMismatch, "%d overflows %s at %s", value, target.Type(), path)
+			} else {
+				target.SetUint(uint64(value))
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch elemType {
+		case FloatType:
+			target.SetFloat(elem.Value().(float64))
+		case IntegerType:
+			target.SetFloat(float64(elem.Value().(int64)))
+		case BigDecType:
+			value, _ := elem.Value().(*big.Float).Float64()
+			target.SetFloat(value)
+		default:
+			matched = false
+		}
+
+	case reflect.String:
+		switch elemType {
+		case StringType:
+			target.SetString(elem.Value().(string))
+		case CharacterType:
+			target.SetString(string(elem.Value().(rune)))
+		case KeywordType, SymbolType:
+			target.SetString(elem.String())
+		default:
+			matched = false
+		}
+
+	case reflect.Slice:
+		var children []Element
+		if children, matched = sequence(elem); matched {
+			slice := reflect.MakeSlice(target.Type(), len(children), len(children))
+			for i, child := range children {
+				if err = decodeValue(child, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					break
+				}
+			}
+
+			if err == nil {
+				target.Set(slice)
+			}
+		}
+
+	case reflect.Array:
+		var children []Element
+		if children, matched = sequence(elem); matched {
+			if len(children) <= target.Len() {
+				for i := 0; i < target.Len() && err == nil; i++ {
+					if i < len(children) {
+						err = decodeValue(children[i], target.Index(i), fmt.Sprintf("%s[%d]", path, i))
+					} else {
+						target.Index(i).Set(reflect.Zero(target.Type().Elem()))
+					}
+				}
+			} else {
+				err = MakeErrorWithFormat(ErrTypeMismatch, "%d elements do not fit in %s at %s", len(children), target.Type(), path)
+			}
+		}
+
+	case reflect.Map:
+		if matched = elemType == MapType; matched {
+			mapValue := reflect.MakeMapWithSize(target.Type(), elem.(CollectionElement).Len())
+			err = elem.(CollectionElement).IterateChildren(func(key Element, value Element) (e error) {
+				k := reflect.New(target.Type().Key()).Elem()
+				v := reflect.New(target.Type().Elem()).Elem()
+				if e = decodeValue(key, k, fmt.Sprintf("%s[%s]", path, key.String())); e == nil {
+					if e = decodeValue(value, v, fmt.Sprintf("%s[%s]", path, key.String())); e == nil {
+						mapValue.SetMapIndex(k, v)
+					}
+				}
+				return e
+			})
+
+			if err == nil {
+				target.Set(mapValue)
+			}
+		}
+
+	case reflect.Struct:
+		if matched = elemType == MapType; matched {
+			fields := fieldsOf(target.Type())
+			err = elem.(CollectionElement).IterateChildren(func(key Element, value Element) (e error) {
+				if field, has := matchField(fields, key); has {
+					e = decodeValue(value, target.Field(field.index), path+"."+target.Type().Field(field.index).Name)
+				}
+				return e
+			})
+		}
+
+	default:
+		matched = false
+	}
+
+	return matched, err
+}
+
+// sequence returns the children of a list, vector or set.
+func sequence(elem Element) (children []Element, is bool) {
+	switch elem.ElementType() {
+	case ListType, VectorType, SetType:
+		is = true
+		children = make([]Element, 0, elem.(CollectionElement).Len())
+		_ = elem.(CollectionElement).IterateChildren(func(_ Element, child Element) error {
+			children = append(children, child)
+			return nil
+		})
+	}
+
+	return children, is
+}
+
+// naturalValue returns the plain Go value of the element, keywords and symbols become their string and collections
+// become []interface{} and map[interface{}]interface{}.
+func naturalValue(elem Element) (value interface{}, err error) {
+
+	switch elem.ElementType() {
+	case NilType:
+	case KeywordType, SymbolType:
+		value = elem.String()
+	case ListType, VectorType, SetType:
+		children, _ := sequence(elem)
+		values := make([]interface{}, len(children))
+		for i, child := range children {
+			if values[i], err = naturalValue(child); err != nil {
+				break
+			}
+		}
+		value = values
+	case MapType:
+		values := make(map[interface{}]interface{}, elem.(CollectionElement).Len())
+		err = elem.(CollectionElement).IterateChildren(func(key Element, child Element) (e error) {
+			var k, v interface{}
+			if k, e = naturalValue(key); e == nil {
+				if k != nil && !reflect.TypeOf(k).Comparable() {
+					k = key.String()
+				}
+				if v, e = naturalValue(child); e == nil {
+					values[k] = v
+				}
+			}
+			return e
+		})
+		value = values
+	default:
+		value = elem.Value()
+	}
+
+	return value, err
+}
+
+// fieldsOf returns the decodable fields of the struct type.
+func fieldsOf(structType reflect.Type) (fields []structField) {
+
+	if cached, has := structFields.Load(structType); has {
+		fields = cached.([]structField)
+	} else {
+		for i := 0; i < structType.NumField(); i++ {
+			field := structType.Field(i)
+			if field.PkgPath == "" && !field.Anonymous {
+				switch tag := strings.Split(field.Tag.Get(DecodeTag), ",")[0]; tag {
+				case "-":
+				case "":
+					fields = append(fields, structField{index: i, name: foldName(field.Name)})
+				default:
+					fields = append(fields, structField{index: i, name: strings.TrimPrefix(tag, KeywordPrefix), tagged: true})
+				}
+			}
+		}
+
+		structFields.Store(structType, fields)
+	}
+
+	return fields
+}
+
+// matchField finds the field for the map key, tagged fields win over the ones matched by name.
+func matchField(fields []structField, key Element) (field structField, has bool) {
+
+	var full, name string
+	switch key.ElementType() {
+	case KeywordType, SymbolType:
+		full = strings.TrimPrefix(key.String(), KeywordPrefix)
+		name = foldName(key.(SymbolElement).Name())
+	case StringType:
+		full = key.Value().(string)
+		name = foldName(full)
+	}
+
+	if len(full) > 0 {
+		for _, f := range fields {
+			if f.tagged && f.name == full {
+				field, has = f, true
+				break
+			}
+		}
+
+		if !has {
+			for _, f := range fields {
+				if !f.tagged && f.name == name {
+					field, has = f, true
+					break
+				}
+			}
+		}
+	}
+
+	return field, has
+}
+
+// foldName removes the case, dashes and underscores from a name.
+func foldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
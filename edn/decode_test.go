@@ -0,0 +1,237 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Workiva/eva-client-go/test"
+	"github.com/mattrobenolt/gocql/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type decodeAuthor struct {
+	Name string `edn:"author/name"`
+}
+
+type decodeBook struct {
+	Title       string `edn:":book/title"`
+	ReleaseYear int
+	Price       float64
+	Tags        []string
+	Author      *decodeAuthor `edn:"book/author"`
+	Published   time.Time
+	Raw         Element `edn:"book/raw"`
+	Ignored     string  `edn:"-"`
+	hidden      string
+}
+
+type decodeUpper string
+
+// UnmarshalEDN decodes the string in upper case.
+func (upper *decodeUpper) UnmarshalEDN(elem Element) (err error) {
+	if elem.ElementType() == StringType {
+		*upper = decodeUpper("<" + elem.Value().(string) + ">")
+	} else {
+		err = MakeError(ErrTypeMismatch, elem)
+	}
+	return err
+}
+
+var _ = Describe("Decode", func() {
+
+	decode := func(data string, v interface{}) error {
+		elem, err := Parse(data)
+		Ω(err).Should(BeNil())
+		return Decode(elem, v)
+	}
+
+	It("should decode the primitives", func() {
+		var i int
+		Ω(decode("42", &i)).Should(BeNil())
+		Ω(i).Should(BeEquivalentTo(42))
+
+		var u uint16
+		Ω(decode("42", &u)).Should(BeNil())
+		Ω(u).Should(BeEquivalentTo(42))
+
+		var f float32
+		Ω(decode("1.5", &f)).Should(BeNil())
+		Ω(f).Should(BeEquivalentTo(1.5))
+		Ω(decode("2", &f)).Should(BeNil())
+		Ω(f).Should(BeEquivalentTo(2))
+
+		var b bool
+		Ω(decode("true", &b)).Should(BeNil())
+		Ω(b).Should(BeTrue())
+
+		var s string
+		Ω(decode(`"text"`, &s)).Should(BeNil())
+		Ω(s).Should(BeEquivalentTo("text"))
+		Ω(decode(":db/ident", &s)).Should(BeNil())
+		Ω(s).Should(BeEquivalentTo(":db/ident"))
+		Ω(decode(`\c`, &s)).Should(BeNil())
+		Ω(s).Should(BeEquivalentTo("c"))
+
+		var t time.Time
+		Ω(decode(`#inst "2019-01-02T03:04:05.000-00:00"`, &t)).Should(BeNil())
+		Ω(t.Year()).Should(BeEquivalentTo(2019))
+
+		var id uuid.UUID
+		Ω(decode(`#uuid "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"`, &id)).Should(BeNil())
+		Ω(id.String()).Should(BeEquivalentTo("f81d4fae-7dec-11d0-a765-00a0c91e6bf6"))
+
+		var bi big.Int
+		Ω(decode("1234N", &bi)).Should(BeNil())
+		Ω(bi.String()).Should(BeEquivalentTo("1234"))
+
+		var bf big.Float
+		Ω(decode("1.5M", &bf)).Should(BeNil())
+		Ω(bf.String()).Should(BeEquivalentTo("1.5"))
+	})
+
+	It("should decode nil into the zero value", func() {
+		i := 5
+		Ω(decode("nil", &i)).Should(BeNil())
+		Ω(i).Should(BeZero())
+
+		p := &i
+		Ω(decode("nil", &p)).Should(BeNil())
+		Ω(p).Should(BeNil())
+	})
+
+	It("should decode the collections", func() {
+		var ints []int64
+		Ω(decode("[1 2 3]", &ints)).Should(BeNil())
+		Ω(ints).Should(BeEquivalentTo([]int64{1, 2, 3}))
+
+		Ω(decode("(4 5)", &ints)).Should(BeNil())
+		Ω(ints).Should(BeEquivalentTo([]int64{4, 5}))
+
+		var set []string
+		Ω(decode(`#{"a"}`, &set)).Should(BeNil())
+		Ω(set).Should(BeEquivalentTo([]string{"a"}))
+
+		var pair [2]int
+		Ω(decode("[7]", &pair)).Should(BeNil())
+		Ω(pair).Should(BeEquivalentTo([2]int{7, 0}))
+
+		var byName map[string]int
+		Ω(decode(`{:a 1 "b" 2}`, &byName)).Should(BeNil())
+		Ω(byName).Should(BeEquivalentTo(map[string]int{":a": 1, "b": 2}))
+
+		var nested [][]int
+		Ω(decode("[[1] [2 3]]", &nested)).Should(BeNil())
+		Ω(nested).Should(BeEquivalentTo([][]int{{1}, {2, 3}}))
+	})
+
+	It("should decode maps into structs", func() {
+		var book decodeBook
+		err := decode(`{:book/title "First Book"
+		                :book/release-year 1999
+		                :book/price 10
+		                :book/tags ["a" "b"]
+		                :book/author {:author/name "James Madison"}
+		                :book/published #inst "2019-01-02T03:04:05.000-00:00"
+		                :book/raw [1 2]
+		                :book/ignored "no"
+		                :book/unknown "skip"}`, &book)
+		Ω(err).Should(BeNil())
+		Ω(book.Title).Should(BeEquivalentTo("First Book"))
+		Ω(book.ReleaseYear).Should(BeEquivalentTo(1999))
+		Ω(book.Price).Should(BeEquivalentTo(10))
+		Ω(book.Tags).Should(BeEquivalentTo([]string{"a", "b"}))
+		Ω(book.Author).ShouldNot(BeNil())
+		Ω(book.Author.Name).Should(BeEquivalentTo("James Madison"))
+		Ω(book.Published.Year()).Should(BeEquivalentTo(2019))
+		Ω(book.Raw.ElementType()).Should(BeEquivalentTo(VectorType))
+		Ω(book.Ignored).Should(BeEmpty())
+		Ω(book.hidden).Should(BeEmpty())
+
+		var books []decodeBook
+		Ω(decode(`[{:book/title "a"} {:book/title "b"}]`, &books)).Should(BeNil())
+		Ω(books).Should(HaveLen(2))
+		Ω(books[1].Title).Should(BeEquivalentTo("b"))
+	})
+
+	It("should decode into an empty interface", func() {
+		var v interface{}
+		Ω(decode(`{:a [1 "b" :c nil] [1 2] #{2.5}}`, &v)).Should(BeNil())
+		Ω(v).Should(BeEquivalentTo(map[interface{}]interface{}{
+			":a":    []interface{}{int64(1), "b", ":c", nil},
+			"[1 2]": []interface{}{2.5},
+		}))
+	})
+
+	It("should use the unmarshaler", func() {
+		var upper decodeUpper
+		Ω(decode(`"a"`, &upper)).Should(BeNil())
+		Ω(upper).Should(BeEquivalentTo("<a>"))
+
+		var uppers []*decodeUpper
+		Ω(decode(`["a" "b"]`, &uppers)).Should(BeNil())
+		Ω(uppers).Should(HaveLen(2))
+		Ω(*uppers[1]).Should(BeEquivalentTo("<b>"))
+
+		Ω(decode("1", &upper)).Should(test.HaveMessage(ErrTypeMismatch))
+	})
+
+	It("should unmarshal the text", func() {
+		var ints []int
+		Ω(Unmarshal("[1 2]", &ints)).Should(BeNil())
+		Ω(ints).Should(BeEquivalentTo([]int{1, 2}))
+
+		Ω(Unmarshal("[1 2", &ints)).ShouldNot(BeNil())
+	})
+
+	Context("with the wrong target", func() {
+		It("should require a pointer", func() {
+			var i int
+			elem := NewIntegerElement(1)
+			Ω(Decode(elem, i)).Should(test.HaveMessage(ErrInvalidTarget))
+			Ω(Decode(elem, nil)).Should(test.HaveMessage(ErrInvalidTarget))
+			Ω(Decode(elem, (*int)(nil))).Should(test.HaveMessage(ErrInvalidTarget))
+		})
+
+		It("should report the mismatches with their path", func() {
+			var books []decodeBook
+			err := decode(`[{:book/title "a"} {:book/title 1}]`, &books)
+			Ω(err).Should(test.HaveMessage(ErrTypeMismatch))
+			Ω(err.Error()).Should(ContainSubstring("$[1].Title"))
+
+			var i int8
+			err = decode("300", &i)
+			Ω(err).Should(test.HaveMessage(ErrTypeMismatch))
+			Ω(err.Error()).Should(ContainSubstring("overflows"))
+
+			var u uint
+			Ω(decode("-1", &u)).Should(test.HaveMessage(ErrTypeMismatch))
+
+			var pair [1]int
+			Ω(decode("[1 2]", &pair)).Should(test.HaveMessage(ErrTypeMismatch))
+
+			var s string
+			Ω(decode("[1]", &s)).Should(test.HaveMessage(ErrTypeMismatch))
+
+			var m map[string]int
+			Ω(decode("[1]", &m)).Should(test.HaveMessage(ErrTypeMismatch))
+
+			var sym SymbolElement
+			Ω(decode("1", &sym)).Should(test.HaveMessage(ErrTypeMismatch))
+		})
+	})
+})
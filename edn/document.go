@@ -0,0 +1,213 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import "strings"
+
+const (
+
+	// ErrInvalidEdit defines the error for edits that can not be made to a document.
+	ErrInvalidEdit = ErrorMessage("Invalid edit")
+)
+
+// Document is the lossless, concrete syntax tree of EDN text. It keeps the comments, whitespace, commas and the
+// spelling of every token, so an unmodified document prints back exactly as it was read.
+type Document struct {
+	parser   *parserImpl
+	nodes    []*Node
+	trailing string
+}
+
+// Nodes returns the top level nodes of the document.
+func (doc *Document) Nodes() []*Node {
+	return doc.nodes
+}
+
+// Trailing returns the whitespace, commas and comments after the last node.
+func (doc *Document) Trailing() string {
+	return doc.trailing
+}
+
+// SetTrailing replaces the whitespace, commas and comments after the last node.
+func (doc *Document) SetTrailing(trailing string) (err error) {
+	if err = doc.parser.checkTrivia(trailing, false); err == nil {
+		doc.trailing = trailing
+	}
+
+	return err
+}
+
+// String prints the document.
+func (doc *Document) String() string {
+	var builder strings.Builder
+	for _, node := range doc.nodes {
+		node.write(&builder)
+	}
+	builder.WriteString(doc.trailing)
+
+	return builder.String()
+}
+
+// documentReader reads a document with the reader, keeping the text between and around the tokens.
+type documentReader struct {
+	*reader
+	parser *parserImpl
+}
+
+// newDocumentReader creates a reader for the document in the data.
+func newDocumentReader(parser *parserImpl, data string) *documentReader {
+	return &documentReader{
+		reader: newReader(parser.lexer, newParseSession(parser.config), data),
+		parser: parser,
+	}
+}
+
+// readDocument reads every node in the data.
+func (r *documentReader) readDocument() (doc *Document, err error) {
+
+	doc = &Document{
+		parser: r.parser,
+	}
+
+	for done := false; !done && err == nil; {
+		var node *Node
+		var leading string
+		var closing string
+		if node, leading, closing, err = r.readNode(); err == nil {
+			switch {
+			case node != nil:
+				doc.nodes = append(doc.nodes, node)
+			case len(closing) > 0:
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s'", closing)
+			default:
+				doc.trailing = leading
+				done = true
+			}
+		}
+	}
+
+	if err != nil {
+		doc = nil
+	}
+
+	return doc, err
+}
+
+// readNode reads the next node. When there is no node, the text before the closing literal, or the end of the data,
+// is returned instead.
+func (r *documentReader) readNode() (node *Node, leading string, closing string, err error) {
+
+	start := r.pos
+	r.skip()
+	leading = r.data[start:r.pos]
+
+	if r.pos < len(r.data) {
+		if closing = r.closing(); len(closing) > 0 {
+			r.pos += len(closing)
+		} else {
+			node = &Node{
+				parser:  r.parser,
+				leading: leading,
+			}
+
+			var tag string
+			tagStart := r.pos
+			if tag, err = r.readTag(); err == nil {
+				node.tag = r.data[tagStart:r.pos]
+
+				if def := r.collection(); def != nil {
+					err = r.readCollectionNode(node, def, tag)
+				} else {
+					tokenStart := r.pos
+					if node.elem, err = r.readPrimitive(tag); err == nil {
+						node.token = r.data[tokenStart:r.pos]
+					}
+				}
+			}
+
+			if err != nil {
+				node = nil
+			}
+		}
+	}
+
+	return node, leading, closing, err
+}
+
+// readCollectionNode reads the children of the collection node up to its end literal.
+func (r *documentReader) readCollectionNode(node *Node, def *collProcDef, tag string) (err error) {
+	r.pos += len(def.start)
+	node.def = def
+
+	// the depth is checked before descending so deeply nested input can not exhaust the stack.
+	if err = r.session.enter(); err == nil {
+		var children []Element
+		for done := false; !done && err == nil; {
+			var child *Node
+			var leading string
+			var closing string
+			if child, leading, closing, err = r.readNode(); err == nil {
+				if child != nil {
+					child.parent = node
+					node.children = append(node.children, child)
+					children = append(children, child.elem)
+					err = r.session.checkElements(len(children))
+				} else if done, err = collectionEnd(def, closing); done {
+					node.inner = leading
+				}
+			}
+		}
+
+		r.session.leave()
+
+		if err == nil {
+			node.elem, err = r.session.collection(def.processor, tag, children)
+		}
+	}
+
+	return err
+}
+
+// parseNode parses the source of a single node, the whitespace after the node is dropped.
+func (parser *parserImpl) parseNode(source string) (node *Node, err error) {
+
+	var doc *Document
+	if doc, err = parser.ParseDocument(source); err == nil {
+		switch {
+		case len(doc.nodes) != 1:
+			err = MakeErrorWithFormat(ErrInvalidEdit, "Expected one node, got: %d", len(doc.nodes))
+		case len(strings.TrimSpace(doc.trailing)) > 0:
+			err = MakeErrorWithFormat(ErrInvalidEdit, "Unexpected text after the node: '%s'", doc.trailing)
+		default:
+			node = doc.nodes[0]
+		}
+	}
+
+	return node, err
+}
+
+// checkTrivia checks that the text only holds whitespace, commas and comments. When the text comes before a node, its
+// last comment has to end with a new line or the node would be part of the comment.
+func (parser *parserImpl) checkTrivia(trivia string, beforeNode bool) (err error) {
+	r := newReader(parser.lexer, newParseSession(parser.config), trivia)
+	switch r.skip(); {
+	case r.pos < len(trivia):
+		err = MakeErrorWithFormat(ErrInvalidEdit, "Expected only whitespace, commas and comments, got: '%s'", trivia)
+	case beforeNode && strings.LastIndexByte(trivia, ';') > strings.LastIndexByte(trivia, '\n'):
+		err = MakeErrorWithFormat(ErrInvalidEdit, "The comment has to end with a new line: '%s'", trivia)
+	}
+
+	return err
+}
@@ -0,0 +1,128 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// schemaDocument is a hand-written schema, as kept in the schema files.
+const schemaDocument = `;; The book schema.
+[
+ ;; the title
+ {:db/id #db/id [:db.part/db]
+  :db/ident       :book/title,
+  :db/valueType   :db.type/string
+  :db/cardinality :db.cardinality/one
+  :db/doc         "The title of the book"
+  :db.install/_attribute :db.part/db}
+
+ {:db/id #db/id[:db.part/db] ; no space after the tag
+  :db/ident :book/pages :db/valueType :db.type/long
+  :db/cardinality :db.cardinality/one
+  :book/weight 1.50 :book/price 10.50M :book/count +7N}
+] ; end of the schema`
+
+var _ = Describe("Document", func() {
+
+	It("should print back the text as it was read", func() {
+		for _, data := range []string{
+			schemaDocument,
+			"",
+			"  ; only a comment",
+			"1 2 3",
+			"  [1,2 , 3]  ",
+			"#{ :a }",
+			"(;empty\n)",
+			`#inst   "2018-01-02T03:04:05Z"`,
+			"[1.0 1.50 -0 +1 1e5 1E+5 1.5M 10N]",
+			"{:a ;comment\n 1,,,}",
+		} {
+			doc, err := ParseDocument(data)
+			Ω(err).Should(BeNil(), data)
+			Ω(doc.String()).Should(BeEquivalentTo(data))
+		}
+	})
+
+	It("should map the nodes to the elements", func() {
+		doc, err := ParseDocument(schemaDocument)
+		Ω(err).Should(BeNil())
+		Ω(doc.Nodes()).Should(HaveLen(1))
+		Ω(doc.Trailing()).Should(BeEquivalentTo(" ; end of the schema"))
+
+		root := doc.Nodes()[0]
+		Ω(root.IsCollection()).Should(BeTrue())
+		Ω(root.Len()).Should(BeEquivalentTo(2))
+		Ω(root.Leading()).Should(BeEquivalentTo(";; The book schema.\n"))
+
+		expected, err := Parse(schemaDocument)
+		Ω(err).Should(BeNil())
+
+		elem, err := root.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.Equals(expected)).Should(BeTrue())
+
+		id, err := root.Children()[0].Get(mustKeyword("db/id"))
+		Ω(err).Should(BeNil())
+		Ω(id.Tag()).Should(BeEquivalentTo("db/id"))
+		Ω(id.Text()).Should(BeEquivalentTo("#db/id [:db.part/db]"))
+		Ω(id.Parent()).Should(BeIdenticalTo(root.Children()[0]))
+
+		weight, err := root.Children()[1].Get(mustKeyword("book/weight"))
+		Ω(err).Should(BeNil())
+		Ω(weight.Text()).Should(BeEquivalentTo("1.50"))
+		Ω(weight.IsCollection()).Should(BeFalse())
+
+		elem, err = weight.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.Value()).Should(BeEquivalentTo(1.5))
+	})
+
+	It("should report the errors in the text", func() {
+		for _, data := range []string{"[1 2", "]", `"unterminated`, "#my/tag", "{:a}"} {
+			doc, err := ParseDocument(data)
+			Ω(err).ShouldNot(BeNil(), data)
+			Ω(doc).Should(BeNil())
+		}
+	})
+
+	It("should apply the limits of the parser", func() {
+		parser, err := NewParser(WithLimits(Limits{MaxDepth: 2}))
+		Ω(err).Should(BeNil())
+
+		doc, err := parser.ParseDocument("[[[1]]]")
+		Ω(err).Should(test.HaveMessage(ErrLimitExceeded))
+		Ω(doc).Should(BeNil())
+	})
+
+	It("should replace the text after the last node", func() {
+		doc, err := ParseDocument("[1] ; old")
+		Ω(err).Should(BeNil())
+
+		Ω(doc.SetTrailing(" ; new")).Should(BeNil())
+		Ω(doc.String()).Should(BeEquivalentTo("[1] ; new"))
+
+		Ω(doc.SetTrailing(" 2")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(doc.String()).Should(BeEquivalentTo("[1] ; new"))
+	})
+})
+
+func mustKeyword(name string) SymbolElement {
+	keyword, err := NewKeywordElement(name)
+	Ω(err).Should(BeNil())
+	return keyword
+}
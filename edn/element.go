@@ -15,6 +15,7 @@
 package edn
 
 import (
+	"math/big"
 	"time"
 
 	"github.com/mattrobenolt/gocql/uuid"
@@ -87,6 +88,10 @@ func stereotypePrimitive(value interface{}) (_ interface{}, stereotype ElementTy
 		stereotype = InstantType
 	case uuid.UUID:
 		stereotype = UUIDType
+	case *big.Int:
+		stereotype = BigIntType
+	case *big.Float:
+		stereotype = BigDecType
 	default:
 		err = MakeErrorWithFormat(ErrUnknownMimeType, "[%T]: %#v", v, v)
 	}
@@ -29,7 +29,7 @@ var _ = Describe("Elements in EDN", func() {
 	Context("with the default marshaller", func() {
 		It("should create an base element with no error", func() {
 
-			t := ElementType(99)
+			t := ElementType(rune(99))
 
 			elem, err := baseFactory().make(nil, t, func(serializer Serializer, tag string, i interface{}) (string, error) {
 				return "", nil
@@ -41,7 +41,7 @@ var _ = Describe("Elements in EDN", func() {
 
 		It("should create an base element with no error", func() {
 
-			t := ElementType(99)
+			t := ElementType(rune(99))
 
 			elem, err := baseFactory().make(nil, t, nil)
 			Ω(err).ShouldNot(BeNil())
@@ -105,7 +105,7 @@ var _ = Describe("Elements in EDN", func() {
 
 		It("should create an base element with no error", func() {
 
-			t := ElementType(99)
+			t := ElementType(rune(99))
 
 			elem, err := baseFactory().make(nil, t, func(serializer Serializer, tag string, i interface{}) (string, error) {
 				return "", errors.New("expected")
@@ -114,7 +114,7 @@ var _ = Describe("Elements in EDN", func() {
 			Ω(err).Should(BeNil())
 			Ω(elem).ShouldNot(BeNil())
 
-			Ω(func() { elem.String() }).Should(Panic())
+			Ω(func() { _ = elem.String() }).Should(Panic())
 		})
 
 		It("should create an base element with no error", func() {
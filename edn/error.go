@@ -15,16 +15,19 @@
 package edn
 
 import (
+	"errors"
 	"fmt"
 )
 
-// ErrorMessage defines a message used in an error
+// ErrorMessage defines a message used in an error, it is the kind of the error and matches the errors of its kind with
+// errors.Is.
 type ErrorMessage string
 
 // Error is the error type.
 type Error struct {
 	message ErrorMessage
 	details string
+	cause   error
 }
 
 // Message will get the message part.
@@ -32,6 +35,11 @@ func (em ErrorMessage) Message() string {
 	return string(em)
 }
 
+// Error returns the message, so that the message can be the target of errors.Is.
+func (em ErrorMessage) Error() string {
+	return string(em)
+}
+
 // Message will get the message part.
 func (e *Error) Message() string {
 	return e.message.Message()
@@ -42,6 +50,24 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("[%s]: %s", e.message, e.details)
 }
 
+// Unwrap returns the error this error was made from, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches the errors of the same kind, the target is either the ErrorMessage or an *Error with the same message and,
+// when it has them, the same details.
+func (e *Error) Is(target error) (is bool) {
+	switch t := target.(type) {
+	case ErrorMessage:
+		is = t == e.message
+	case *Error:
+		is = t.message == e.message && (len(t.details) == 0 || t.details == e.details)
+	}
+
+	return is
+}
+
 // FormatError is an error that creates a unique message from the state at the time of creation.
 type FormatError struct {
 	message string
@@ -87,12 +113,17 @@ func (cumErr *CumulativeError) ErrorList() []error {
 	return cumErr.items
 }
 
+// Unwrap returns the errors, errors.Is and errors.As look through each of them.
+func (cumErr *CumulativeError) Unwrap() []error {
+	return cumErr.items
+}
+
 // MakeErrorWithFormat will create the error with a formatted string.
 func MakeErrorWithFormat(message ErrorMessage, format string, details ...interface{}) (err *Error) {
 	return MakeError(message, fmt.Sprintf(format, details...))
 }
 
-// MakeError will create the error
+// MakeError will create the error, details that are an error become the cause of the new error.
 func MakeError(message ErrorMessage, details interface{}) (err *Error) {
 
 	err = &Error{
@@ -101,17 +132,18 @@ func MakeError(message ErrorMessage, details interface{}) (err *Error) {
 
 	if details != nil {
 		err.details = fmt.Sprintf("%+v", details)
+		if cause, is := details.(error); is {
+			err.cause = cause
+		}
 	}
 
 	return err
 }
 
-// IsEquivalent checks if the errors are equivalent.
+// IsEquivalent checks if the error, or any error it wraps, is of this kind.
 func (em ErrorMessage) IsEquivalent(err error) (eq bool) {
 	if err != nil {
-		if myErr, is := err.(*Error); is {
-			eq = em == myErr.message
-		}
+		eq = errors.Is(err, em)
 	}
 
 	return eq
@@ -15,7 +15,9 @@
 package edn
 
 import (
+	"errors"
 	"fmt"
+	"io"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -135,4 +137,44 @@ var _ = Describe("The Error constructs", func() {
 			Expect(v1.ErrorList()[1]).To(BeIdenticalTo(err2))
 		})
 	})
+
+	Context("matching errors", func() {
+		It("should match the kind of the error", func() {
+			err := MakeError(ErrInvalidInput, "details")
+			Expect(errors.Is(err, ErrInvalidInput)).To(BeTrue())
+			Expect(errors.Is(err, ErrNoValue)).To(BeFalse())
+			Expect(errors.Is(err, MakeError(ErrInvalidInput, nil))).To(BeTrue())
+			Expect(errors.Is(err, MakeError(ErrInvalidInput, "details"))).To(BeTrue())
+			Expect(errors.Is(err, MakeError(ErrInvalidInput, "other"))).To(BeFalse())
+			Expect(ErrInvalidInput.Error()).To(BeEquivalentTo(ErrInvalidInput.Message()))
+		})
+
+		It("should keep the cause", func() {
+			cause := io.ErrUnexpectedEOF
+			err := MakeError(ErrInvalidInput, cause)
+			Expect(errors.Unwrap(err)).To(BeIdenticalTo(cause))
+			Expect(errors.Is(err, io.ErrUnexpectedEOF)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(cause.Error()))
+
+			wrapped := MakeError(ErrNoValue, err)
+			Expect(errors.Is(wrapped, ErrInvalidInput)).To(BeTrue())
+			Expect(errors.Is(wrapped, io.ErrUnexpectedEOF)).To(BeTrue())
+			Expect(ErrInvalidInput.IsEquivalent(wrapped)).To(BeTrue())
+
+			Expect(errors.Unwrap(MakeError(ErrInvalidInput, "no cause"))).To(BeNil())
+		})
+
+		It("should look through the cumulative errors", func() {
+			err := AppendError(MakeError(ErrNoValue, nil), &LimitError{Limit: MaxDepthLimit, Max: 1, Actual: 2})
+			Expect(err).To(BeAssignableToTypeOf(&CumulativeError{}))
+			Expect(errors.Is(err, ErrNoValue)).To(BeTrue())
+			Expect(errors.Is(err, ErrLimitExceeded)).To(BeTrue())
+			Expect(errors.Is(err, ErrInvalidInput)).To(BeFalse())
+
+			var limitErr *LimitError
+			Expect(errors.As(err, &limitErr)).To(BeTrue())
+			Expect(limitErr.Actual).To(BeEquivalentTo(2))
+			Expect(ErrLimitExceeded.IsEquivalent(err)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,173 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import "fmt"
+
+// EventType defines the kinds of events read from a stream.
+type EventType int
+
+const (
+	UnknownEvent EventType = iota
+	StartListEvent
+	EndListEvent
+	StartVectorEvent
+	EndVectorEvent
+	StartMapEvent
+	EndMapEvent
+	StartSetEvent
+	EndSetEvent
+
+	// KeyEvent comes before each key of a map, the events of the key follow it.
+	KeyEvent
+
+	// TagEvent comes before a tagged value, the events of the value follow it.
+	TagEvent
+
+	NilEvent
+	BooleanEvent
+	StringEvent
+	CharacterEvent
+	SymbolEvent
+	KeywordEvent
+	IntegerEvent
+	FloatEvent
+	BigIntEvent
+	BigDecEvent
+	InstantEvent
+	UUIDEvent
+
+	// ValueEvent is the event of the values with no event of their own, such as those made by tag handlers.
+	ValueEvent
+)
+
+var eventNames = map[EventType]string{
+	UnknownEvent:     "Unknown",
+	StartListEvent:   "StartList",
+	EndListEvent:     "EndList",
+	StartVectorEvent: "StartVector",
+	EndVectorEvent:   "EndVector",
+	StartMapEvent:    "StartMap",
+	EndMapEvent:      "EndMap",
+	StartSetEvent:    "StartSet",
+	EndSetEvent:      "EndSet",
+	KeyEvent:         "Key",
+	TagEvent:         "Tag",
+	NilEvent:         "Nil",
+	BooleanEvent:     "Boolean",
+	StringEvent:      "String",
+	CharacterEvent:   "Character",
+	SymbolEvent:      "Symbol",
+	KeywordEvent:     "Keyword",
+	IntegerEvent:     "Integer",
+	FloatEvent:       "Float",
+	BigIntEvent:      "BigInt",
+	BigDecEvent:      "BigDec",
+	InstantEvent:     "Instant",
+	UUIDEvent:        "UUID",
+	ValueEvent:       "Value",
+}
+
+// valueEvents maps the element types to the events of their values.
+var valueEvents = map[ElementType]EventType{
+	NilType:       NilEvent,
+	BooleanType:   BooleanEvent,
+	StringType:    StringEvent,
+	CharacterType: CharacterEvent,
+	SymbolType:    SymbolEvent,
+	KeywordType:   KeywordEvent,
+	IntegerType:   IntegerEvent,
+	FloatType:     FloatEvent,
+	BigIntType:    BigIntEvent,
+	BigDecType:    BigDecEvent,
+	InstantType:   InstantEvent,
+	UUIDType:      UUIDEvent,
+}
+
+// collectionEvents maps the start literals of the collections to their start and end events.
+var collectionEvents = map[string][2]EventType{
+	ListStartLiteral:   {StartListEvent, EndListEvent},
+	VectorStartLiteral: {StartVectorEvent, EndVectorEvent},
+	MapStartLiteral:    {StartMapEvent, EndMapEvent},
+	SetStartLiteral:    {StartSetEvent, EndSetEvent},
+}
+
+// String returns the name of the event type.
+func (t EventType) String() (name string) {
+	var has bool
+	if name, has = eventNames[t]; !has {
+		name = eventNames[UnknownEvent]
+	}
+	return name
+}
+
+// IsStart indicates that the event starts a collection.
+func (t EventType) IsStart() bool {
+	switch t {
+	case StartListEvent, StartVectorEvent, StartMapEvent, StartSetEvent:
+		return true
+	}
+	return false
+}
+
+// IsEnd indicates that the event ends a collection.
+func (t EventType) IsEnd() bool {
+	switch t {
+	case EndListEvent, EndVectorEvent, EndMapEvent, EndSetEvent:
+		return true
+	}
+	return false
+}
+
+// Position is a position in the input. The line and column start at 1, the column counts bytes.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// String returns the line and column of the position.
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Column)
+}
+
+// Event is read from a stream.
+type Event struct {
+
+	// Type of the event.
+	Type EventType
+
+	// Position of the token that made the event.
+	Position Position
+
+	// Tag of a tag event, without the prefix.
+	Tag string
+
+	// Element of the value events.
+	Element Element
+}
+
+// String returns a description of the event.
+func (event Event) String() (out string) {
+	switch {
+	case event.Type == TagEvent:
+		out = fmt.Sprintf("%s(%s)@%s", event.Type, event.Tag, event.Position)
+	case event.Element != nil:
+		out = fmt.Sprintf("%s(%s)@%s", event.Type, event.Element.String(), event.Position)
+	default:
+		out = fmt.Sprintf("%s@%s", event.Type, event.Position)
+	}
+	return out
+}
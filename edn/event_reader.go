@@ -0,0 +1,274 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"io"
+	"strings"
+)
+
+// EventReader reads the values in the input as a stream of events. Elements are only built when asked for, so the
+// parts of a large input that are not needed can be skipped cheaply. Tag handlers only apply to the primitives read
+// as events and to the values read with Element.
+type EventReader interface {
+
+	// Next returns the next event, io.EOF is returned once the input is exhausted.
+	Next() (Event, error)
+
+	// Element reads the next value into an element, as Parse would.
+	Element() (Element, error)
+
+	// Skip the next value without reading it into elements.
+	Skip() error
+
+	// SkipRest skips the rest of the collection last started, including its end.
+	SkipRest() error
+
+	// Depth returns the number of collections started and not yet ended.
+	Depth() int
+}
+
+// eventFrame holds the state of a collection started by an event.
+type eventFrame struct {
+	def *collProcDef
+
+	// count of the values read in the collection.
+	count int
+
+	// keyed indicates that the key event of the next value was sent.
+	keyed bool
+}
+
+// eventReader implements the EventReader interface.
+type eventReader struct {
+	*reader
+	frames []*eventFrame
+
+	// tag holds the tag of the last tag event until the value it tags is read.
+	tag    string
+	tagged bool
+
+	// the position of the mark, the line and column are counted up to it.
+	mark   int
+	line   int
+	column int
+}
+
+// newEventReader creates an event reader for the data.
+func newEventReader(parser *parserImpl, data string) *eventReader {
+	return &eventReader{
+		reader: newReader(parser.lexer, newParseSession(parser.config), data),
+		line:   1,
+		column: 1,
+	}
+}
+
+// Next returns the next event, io.EOF is returned once the input is exhausted.
+func (r *eventReader) Next() (event Event, err error) {
+
+	r.skip()
+	event.Position = r.position(r.pos)
+	top := r.top()
+
+	switch closing := r.closing(); {
+	case r.pos >= len(r.data) && top != nil:
+		err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input, expected: '%s'", top.def.end)
+	case r.pos >= len(r.data):
+		err = io.EOF
+	case r.tagged:
+		event, err = r.nextValue(event)
+	case len(closing) > 0 && top == nil:
+		err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s'", closing)
+	case len(closing) > 0:
+		if _, err = collectionEnd(top.def, closing); err == nil {
+			r.pos += len(closing)
+			event.Type = collectionEvents[top.def.start][1]
+			err = r.end()
+		}
+	case top != nil && top.def.start == MapStartLiteral && top.count%2 == 0 && !top.keyed:
+		top.keyed = true
+		event.Type = KeyEvent
+	default:
+		var tag string
+		if tag, err = r.readTag(); err == nil {
+			if len(tag) > 0 {
+				r.tag, r.tagged = tag, true
+				event.Type = TagEvent
+				event.Tag = tag
+			} else {
+				event, err = r.nextValue(event)
+			}
+		}
+	}
+
+	return event, err
+}
+
+// nextValue returns the event of the value at the current position.
+func (r *eventReader) nextValue(event Event) (Event, error) {
+
+	tag := r.untag()
+
+	var err error
+	if def := r.collection(); def != nil {
+		r.pos += len(def.start)
+		if err = r.session.enter(); err == nil {
+			r.frames = append(r.frames, &eventFrame{def: def})
+			event.Type = collectionEvents[def.start][0]
+			event.Tag = tag
+		}
+	} else if event.Element, err = r.readPrimitive(tag); err == nil {
+		var has bool
+		if event.Type, has = valueEvents[event.Element.ElementType()]; !has {
+			event.Type = ValueEvent
+		}
+		err = r.completed()
+	}
+
+	return event, err
+}
+
+// Element reads the next value into an element, as Parse would.
+func (r *eventReader) Element() (elem Element, err error) {
+
+	var tag string
+	if tag, err = r.startValue(); err == nil {
+		if elem, err = r.readValue(tag); err == nil {
+			err = r.completed()
+		}
+	}
+
+	if err != nil {
+		elem = nil
+	}
+
+	return elem, err
+}
+
+// Skip the next value without reading it into elements.
+func (r *eventReader) Skip() (err error) {
+	if _, err = r.startValue(); err == nil {
+		if err = r.skipValue(); err == nil {
+			err = r.completed()
+		}
+	}
+
+	return err
+}
+
+// SkipRest skips the rest of the collection last started, including its end.
+func (r *eventReader) SkipRest() (err error) {
+
+	top := r.top()
+	if top == nil {
+		err = MakeErrorWithFormat(ErrParserError, "No collection was started")
+	}
+
+	if err == nil && r.tagged {
+		r.untag()
+		err = r.skipValue()
+	}
+
+	for done := false; !done && err == nil; {
+		r.skip()
+		switch closing := r.closing(); {
+		case r.pos >= len(r.data):
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input, expected: '%s'", top.def.end)
+		case len(closing) > 0:
+			if done, err = collectionEnd(top.def, closing); err == nil {
+				r.pos += len(closing)
+				err = r.end()
+			}
+		default:
+			err = r.skipValue()
+		}
+	}
+
+	return err
+}
+
+// Depth returns the number of collections started and not yet ended.
+func (r *eventReader) Depth() int {
+	return len(r.frames)
+}
+
+// top returns the collection last started, or nil.
+func (r *eventReader) top() (frame *eventFrame) {
+	if len(r.frames) > 0 {
+		frame = r.frames[len(r.frames)-1]
+	}
+	return frame
+}
+
+// untag returns the tag of the last tag event and forgets it.
+func (r *eventReader) untag() (tag string) {
+	tag = r.tag
+	r.tag, r.tagged = "", false
+	return tag
+}
+
+// startValue moves to the start of the next value and returns its tag.
+func (r *eventReader) startValue() (tag string, err error) {
+	if r.tagged {
+		tag = r.untag()
+	} else {
+		switch r.skip(); {
+		case r.pos >= len(r.data):
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input, expected a value")
+		case len(r.closing()) > 0:
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s', expected a value", r.closing())
+		default:
+			tag, err = r.readTag()
+		}
+	}
+
+	return tag, err
+}
+
+// end the collection last started.
+func (r *eventReader) end() error {
+	r.frames = r.frames[:len(r.frames)-1]
+	r.session.leave()
+	return r.completed()
+}
+
+// completed counts the value just read in the collection holding it.
+func (r *eventReader) completed() (err error) {
+	if top := r.top(); top != nil {
+		top.count++
+		top.keyed = false
+		err = r.session.checkElements(top.count)
+	}
+
+	return err
+}
+
+// position returns the position of the offset, which can not be before the previous one.
+func (r *eventReader) position(offset int) Position {
+	chunk := r.data[r.mark:offset]
+	if lines := strings.Count(chunk, "\n"); lines > 0 {
+		r.line += lines
+		r.column = len(chunk) - strings.LastIndexByte(chunk, '\n')
+	} else {
+		r.column += len(chunk)
+	}
+	r.mark = offset
+
+	return Position{
+		Offset: offset,
+		Line:   r.line,
+		Column: r.column,
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// readEvents reads all the events, describing each.
+func readEvents(events EventReader) (described []string, err error) {
+	var event Event
+	for event, err = events.Next(); err == nil; event, err = events.Next() {
+		described = append(described, event.String())
+	}
+
+	if err == io.EOF {
+		err = nil
+	}
+
+	return described, err
+}
+
+var _ = Describe("Event reader", func() {
+
+	newEvents := func(data string) EventReader {
+		events, err := NewEventReader(data)
+		Ω(err).Should(BeNil())
+		return events
+	}
+
+	It("should read the events with their positions", func() {
+		described, err := readEvents(newEvents("{:a [1 \"two\"],\n :b #my/tag (nil), #{\\c}}"))
+		Ω(err).Should(BeNil())
+		Ω(described).Should(BeEquivalentTo([]string{
+			"StartMap@1:1",
+			"Key@1:2",
+			"Keyword(:a)@1:2",
+			"StartVector@1:5",
+			"Integer(1)@1:6",
+			`String("two")@1:8`,
+			"EndVector@1:13",
+			"Key@2:2",
+			"Keyword(:b)@2:2",
+			"Tag(my/tag)@2:5",
+			"StartList@2:13",
+			"Nil(nil)@2:14",
+			"EndList@2:17",
+			"Key@2:20",
+			"StartSet@2:20",
+			`Character(\c)@2:22`,
+			"EndSet@2:24",
+			"EndMap@2:25",
+		}))
+	})
+
+	It("should read a stream of values", func() {
+		described, err := readEvents(newEvents(`1 :a "b" #inst "2018-01-02T03:04:05Z" 1N`))
+		Ω(err).Should(BeNil())
+		Ω(described).Should(BeEquivalentTo([]string{
+			"Integer(1)@1:1",
+			"Keyword(:a)@1:3",
+			`String("b")@1:6`,
+			"Tag(inst)@1:10",
+			"Instant(#inst \"2018-01-02T03:04:05Z\")@1:16",
+			"Integer(1)@1:39",
+		}))
+	})
+
+	It("should skip values", func() {
+		events := newEvents(sampleTransactResult(3))
+
+		event, err := events.Next()
+		Ω(err).Should(BeNil())
+		Ω(event.Type).Should(BeEquivalentTo(StartMapEvent))
+
+		var datoms []Element
+		for event, err = events.Next(); err == nil && event.Type == KeyEvent; event, err = events.Next() {
+			var key Element
+			key, err = events.Element()
+			Ω(err).Should(BeNil())
+
+			if key.Equals(mustKeyword("tx-data")) {
+				event, err = events.Next()
+				Ω(err).Should(BeNil())
+				Ω(event.Type).Should(BeEquivalentTo(StartVectorEvent))
+
+				for event, err = events.Next(); err == nil && event.Type == TagEvent; event, err = events.Next() {
+					var datom Element
+					datom, err = events.Element()
+					Ω(err).Should(BeNil())
+					datoms = append(datoms, datom)
+				}
+				Ω(event.Type).Should(BeEquivalentTo(EndVectorEvent))
+			} else {
+				Ω(events.Skip()).Should(BeNil())
+			}
+		}
+
+		Ω(err).Should(BeNil())
+		Ω(event.Type).Should(BeEquivalentTo(EndMapEvent))
+		Ω(datoms).Should(HaveLen(3))
+		Ω(datoms[0].Tag()).Should(BeEquivalentTo("datom"))
+		Ω(events.Depth()).Should(BeEquivalentTo(0))
+
+		_, err = events.Next()
+		Ω(err).Should(Equal(io.EOF))
+	})
+
+	It("should skip the rest of a collection", func() {
+		events := newEvents(`[[1 #my/tag [2 "]"] {:a (3)}] 4]`)
+
+		for _, expected := range []EventType{StartVectorEvent, StartVectorEvent, IntegerEvent} {
+			event, err := events.Next()
+			Ω(err).Should(BeNil())
+			Ω(event.Type).Should(BeEquivalentTo(expected))
+		}
+
+		Ω(events.Depth()).Should(BeEquivalentTo(2))
+		Ω(events.SkipRest()).Should(BeNil())
+		Ω(events.Depth()).Should(BeEquivalentTo(1))
+
+		elem, err := events.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.Value()).Should(BeEquivalentTo(4))
+
+		event, err := events.Next()
+		Ω(err).Should(BeNil())
+		Ω(event.Type).Should(BeEquivalentTo(EndVectorEvent))
+	})
+
+	It("should skip tagged values", func() {
+		events := newEvents(`[#my/tag {:a 1} 2]`)
+
+		event, err := events.Next()
+		Ω(err).Should(BeNil())
+		event, err = events.Next()
+		Ω(err).Should(BeNil())
+		Ω(event.Type).Should(BeEquivalentTo(TagEvent))
+		Ω(event.Tag).Should(BeEquivalentTo("my/tag"))
+
+		Ω(events.Skip()).Should(BeNil())
+
+		event, err = events.Next()
+		Ω(err).Should(BeNil())
+		Ω(event.Element.Value()).Should(BeEquivalentTo(2))
+	})
+
+	It("should read tagged collections into elements", func() {
+		events := newEvents(`#db/id [:db.part/user -1]`)
+
+		event, err := events.Next()
+		Ω(err).Should(BeNil())
+		Ω(event.Type).Should(BeEquivalentTo(TagEvent))
+
+		elem, err := events.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.String()).Should(BeEquivalentTo("#db/id [:db.part/user -1]"))
+	})
+
+	It("should report the errors", func() {
+		for data, message := range map[string]string{
+			"[1 2":    "Unexpected end of input",
+			"[1 2}":   "Unexpected end token: '}' instead of ']'",
+			"]":       "Unexpected end token: ']'",
+			"@":       "Unexpected token: '@'",
+			"#my/tag": "Unexpected end of input after tag",
+		} {
+			_, err := readEvents(newEvents(data))
+			Ω(err).Should(test.HaveMessage(ErrParserError), data)
+			Ω(err.Error()).Should(ContainSubstring(message), data)
+		}
+
+		events := newEvents("[]")
+		Ω(events.SkipRest()).Should(test.HaveMessage(ErrParserError))
+		_, err := events.Next()
+		Ω(err).Should(BeNil())
+		Ω(events.Skip()).Should(test.HaveMessage(ErrParserError))
+		_, err = events.Element()
+		Ω(err).Should(test.HaveMessage(ErrParserError))
+
+		Ω(newEvents("[1 {]").Skip()).Should(test.HaveMessage(ErrParserError))
+		Ω(newEvents("[1").Skip()).Should(test.HaveMessage(ErrParserError))
+	})
+
+	It("should apply the limits of the parser", func() {
+		parser, err := NewParser(WithLimits(Limits{MaxDepth: 2, MaxElements: 2}))
+		Ω(err).Should(BeNil())
+
+		for _, data := range []string{"[[[1]]]", "[1 2 3]", "[[1 2 3]]"} {
+			events, err := parser.NewEventReader(data)
+			Ω(err).Should(BeNil())
+			_, err = readEvents(events)
+			Ω(err).Should(test.HaveMessage(ErrLimitExceeded), data)
+		}
+
+		events, err := parser.NewEventReader("[[[1]]]")
+		Ω(err).Should(BeNil())
+		Ω(events.Skip()).Should(test.HaveMessage(ErrLimitExceeded))
+
+		parser, err = NewParser(WithLimits(Limits{MaxInputBytes: 2}))
+		Ω(err).Should(BeNil())
+		events, err = parser.NewEventReader("[1 2]")
+		Ω(err).Should(test.HaveMessage(ErrLimitExceeded))
+		Ω(events).Should(BeNil())
+	})
+
+	It("should name the event types", func() {
+		for t := UnknownEvent; t <= ValueEvent; t++ {
+			Ω(t.String()).ShouldNot(BeEmpty())
+			Ω(t.IsStart() && t.IsEnd()).Should(BeFalse())
+		}
+		Ω(EventType(99).String()).Should(BeEquivalentTo("Unknown"))
+		Ω(StartMapEvent.IsStart()).Should(BeTrue())
+		Ω(EndSetEvent.IsEnd()).Should(BeTrue())
+		Ω(fmt.Sprint(Event{Type: TagEvent, Tag: "a"})).Should(BeEquivalentTo("Tag(a)@0:0"))
+	})
+})
+
+func BenchmarkEventsSkipTransactResult(b *testing.B) {
+	data := sampleTransactResult(5000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		events, err := NewEventReader(data)
+		if err == nil {
+			err = events.Skip()
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEventsTransactResult(b *testing.B) {
+	data := sampleTransactResult(5000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		events, err := NewEventReader(data)
+		for err == nil {
+			_, err = events.Next()
+		}
+		if err != io.EOF {
+			b.Fatal(err)
+		}
+	}
+}
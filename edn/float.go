@@ -29,7 +29,7 @@ func initFloat(lexer Lexer) (err error) {
 		}
 		return elem, e
 	}); err == nil {
-		lexer.AddPattern(FloatPrimitive, "[-+]?(0|[1-9][0-9]*)(\\.[0-9]*)?([eE][-+]?[0-9]+)?M?", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(FloatPrimitive, "[-+]?(0|[1-9][0-9]*)(\\.[0-9]*)?([eE][-+]?[0-9]+)?M?", isFloatToken, func(tag string, tokenValue string) (el Element, e error) {
 
 			if strings.HasSuffix(tokenValue, "M") {
 				tokenValue = strings.TrimSuffix(tokenValue, "M")
@@ -48,6 +48,29 @@ func initFloat(lexer Lexer) (err error) {
 	return err
 }
 
+// isFloatToken is the hand-written matcher of the float pattern.
+func isFloatToken(token string) bool {
+	start := skipSign(token, 0)
+	end := skipNatural(token, start)
+	ok := end > start
+
+	if ok && end < len(token) && token[end] == '.' {
+		end = skipDigits(token, end+1)
+	}
+
+	if ok && end < len(token) && (token[end] == 'e' || token[end] == 'E') {
+		exponent := skipSign(token, end+1)
+		end = skipDigits(token, exponent)
+		ok = end > exponent
+	}
+
+	if ok && end < len(token) && token[end] == BigDecSuffix[0] {
+		end++
+	}
+
+	return ok && end == len(token)
+}
+
 // NewFloatElement creates a new float point element or an error.
 func NewFloatElement(value float64) (elem Element) {
 
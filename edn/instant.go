@@ -22,6 +22,9 @@ const (
 
 	// InstantElementTag defines the instant tag value.
 	InstantElementTag = "inst"
+
+	// instantLayout is RFC 3339 with the milliseconds, when there are some, which is the precision of the instants in eva.
+	instantLayout = "2006-01-02T15:04:05.999Z07:00"
 )
 
 // instStringProcessor used the string processor but will accurately create the instances.
@@ -56,7 +59,7 @@ func NewInstantElement(value time.Time) (elem Element) {
 			if len(tag) > 0 {
 				out = TagPrefix + tag + " "
 			}
-			out += value.(time.Time).Format(time.RFC3339)
+			out += "\"" + value.(time.Time).Format(instantLayout) + "\""
 		default:
 			e = MakeError(ErrUnknownMimeType, serializer.MimeType())
 		}
@@ -88,7 +88,11 @@ var _ = Describe("Instant in EDN", func() {
 
 			edn, err := elem.Serialize(EvaEdnMimeType)
 			Ω(err).Should(BeNil())
-			Ω(edn).Should(BeEquivalentTo("#inst 2017-12-28T22:20:30Z"))
+			Ω(edn).Should(BeEquivalentTo("#inst \"2017-12-28T22:20:30Z\""))
+
+			parsed, err := Parse(edn)
+			Ω(err).Should(BeNil())
+			Ω(parsed.Value()).Should(BeEquivalentTo(testValue.Truncate(time.Millisecond)))
 		})
 
 		It("should serialize the instant without an issue", func() {
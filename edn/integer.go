@@ -29,7 +29,7 @@ func initInteger(lexer Lexer) (err error) {
 		}
 		return elem, e
 	}); err == nil {
-		lexer.AddPattern(IntegerPrimitive, "[-+]?(0|[1-9][0-9]*)N?", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(IntegerPrimitive, "[-+]?(0|[1-9][0-9]*)N?", isIntegerToken, func(tag string, tokenValue string) (el Element, e error) {
 
 			if strings.HasSuffix(tokenValue, "N") {
 				tokenValue = strings.TrimSuffix(tokenValue, "N")
@@ -48,6 +48,17 @@ func initInteger(lexer Lexer) (err error) {
 	return err
 }
 
+// isIntegerToken is the hand-written matcher of the integer pattern.
+func isIntegerToken(token string) bool {
+	start := skipSign(token, 0)
+	end := skipNatural(token, start)
+	if end > start && end < len(token) && token[end] == BigIntSuffix[0] {
+		end++
+	}
+
+	return end > start && end == len(token)
+}
+
 // NewIntegerElement creates a new integer element or an error.
 func NewIntegerElement(value int64) (elem Element) {
 
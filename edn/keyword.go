@@ -37,7 +37,7 @@ func initKeyword(lexer Lexer) (err error) {
 		}
 		return elem, e
 	}); err == nil {
-		lexer.AddPattern(SymbolPrimitive, ":([*!?$%&=<>]|\\w)([-+*!?$%&=<>.#]|\\w)*(/([-+*!?$%&=<>.#]|\\w)*)?", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(SymbolPrimitive, ":([*!?$%&=<>]|\\w)([-+*!?$%&=<>.#]|\\w)*(/([-+*!?$%&=<>.#]|\\w)*)?", isKeywordToken, func(tag string, tokenValue string) (el Element, e error) {
 			tokenValue = strings.TrimSuffix(tokenValue, KeywordPrefix)
 			if el, e = NewKeywordElement(tokenValue); e == nil {
 				e = el.SetTag(tag)
@@ -50,6 +50,17 @@ func initKeyword(lexer Lexer) (err error) {
 	return err
 }
 
+// The bytes the keyword pattern accepts.
+var (
+	keywordFirstBytes = newByteClass("*!?$%&=<>", true)
+	keywordPartBytes  = newByteClass("-+*!?$%&=<>.#", true)
+)
+
+// isKeywordToken is the hand-written matcher of the keyword pattern.
+func isKeywordToken(token string) bool {
+	return strings.HasPrefix(token, KeywordPrefix) && isNamed(token[len(KeywordPrefix):], keywordFirstBytes, keywordPartBytes)
+}
+
 // NewKeywordElement creates a new character element or an error.
 //
 // Keywords are identifiers that typically designate themselves. They are semantically akin to enumeration values.
@@ -15,10 +15,9 @@
 package edn
 
 import (
-	"fmt"
-	"github.com/timtadh/lexmachine"
-	"github.com/timtadh/lexmachine/machines"
-	"strings"
+	"regexp"
+	"sort"
+	"sync"
 )
 
 type PrimitiveType int
@@ -36,221 +35,160 @@ const (
 type PrimitiveProcessor func(tag string, tokenValue string) (Element, error)
 type CollectionProcessor func(tag string, elements []Element) (el Element, e error)
 
+// TokenMatcher reports if the whole token is one the processor handles.
+type TokenMatcher func(token string) bool
+
+type primitiveDef struct {
+	matcher   TokenMatcher
+	processor PrimitiveProcessor
+}
+
 type collProcDef struct {
 	start     string
 	end       string
 	processor CollectionProcessor
 }
 
-type tokenType string
-
-const (
-
-	// because all blanks are skipped, all these token types are # of blanks.
-	skipToken    tokenType = " "
-	elementToken tokenType = "  "
-)
-
-func (tt tokenType) String() string {
-	switch tt {
-	case skipToken:
-		return "[Skip Token]"
-	case elementToken:
-		return "[Element]"
-	default:
-		return string(tt)
-	}
-}
-
-func (tt tokenType) Is(this string) bool {
-	return string(tt) == this
-}
-
-// Lexer defines the lexical analyser for the
+// Lexer defines the lexical analyser for the reader.
 type Lexer interface {
 
-	// AddPattern will take a pattern and attach the processor for that pattern.
+	// AddPattern will take a pattern and attach the processor for that pattern. The pattern is a regular expression
+	// that has to match the whole token.
 	AddPattern(priority PrimitiveType, pattern string, processor PrimitiveProcessor)
 
+	// AddMatcher will attach the processor for the pattern, the matcher is a hand-written equivalent of the pattern
+	// and is used in its place when reading.
+	AddMatcher(priority PrimitiveType, pattern string, matcher TokenMatcher, processor PrimitiveProcessor)
+
 	AddCollectionPattern(start string, end string, processor CollectionProcessor)
 
 	Parse(data string) (Element, error)
 }
 
-func splitTag(data []byte, possible string) (tag string, value string) {
-
-	// Special case, if the #{ appears then ignore the splitting and just return the value.
-	if full := string(data); !strings.HasPrefix(full, SetStartLiteral) && strings.HasPrefix(full, TagPrefix) {
-		parts := strings.Fields(full)
-		tag = parts[0]
-		value = strings.TrimPrefix(full, tag)
-		tag = strings.TrimPrefix(tag, TagPrefix)
-		value = strings.TrimSpace(value)
-
-		if len(possible) > 0 && strings.HasSuffix(tag, possible) {
-			tag = strings.TrimSuffix(tag, possible)
-		}
-	} else {
-		value = full
-	}
-
-	return tag, value
+// primitivePattern is a compiled primitive pattern.
+type primitivePattern struct {
+	matcher   TokenMatcher
+	processor PrimitiveProcessor
 }
 
-func buildTagPattern(pattern string, mustHasSpace bool) []byte {
+// primitiveMatcher finds the processor for the tokens of a primitive type.
+type primitiveMatcher struct {
 
-	subPattern := "*"
-	if mustHasSpace {
-		subPattern = "+"
-	}
+	// literals holds the patterns that only match a single token, these are found without running an expression.
+	literals map[string]PrimitiveProcessor
 
-	return []byte(fmt.Sprintf("(%s[A-Za-z][-A-Za-z0-9_/.]*(\\s)%s)?%s", TagPrefix, subPattern, pattern))
+	// patterns holds the rest of the patterns.
+	patterns []*primitivePattern
 }
 
-func runScanner(scanner *lexmachine.Scanner) (tokType tokenType, elems []Element, err error) {
-	var t interface{}
-
-	var eos bool
-	for t, err, eos = scanner.Next(); !eos && err == nil; t, err, eos = scanner.Next() {
-		switch v := t.(type) {
-		case Element:
-			elems = append(elems, v)
-			tokType = elementToken
-		case tokenType:
-			tokType = v
-		}
-
-		if tokType != elementToken && tokType != skipToken {
-			break
-		}
-	}
-
-	if err != nil {
-		switch v := err.(type) {
-		case *machines.UnconsumedInput:
-			err = MakeError(ErrParserError, struct {
-				message string
-				elem    []Element
-			}{
-				v.Error(),
-				elems,
-			})
+// match the token to its processor.
+func (matcher *primitiveMatcher) match(token string) (processor PrimitiveProcessor, found bool) {
+	if processor, found = matcher.literals[token]; !found {
+		for _, p := range matcher.patterns {
+			if p.matcher(token) {
+				processor, found = p.processor, true
+				break
+			}
 		}
 	}
 
-	return tokType, elems, err
+	return processor, found
 }
 
 ///// ----------------------------------------------
 
 type lexerImpl struct {
-	primitivePatterns  map[PrimitiveType]map[string]PrimitiveProcessor
+	primitivePatterns  map[PrimitiveType]map[string]*primitiveDef
 	collectionPatterns map[string]*collProcDef
-	lex                *lexmachine.Lexer
-	built              bool
+	startup            sync.Once
+	startupErr         error
+
+	// primitives holds the compiled primitive patterns for each primitive type.
+	primitives [lastPrimitivePriority]*primitiveMatcher
+
+	// collections holds the collection definitions, the longest start literals first.
+	collections []*collProcDef
 }
 
 // newLexer will create a new lexer.
 func newLexer() (lexer Lexer, err error) {
 	lexer = &lexerImpl{
-		primitivePatterns:  map[PrimitiveType]map[string]PrimitiveProcessor{},
+		primitivePatterns:  map[PrimitiveType]map[string]*primitiveDef{},
 		collectionPatterns: map[string]*collProcDef{},
-		lex:                lexmachine.NewLexer(),
-		built:              false,
 	}
 
 	return lexer, err
 }
 
-// completeStartup of the lexer
-func (lexer *lexerImpl) completeStartup() (err error) {
+// completeStartup of the lexer, the lexer is only compiled once no matter how many parsers use it.
+func (lexer *lexerImpl) completeStartup() error {
+	lexer.startup.Do(func() {
+		lexer.startupErr = lexer.compile()
+	})
 
-	if !lexer.built {
+	return lexer.startupErr
+}
 
-		for i := PrimitiveType(0); i < lastPrimitivePriority; i++ {
-			if v, has := lexer.primitivePatterns[i]; has {
+// compile the patterns into the lexer.
+func (lexer *lexerImpl) compile() (err error) {
 
-				for pattern, p := range v {
-					processor := p // this is required as the processor needs to have a local reference... yay golang oddities! :(
-					lexer.addPattern(buildTagPattern(pattern, true), func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-						tag, value := splitTag(match.Bytes, "")
-						return processor(tag, value)
-					})
-				}
-			}
+	for i := PrimitiveType(0); i < lastPrimitivePriority && err == nil; i++ {
+		matcher := &primitiveMatcher{
+			literals: map[string]PrimitiveProcessor{},
 		}
 
-		endPatterns := map[string]bool{}
-
-		lexSpecialChars := []string{
-			"\\", "[", "]", "{", "}", "(", ")",
+		// the patterns are sorted so the matching does not depend on the order of the map.
+		var patterns []string
+		for pattern := range lexer.primitivePatterns[i] {
+			patterns = append(patterns, pattern)
 		}
+		sort.Strings(patterns)
 
-		for _, def := range lexer.collectionPatterns {
-			processor := def.processor // and again boo - golang oddities! :(
-			end := def.end
-			start := def.start
+		for _, pattern := range patterns {
+			def := lexer.primitivePatterns[i][pattern]
 
-			for _, c := range lexSpecialChars {
-				start = strings.Replace(start, c, "\\"+c, -1)
-				end = strings.Replace(end, c, "\\"+c, -1)
+			var re *regexp.Regexp
+			if re, err = regexp.Compile(pattern); err == nil {
+				literal, complete := re.LiteralPrefix()
+				switch {
+				case complete:
+					matcher.literals[literal] = def.processor
+				case def.matcher != nil:
+					matcher.patterns = append(matcher.patterns, &primitivePattern{
+						matcher:   def.matcher,
+						processor: def.processor,
+					})
+				default:
+					if re, err = regexp.Compile("^(?:" + pattern + ")$"); err == nil {
+						matcher.patterns = append(matcher.patterns, &primitivePattern{
+							matcher:   re.MatchString,
+							processor: def.processor,
+						})
+					}
+				}
 			}
 
-			startRaw := def.start
-
-			if _, has := endPatterns[end]; !has {
-				lexer.addPattern([]byte(end), func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-					return tokenType(end), nil
-				})
-				endPatterns[end] = true
+			if err != nil {
+				err = MakeErrorWithFormat(ErrParserError, "Invalid pattern: '%s'", pattern)
+				break
 			}
+		}
 
-			// Add the non tagged items.
-			lexer.addPattern(buildTagPattern(start, false), func(scan *lexmachine.Scanner, match *machines.Match) (v interface{}, e error) {
-				tag, _ := splitTag(match.Bytes, startRaw)
-
-				var tt tokenType
-				var children []Element
-				var c []Element
-
-				for tt, c, e = runScanner(scan); ; tt, c, e = runScanner(scan) {
-					stop := true
-					if e == nil {
-						children = append(children, c...)
-						switch {
-						case tt == elementToken:
-							stop = false
-						case tt.Is(end):
-						default:
-							e = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s' instead of '%s'", tt.String(), end)
-						}
-					}
-
-					if stop {
-						break
-					}
-				}
-
-				if e == nil {
-					v, e = processor(tag, children)
-				}
+		lexer.primitives[i] = matcher
+	}
 
-				return v, e
-			})
+	if err == nil {
+		for _, def := range lexer.collectionPatterns {
+			lexer.collections = append(lexer.collections, def)
 		}
 
-		lexer.addPattern([]byte("(\\s|,)+"), func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			return skipToken, nil
-		})
-
-		lexer.addPattern([]byte(";[^\\n]*(\\n)?"), func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			return skipToken, nil
+		// the longest start literals are checked first so '#{' is never read as a tag.
+		sort.Slice(lexer.collections, func(i, j int) bool {
+			if li, lj := len(lexer.collections[i].start), len(lexer.collections[j].start); li != lj {
+				return li > lj
+			}
+			return lexer.collections[i].start < lexer.collections[j].start
 		})
-
-		compile := lexer.lex.CompileNFA
-		if err = compile(); err == nil {
-			lexer.built = true
-		}
 	}
 
 	return err
@@ -258,19 +196,17 @@ func (lexer *lexerImpl) completeStartup() (err error) {
 
 // Parse the value
 func (lexer *lexerImpl) Parse(data string) (elem Element, err error) {
-	if err = lexer.completeStartup(); err == nil {
-		var scanner *lexmachine.Scanner
-		if scanner, err = lexer.lex.Scanner([]byte(data)); err == nil {
-			var elems []Element
-			if _, elems, err = runScanner(scanner); err == nil {
-				switch {
-				case len(elems) == 1:
-					elem = elems[0]
-				default:
-					err = MakeErrorWithFormat(ErrParserError, "Expected one result, got: %d", len(elems))
-				}
-			}
-		}
+	return lexer.parse(data, defaultParserConfig)
+}
+
+// parse the value with the parser configuration supplied.
+func (lexer *lexerImpl) parse(data string, config *parserConfig) (elem Element, err error) {
+	if err = config.limits.checkInput(len(data)); err == nil {
+		err = lexer.completeStartup()
+	}
+
+	if err == nil {
+		elem, err = newReader(lexer, newParseSession(config), data).readAll()
 	}
 
 	return elem, err
@@ -278,14 +214,22 @@ func (lexer *lexerImpl) Parse(data string) (elem Element, err error) {
 
 // AddPattern will add a pattern to the lexer
 func (lexer *lexerImpl) AddPattern(priority PrimitiveType, pattern string, processor PrimitiveProcessor) {
+	lexer.AddMatcher(priority, pattern, nil, processor)
+}
 
-	//  map[PrimitiveType]map[string]PrimitiveProcessor
+// AddMatcher will add a pattern, and its hand-written matcher, to the lexer
+func (lexer *lexerImpl) AddMatcher(priority PrimitiveType, pattern string, matcher TokenMatcher, processor PrimitiveProcessor) {
+
+	//  map[PrimitiveType]map[string]*primitiveDef
 	if _, has := lexer.primitivePatterns[priority]; !has {
-		lexer.primitivePatterns[priority] = map[string]PrimitiveProcessor{}
+		lexer.primitivePatterns[priority] = map[string]*primitiveDef{}
 	}
 
 	if _, has := lexer.primitivePatterns[priority][pattern]; !has {
-		lexer.primitivePatterns[priority][pattern] = processor
+		lexer.primitivePatterns[priority][pattern] = &primitiveDef{
+			matcher:   matcher,
+			processor: processor,
+		}
 	}
 }
 
@@ -301,55 +245,84 @@ func (lexer *lexerImpl) AddCollectionPattern(start string, end string, processor
 	}
 }
 
-func (lexer *lexerImpl) addPattern(pattern []byte, action lexmachine.Action) {
+// byteClass is a set of bytes the hand-written matchers accept.
+type byteClass [256]bool
 
-	lexer.lex.Add(pattern, action)
+// newByteClass creates the class of the characters, and the word characters (\w) if requested.
+func newByteClass(chars string, word bool) (class *byteClass) {
+	class = &byteClass{}
+	for i := 0; i < len(chars); i++ {
+		class[chars[i]] = true
+	}
 
-	// NOTE:
-	//   The following code here is to diagnose pattern issues.
+	if word {
+		for c := 0; c < len(class); c++ {
+			class[c] = class[c] || isWordByte(byte(c))
+		}
+	}
 
-	/*
+	return class
+}
 
-		fmt.Println("Pattern: ", string(pattern))
-		lex := lexmachine.NewLexer()
+// has indicates the byte is in the class.
+func (class *byteClass) has(c byte) bool {
+	return class[c]
+}
 
-		lex.Add([]byte(pattern), func(scan *lexmachine.Scanner, match *machines.Match) (interface{}, error) {
-			fmt.Println("  Matches empty: ", string(pattern))
-			return true, nil
-		})
+// isWordByte indicates the byte is a word character (\w).
+func isWordByte(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c) || c == '_'
+}
 
-		compile := lex.CompileNFA
-
-		var err error
-		if err = compile(); err == nil {
-
-			var s *lexmachine.Scanner
-			if s, err = lex.Scanner([]byte("")); err == nil {
-
-				var tok interface{}
-				var end bool
-
-				if tok, err, end = s.Next(); err == nil {
-					if !end {
-						err = errors.New("expected an end of string")
-					} else {
-						if tok != nil {
-							switch v := tok.(type) {
-							case bool:
-								if v {
-									err = errors.New("expected false")
-								}
-							default:
-								err = errors.New(fmt.Sprintf("expected a bool: %#v", tok))
-							}
-						}
-					}
-				}
+// isHexByte indicates the byte is a hexadecimal digit.
+func isHexByte(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isHexQuad indicates the value is made of four hexadecimal digits.
+func isHexQuad(value string) bool {
+	return len(value) == 4 && isHexByte(value[0]) && isHexByte(value[1]) && isHexByte(value[2]) && isHexByte(value[3])
+}
+
+// skipDigits returns the position after the decimal digits starting at the position.
+func skipDigits(token string, pos int) int {
+	for pos < len(token) && isDigit(token[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// skipSign returns the position after the sign, if the token starts with one.
+func skipSign(token string, pos int) int {
+	if pos < len(token) && (token[pos] == '-' || token[pos] == '+') {
+		pos++
+	}
+	return pos
+}
+
+// skipNatural returns the position after the natural number, without leading zeros, starting at the position. The
+// position is unchanged if there is no such number.
+func skipNatural(token string, pos int) int {
+	end := skipDigits(token, pos)
+	if end-pos > 1 && token[pos] == '0' {
+		end = pos
+	}
+	return end
+}
+
+// isNamed indicates the value is a name; one leading byte, then parts with at most one separator.
+func isNamed(value string, first *byteClass, part *byteClass) (ok bool) {
+	if ok = len(value) > 0 && first.has(value[0]); ok {
+		separated := false
+		for i := 1; ok && i < len(value); i++ {
+			switch c := value[i]; {
+			case c == SymbolSeparator[0] && !separated:
+				separated = true
+			default:
+				ok = part.has(c)
 			}
 		}
+	}
 
-		if err != nil {
-			fmt.Println("  Error: ", err)
-		}
-	*/
+	return ok
 }
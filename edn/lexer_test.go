@@ -15,6 +15,9 @@
 package edn
 
 import (
+	"math/rand"
+	"regexp"
+
 	"github.com/Workiva/eva-client-go/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -65,17 +68,6 @@ var _ = Describe("Lexer tests", func() {
 		Ω(err).Should(test.HaveMessage(ErrParserError))
 	})
 
-	It("should break the creation if there is an error", func() {
-		tt := tokenType("  ")
-		Ω(tt.String()).Should(BeEquivalentTo("[Element]"))
-	})
-
-	It("should break the creation if there is an error", func() {
-		tag, value := splitTag([]byte("#my/taco foobar"), "taco")
-		Ω(tag).Should(BeEquivalentTo("my/"))
-		Ω(value).Should(BeEquivalentTo("foobar"))
-	})
-
 	It("should initialize without issue", func() {
 		lexer, err := newLexer()
 		Ω(err).Should(BeNil())
@@ -86,4 +78,37 @@ var _ = Describe("Lexer tests", func() {
 		Ω(elem).Should(BeNil())
 		Ω(err).Should(test.HaveMessage(ErrParserError))
 	})
+
+	It("should match the same tokens with the matchers as with the patterns", func() {
+		l, err := getLexer()
+		Ω(err).Should(BeNil())
+
+		tokens := []string{
+			"", "0", "00", "-0", "+1", "1N", "1NN", "1.", "1.5", ".5", "1e", "1e5", "1E+5", "1.5e-5M", "1M", "01",
+			`\a`, `\ab`, `\u00e9`, `\u00g9`, `\u00e`, `\`, ":a", "::a", ":/a", ":a/b", ":a/b/c", ":a/", "a/", "/",
+			`""`, `"a"`, `"\"`, `"\""`, `"\u00e9"`, `"\u00e"`, `"\q"`, `"a b"`, `"a"b"`, `"é"`,
+		}
+
+		// the alphabet holds the bytes of interest to the patterns, and a few that none accept.
+		alphabet := `abzAZ_019-+.eEMNu:/#*!?$%&=<>"\ @;é`
+		random := rand.New(rand.NewSource(42))
+		for i := 0; i < 20000; i++ {
+			token := make([]byte, random.Intn(8))
+			for j := range token {
+				token[j] = alphabet[random.Intn(len(alphabet))]
+			}
+			tokens = append(tokens, string(token))
+		}
+
+		for _, patterns := range l.(*lexerImpl).primitivePatterns {
+			for pattern, def := range patterns {
+				if def.matcher != nil {
+					re := regexp.MustCompile("^(?:" + pattern + ")$")
+					for _, token := range tokens {
+						Ω(def.matcher(token)).Should(BeEquivalentTo(re.MatchString(token)), "pattern %s, token %q", pattern, token)
+					}
+				}
+			}
+		}
+	})
 })
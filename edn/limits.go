@@ -0,0 +1,131 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import "fmt"
+
+const (
+
+	// ErrLimitExceeded defines the error for input that goes past one of the parser limits.
+	ErrLimitExceeded = ErrorMessage("Limit exceeded")
+
+	// MaxDepthLimit names the nesting depth limit.
+	MaxDepthLimit = "max-depth"
+
+	// MaxElementsLimit names the collection size limit.
+	MaxElementsLimit = "max-elements"
+
+	// MaxStringBytesLimit names the string length limit.
+	MaxStringBytesLimit = "max-string-bytes"
+
+	// MaxInputBytesLimit names the input length limit.
+	MaxInputBytesLimit = "max-input-bytes"
+)
+
+// Limits bounds the resources a single parse may use. A limit of zero, or less, is not enforced.
+type Limits struct {
+
+	// MaxDepth is the deepest collections may be nested.
+	MaxDepth int
+
+	// MaxElements is the most children a single collection may hold, the keys and values of a map both count.
+	MaxElements int
+
+	// MaxStringBytes is the longest a string may be in the source, in bytes.
+	MaxStringBytes int
+
+	// MaxInputBytes is the longest the whole input may be, in bytes.
+	MaxInputBytes int
+}
+
+// DefaultLimits are the limits of a parser created without WithLimits, they are suited to reading untrusted input.
+var DefaultLimits = Limits{
+	MaxDepth:       512,
+	MaxElements:    1 << 20,
+	MaxStringBytes: 8 << 20,
+	MaxInputBytes:  64 << 20,
+}
+
+// NoLimits lifts every limit, for trusted input that goes past the DefaultLimits.
+var NoLimits = Limits{}
+
+// LimitError is the error returned when a parse goes past one of its limits.
+type LimitError struct {
+
+	// Limit is the name of the limit that was exceeded.
+	Limit string
+
+	// Max is the value of the limit.
+	Max int
+
+	// Actual is the value found in the input.
+	Actual int
+}
+
+// Message will get the message part.
+func (e *LimitError) Message() string {
+	return ErrLimitExceeded.Message()
+}
+
+// Error returns the error message.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("[%s]: %s is %d, found %d", ErrLimitExceeded, e.Limit, e.Max, e.Actual)
+}
+
+// Is matches the ErrLimitExceeded kind.
+func (e *LimitError) Is(target error) bool {
+	return target == error(ErrLimitExceeded)
+}
+
+// WithLimits sets the resource limits of the parser, WithLimits(NoLimits) opts out of the DefaultLimits.
+func WithLimits(limits Limits) ParserOption {
+	return func(config *parserConfig) error {
+		config.limits = limits
+		return nil
+	}
+}
+
+// check the actual value against the limit.
+func checkLimit(name string, max int, actual int) (err error) {
+	if max > 0 && actual > max {
+		err = &LimitError{
+			Limit:  name,
+			Max:    max,
+			Actual: actual,
+		}
+	}
+
+	return err
+}
+
+// checkDepth checks the nesting depth.
+func (limits Limits) checkDepth(depth int) error {
+	return checkLimit(MaxDepthLimit, limits.MaxDepth, depth)
+}
+
+// checkElements checks the size of a collection.
+func (limits Limits) checkElements(count int) error {
+	return checkLimit(MaxElementsLimit, limits.MaxElements, count)
+}
+
+// checkString checks the length of a string.
+func (limits Limits) checkString(length int) error {
+	return checkLimit(MaxStringBytesLimit, limits.MaxStringBytes, length)
+}
+
+// checkInput checks the length of the input.
+func (limits Limits) checkInput(length int) error {
+	return checkLimit(MaxInputBytesLimit, limits.MaxInputBytes, length)
+}
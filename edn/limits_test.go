@@ -0,0 +1,125 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"strings"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parser limits", func() {
+
+	limited := func(limits Limits) Parser {
+		parser, err := NewParser(WithLimits(limits))
+		Ω(err).Should(BeNil())
+		return parser
+	}
+
+	expectLimit := func(err error, name string, max int, actual int) {
+		Ω(err).Should(test.HaveMessage(ErrLimitExceeded))
+		Ω(err).Should(BeAssignableToTypeOf(&LimitError{}))
+		limitErr := err.(*LimitError)
+		Ω(limitErr.Limit).Should(BeEquivalentTo(name))
+		Ω(limitErr.Max).Should(BeEquivalentTo(max))
+		Ω(limitErr.Actual).Should(BeEquivalentTo(actual))
+	}
+
+	It("should apply the default limits to the default parser", func() {
+		data := strings.Repeat("[", 600) + strings.Repeat("]", 600)
+		elem, err := Parse(data)
+		expectLimit(err, MaxDepthLimit, DefaultLimits.MaxDepth, DefaultLimits.MaxDepth+1)
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should not limit a parser that opts out", func() {
+		data := strings.Repeat("[", 600) + strings.Repeat("]", 600)
+		elem, err := limited(NoLimits).Parse(data)
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(VectorType))
+	})
+
+	It("should limit the depth", func() {
+		parser := limited(Limits{MaxDepth: 3})
+
+		elem, err := parser.Parse("[{:a #{1}}]")
+		Ω(err).Should(BeNil())
+		Ω(elem).ShouldNot(BeNil())
+
+		elem, err = parser.Parse("[{:a #{(1)}}]")
+		expectLimit(err, MaxDepthLimit, 3, 4)
+		Ω(elem).Should(BeNil())
+
+		elem, err = parser.Parse(strings.Repeat("[", 100000))
+		expectLimit(err, MaxDepthLimit, 3, 4)
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should limit the elements of a collection", func() {
+		parser := limited(Limits{MaxElements: 4})
+
+		elem, err := parser.Parse("[[1 2 3 4] {:a 1 :b 2}]")
+		Ω(err).Should(BeNil())
+		Ω(elem).ShouldNot(BeNil())
+
+		elem, err = parser.Parse("[1 2 3 4 5]")
+		expectLimit(err, MaxElementsLimit, 4, 5)
+		Ω(elem).Should(BeNil())
+
+		elem, err = limited(Limits{MaxElements: 5}).Parse("{:a 1 :b 2 :c 3}")
+		expectLimit(err, MaxElementsLimit, 5, 6)
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should limit the length of strings", func() {
+		parser := limited(Limits{MaxStringBytes: 5})
+
+		elem, err := parser.Parse(`["hello" "world"]`)
+		Ω(err).Should(BeNil())
+		Ω(elem).ShouldNot(BeNil())
+
+		elem, err = parser.Parse(`["hello world"]`)
+		expectLimit(err, MaxStringBytesLimit, 5, 11)
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should limit the length of the input", func() {
+		parser := limited(Limits{MaxInputBytes: 8})
+
+		elem, err := parser.Parse("[1 2 3]")
+		Ω(err).Should(BeNil())
+		Ω(elem).ShouldNot(BeNil())
+
+		elem, err = parser.Parse("[1 2 3 4 5]")
+		expectLimit(err, MaxInputBytesLimit, 8, 11)
+		Ω(elem).Should(BeNil())
+	})
+
+	It("should describe the limit in the error", func() {
+		err := &LimitError{Limit: MaxDepthLimit, Max: 1, Actual: 2}
+		Ω(err.Error()).Should(ContainSubstring(ErrLimitExceeded.Message()))
+		Ω(err.Error()).Should(ContainSubstring("max-depth is 1, found 2"))
+	})
+
+	It("should parse within the default limits", func() {
+		parser := limited(DefaultLimits)
+
+		elem, err := parser.Parse(`{:a [1 2 3] :b "text"}`)
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(MapType))
+	})
+})
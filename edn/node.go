@@ -0,0 +1,250 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import "strings"
+
+// Node is a node of a document. It holds an element along with the text it was read from, including the whitespace,
+// commas and comments before it.
+type Node struct {
+	parser *parserImpl
+	parent *Node
+
+	// leading is the text before the node.
+	leading string
+
+	// tag is the tag, and the text after it, as written.
+	tag string
+
+	// token is the token of a primitive as written.
+	token string
+
+	// def is the definition of a collection, children the nodes within it and inner the text before its end literal.
+	def      *collProcDef
+	children []*Node
+	inner    string
+
+	// elem is the element of the node, it is nil when it has to be read again after an edit.
+	elem Element
+}
+
+// Element returns the element of the node.
+func (node *Node) Element() (elem Element, err error) {
+	if node.elem == nil {
+		node.elem, err = node.parser.Parse(node.Text())
+	}
+
+	return node.elem, err
+}
+
+// Parent returns the collection node that holds this node, or nil for the top level nodes.
+func (node *Node) Parent() *Node {
+	return node.parent
+}
+
+// IsCollection indicates that the node is a collection.
+func (node *Node) IsCollection() bool {
+	return node.def != nil
+}
+
+// Children returns the nodes within the collection.
+func (node *Node) Children() []*Node {
+	return node.children
+}
+
+// Len returns the number of nodes within the collection.
+func (node *Node) Len() int {
+	return len(node.children)
+}
+
+// Tag returns the tag of the node, without the prefix.
+func (node *Node) Tag() string {
+	name := strings.TrimPrefix(node.tag, TagPrefix)
+	end := 0
+	for end < len(name) && isTagPart(name[end]) {
+		end++
+	}
+
+	return name[:end]
+}
+
+// Get returns the node of the value for the key, when the node is a map.
+func (node *Node) Get(key Element) (value *Node, err error) {
+
+	if node.def == nil || node.def.start != MapStartLiteral {
+		err = MakeErrorWithFormat(ErrNoValue, "Not a map: '%s'", node.Text())
+	}
+
+	for i := 0; err == nil && value == nil && i+1 < len(node.children); i += 2 {
+		var child Element
+		if child, err = node.children[i].Element(); err == nil && child.Equals(key) {
+			value = node.children[i+1]
+		}
+	}
+
+	if err == nil && value == nil {
+		err = MakeErrorWithFormat(ErrNoValue, "Key: %s", key.String())
+	}
+
+	return value, err
+}
+
+// Leading returns the whitespace, commas and comments before the node.
+func (node *Node) Leading() string {
+	return node.leading
+}
+
+// SetLeading replaces the whitespace, commas and comments before the node.
+func (node *Node) SetLeading(leading string) (err error) {
+	if err = node.parser.checkTrivia(leading, true); err == nil {
+		node.leading = leading
+		if node.parent != nil {
+			node.parent.separate()
+		}
+	}
+
+	return err
+}
+
+// Text returns the text of the node, without the text before it.
+func (node *Node) Text() string {
+	var builder strings.Builder
+	node.writeText(&builder)
+	return builder.String()
+}
+
+// String returns the text of the node, including the text before it.
+func (node *Node) String() string {
+	var builder strings.Builder
+	node.write(&builder)
+	return builder.String()
+}
+
+// Replace the node with the one in the source, the text before the node is kept.
+func (node *Node) Replace(source string) (err error) {
+
+	var replacement *Node
+	if replacement, err = node.parser.parseNode(source); err == nil {
+		node.tag = replacement.tag
+		node.token = replacement.token
+		node.def = replacement.def
+		node.children = replacement.children
+		node.inner = replacement.inner
+
+		for _, child := range node.children {
+			child.parent = node
+		}
+
+		node.invalidate()
+		node.elem = replacement.elem
+	}
+
+	return err
+}
+
+// ReplaceElement replaces the node with the element, the text before the node is kept.
+func (node *Node) ReplaceElement(elem Element) error {
+	return node.Replace(elem.String())
+}
+
+// Insert the node in the source into the collection at the index. The text before the node in the source is kept, a
+// space is used when there is none and the node is not the first.
+func (node *Node) Insert(index int, source string) (err error) {
+
+	switch {
+	case node.def == nil:
+		err = MakeErrorWithFormat(ErrInvalidEdit, "Not a collection: '%s'", node.Text())
+	case index < 0 || index > len(node.children):
+		err = MakeErrorWithFormat(ErrInvalidEdit, "Index %d out of range [0, %d]", index, len(node.children))
+	}
+
+	var child *Node
+	if err == nil {
+		child, err = node.parser.parseNode(source)
+	}
+
+	if err == nil {
+		child.parent = node
+		node.children = append(node.children, nil)
+		copy(node.children[index+1:], node.children[index:])
+		node.children[index] = child
+
+		node.separate()
+		node.invalidate()
+	}
+
+	return err
+}
+
+// Append the node in the source to the end of the collection.
+func (node *Node) Append(source string) error {
+	return node.Insert(len(node.children), source)
+}
+
+// Remove the node at the index from the collection, along with the text before it.
+func (node *Node) Remove(index int) (err error) {
+
+	switch {
+	case node.def == nil:
+		err = MakeErrorWithFormat(ErrInvalidEdit, "Not a collection: '%s'", node.Text())
+	case index < 0 || index >= len(node.children):
+		err = MakeErrorWithFormat(ErrInvalidEdit, "Index %d out of range [0, %d)", index, len(node.children))
+	default:
+		node.children[index].parent = nil
+		node.children = append(node.children[:index], node.children[index+1:]...)
+
+		node.separate()
+		node.invalidate()
+	}
+
+	return err
+}
+
+// separate the children so they can not run into each other.
+func (node *Node) separate() {
+	for i := 1; i < len(node.children); i++ {
+		if len(node.children[i].leading) == 0 {
+			node.children[i].leading = " "
+		}
+	}
+}
+
+// invalidate the element of the node and the collections holding it.
+func (node *Node) invalidate() {
+	for invalid := node; invalid != nil; invalid = invalid.parent {
+		invalid.elem = nil
+	}
+}
+
+// write the node, including the text before it.
+func (node *Node) write(builder *strings.Builder) {
+	builder.WriteString(node.leading)
+	node.writeText(builder)
+}
+
+// writeText writes the node, without the text before it.
+func (node *Node) writeText(builder *strings.Builder) {
+	builder.WriteString(node.tag)
+	if node.def != nil {
+		builder.WriteString(node.def.start)
+		for _, child := range node.children {
+			child.write(builder)
+		}
+		builder.WriteString(node.inner)
+		builder.WriteString(node.def.end)
+	} else {
+		builder.WriteString(node.token)
+	}
+}
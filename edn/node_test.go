@@ -0,0 +1,134 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"strings"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node", func() {
+
+	var doc *Document
+	var root *Node
+
+	BeforeEach(func() {
+		var err error
+		doc, err = ParseDocument(schemaDocument)
+		Ω(err).Should(BeNil())
+		root = doc.Nodes()[0]
+	})
+
+	It("should change a value and keep the rest of the text", func() {
+		title := root.Children()[0]
+		doc, err := title.Get(mustKeyword("db/doc"))
+		Ω(err).Should(BeNil())
+
+		Ω(doc.ReplaceElement(NewStringElement("The full title"))).Should(BeNil())
+		Ω(doc.Text()).Should(BeEquivalentTo(`"The full title"`))
+
+		expected := strings.Replace(schemaDocument, `"The title of the book"`, `"The full title"`, 1)
+		Ω(root.String() + " ; end of the schema").Should(BeEquivalentTo(expected))
+
+		elem, err := title.Element()
+		Ω(err).Should(BeNil())
+		value, err := elem.(CollectionElement).Get(mustKeyword("db/doc"))
+		Ω(err).Should(BeNil())
+		Ω(value.Value()).Should(BeEquivalentTo("The full title"))
+	})
+
+	It("should add an attribute", func() {
+		attribute := "\n\n ;; the author\n {:db/ident :book/author :db/valueType :db.type/string}"
+		Ω(root.Append(attribute)).Should(BeNil())
+		Ω(root.Len()).Should(BeEquivalentTo(3))
+		Ω(root.Children()[2].Leading()).Should(BeEquivalentTo("\n\n ;; the author\n "))
+
+		Ω(doc.String()).Should(BeEquivalentTo(strings.Replace(schemaDocument, "1.50 :book/price 10.50M :book/count +7N}\n]",
+			"1.50 :book/price 10.50M :book/count +7N}"+attribute+"\n]", 1)))
+
+		elem, err := root.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.(CollectionElement).Len()).Should(BeEquivalentTo(3))
+	})
+
+	It("should separate the nodes", func() {
+		doc, err := ParseDocument("[1]")
+		Ω(err).Should(BeNil())
+		vector := doc.Nodes()[0]
+
+		Ω(vector.Insert(0, "0")).Should(BeNil())
+		Ω(vector.Append("2")).Should(BeNil())
+		Ω(vector.Text()).Should(BeEquivalentTo("[0 1 2]"))
+
+		Ω(vector.Children()[1].SetLeading("")).Should(BeNil())
+		Ω(vector.Text()).Should(BeEquivalentTo("[0 1 2]"))
+
+		Ω(vector.Remove(0)).Should(BeNil())
+		Ω(vector.Children()[0].SetLeading("")).Should(BeNil())
+		Ω(vector.Text()).Should(BeEquivalentTo("[1 2]"))
+	})
+
+	It("should remove a node along with the text before it", func() {
+		Ω(root.Remove(0)).Should(BeNil())
+		Ω(root.Len()).Should(BeEquivalentTo(1))
+		Ω(root.Children()[0].Leading()).Should(BeEquivalentTo("\n\n "))
+
+		elem, err := root.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.(CollectionElement).Len()).Should(BeEquivalentTo(1))
+	})
+
+	It("should replace a collection", func() {
+		id, err := root.Children()[0].Get(mustKeyword("db/id"))
+		Ω(err).Should(BeNil())
+
+		Ω(id.Replace("  #db/id [:db.part/db -1]")).Should(BeNil())
+		Ω(id.Text()).Should(BeEquivalentTo("#db/id [:db.part/db -1]"))
+		Ω(id.Len()).Should(BeEquivalentTo(2))
+		Ω(id.Children()[1].Parent()).Should(BeIdenticalTo(id))
+		Ω(id.Leading()).Should(BeEquivalentTo(" "))
+	})
+
+	It("should reject invalid edits", func() {
+		title := root.Children()[0]
+		ident, err := title.Get(mustKeyword("db/ident"))
+		Ω(err).Should(BeNil())
+
+		Ω(ident.Append("1")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(ident.Remove(0)).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(root.Insert(5, "1")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(root.Remove(-1)).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(root.Append("1 2")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(root.Append("1 ; comment")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(root.Append("[1")).Should(test.HaveMessage(ErrParserError))
+		Ω(ident.SetLeading(" ; comment")).Should(test.HaveMessage(ErrInvalidEdit))
+		Ω(ident.SetLeading(" x ")).Should(test.HaveMessage(ErrInvalidEdit))
+
+		Ω(doc.String()).Should(BeEquivalentTo(schemaDocument))
+	})
+
+	It("should only look up values in maps", func() {
+		value, err := root.Get(mustKeyword("db/id"))
+		Ω(err).Should(test.HaveMessage(ErrNoValue))
+		Ω(value).Should(BeNil())
+
+		value, err = root.Children()[0].Get(mustKeyword("db/unknown"))
+		Ω(err).Should(test.HaveMessage(ErrNoValue))
+		Ω(value).Should(BeNil())
+	})
+})
@@ -0,0 +1,93 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+// parseSession holds the state of a single parse.
+type parseSession struct {
+	*parserConfig
+
+	// depth of the collection currently being parsed.
+	depth int
+}
+
+// newParseSession creates the session for a parse with the configuration supplied.
+func newParseSession(config *parserConfig) *parseSession {
+	return &parseSession{
+		parserConfig: config,
+	}
+}
+
+// enter a collection, this will fail if the collection is nested too deep.
+func (session *parseSession) enter() (err error) {
+	if err = session.limits.checkDepth(session.depth + 1); err == nil {
+		session.depth++
+	}
+
+	return err
+}
+
+// leave the current collection.
+func (session *parseSession) leave() {
+	session.depth--
+}
+
+// checkElements checks the number of children found so far in the current collection.
+func (session *parseSession) checkElements(count int) error {
+	return session.limits.checkElements(count)
+}
+
+// primitive creates the element for the primitive token following the rules of the configuration.
+func (session *parseSession) primitive(priority PrimitiveType, processor PrimitiveProcessor, tag string, tokenValue string) (elem Element, err error) {
+
+	switch {
+	case priority == StringPrimitive:
+		// the quotes are not part of the string.
+		if err = session.limits.checkString(len(tokenValue) - 2); err == nil {
+			elem, err = processor(tag, tokenValue)
+		}
+	case session.numbers == ExactNumbers && priority == IntegerPrimitive:
+		if elem, err = parseExactInteger(tokenValue); err == nil {
+			err = elem.SetTag(tag)
+		}
+	case session.numbers == ExactNumbers && priority == FloatPrimitive:
+		if elem, err = parseExactFloat(tokenValue); err == nil {
+			err = elem.SetTag(tag)
+		}
+	default:
+		elem, err = processor(tag, tokenValue)
+	}
+
+	if err == nil {
+		elem, err = session.interpret(tag, elem)
+	}
+
+	return elem, err
+}
+
+// collection creates the collection element from its children following the rules of the configuration.
+func (session *parseSession) collection(processor CollectionProcessor, tag string, children []Element) (elem Element, err error) {
+
+	if elem, err = processor(tag, children); err == nil {
+		if coll, is := elem.(*collectionElemImpl); is {
+			err = coll.restyle(session.collections, children)
+		}
+
+		if err == nil {
+			elem, err = session.interpret(tag, elem)
+		}
+	}
+
+	return elem, err
+}
@@ -14,38 +14,93 @@
 
 package edn
 
+import "sync"
+
 const (
 	ErrParserError = ErrorMessage("Parser error")
 )
 
+// Parser reads EDN into elements. A parser holds no per-call state, so it is safe for concurrent use.
+type Parser interface {
+
+	// Parse the string into an edn element.
+	Parse(data string) (Element, error)
+
+	// ParseCollection will parse a collection.
+	ParseCollection(data string) (CollectionElement, error)
+
+	// ParseDocument will parse the string into a document that keeps the text around the elements.
+	ParseDocument(data string) (*Document, error)
+
+	// NewEventReader creates a reader of the events of the values in the string.
+	NewEventReader(data string) (EventReader, error)
+}
+
 // globalLexer holds the global lexer.
 var globalLexer Lexer
 
-// getLexer returns the global lexer or an error.
-func getLexer() (lexer Lexer, err error) {
+// globalLexerErr holds the error from creating the global lexer.
+var globalLexerErr error
+
+// globalLexerOnce guards the creation of the global lexer.
+var globalLexerOnce sync.Once
 
-	if globalLexer == nil {
-		globalLexer, err = newLexer()
+// defaultParserConfig holds the configuration used by the package level functions.
+var defaultParserConfig, _ = newParserConfig()
+
+// defaultParser is the parser behind the package level functions.
+var defaultParser Parser
+
+func init() {
+	var err error
+	if defaultParser, err = NewParser(); err != nil {
+		panic(err)
 	}
+}
 
-	return globalLexer, err
+// getLexer returns the global lexer or an error.
+func getLexer() (Lexer, error) {
+	globalLexerOnce.Do(func() {
+		globalLexer, globalLexerErr = newLexer()
+	})
+
+	return globalLexer, globalLexerErr
 }
 
-// Parse the string into an edn element.
-func Parse(data string) (elem Element, err error) {
+// parserImpl implements the Parser interface.
+type parserImpl struct {
+	config *parserConfig
+	lexer  *lexerImpl
+}
+
+// NewParser creates a new parser with the options supplied. Without options the parser behaves like the package level
+// Parse function.
+func NewParser(options ...ParserOption) (parser Parser, err error) {
 
-	var lex Lexer
-	if lex, err = getLexer(); err == nil {
-		elem, err = lex.Parse(data)
+	var config *parserConfig
+	if config, err = newParserConfig(options...); err == nil {
+		var lexer Lexer
+		if lexer, err = getLexer(); err == nil {
+			parser = &parserImpl{
+				config: config,
+				lexer:  lexer.(*lexerImpl),
+			}
+		}
 	}
-	return elem, err
+
+	return parser, err
+}
+
+// Parse the string into an edn element.
+func (parser *parserImpl) Parse(data string) (Element, error) {
+	return parser.lexer.parse(data, parser.config)
 }
 
 // ParseCollection will parse a collection.
-func ParseCollection(data string) (elem CollectionElement, err error) {
+func (parser *parserImpl) ParseCollection(data string) (elem CollectionElement, err error) {
 
 	var rawElem Element
-	if rawElem, err = Parse(data); err == nil {
+	if rawElem, err = parser.Parse(data); err == nil {
 		if rawElem.ElementType().IsCollection() {
 			elem = rawElem.(CollectionElement)
 		} else {
@@ -55,3 +110,49 @@ func ParseCollection(data string) (elem CollectionElement, err error) {
 
 	return elem, err
 }
+
+// ParseDocument will parse the string into a document that keeps the text around the elements.
+func (parser *parserImpl) ParseDocument(data string) (doc *Document, err error) {
+	if err = parser.config.limits.checkInput(len(data)); err == nil {
+		err = parser.lexer.completeStartup()
+	}
+
+	if err == nil {
+		doc, err = newDocumentReader(parser, data).readDocument()
+	}
+
+	return doc, err
+}
+
+// NewEventReader creates a reader of the events of the values in the string.
+func (parser *parserImpl) NewEventReader(data string) (events EventReader, err error) {
+	if err = parser.config.limits.checkInput(len(data)); err == nil {
+		err = parser.lexer.completeStartup()
+	}
+
+	if err == nil {
+		events = newEventReader(parser, data)
+	}
+
+	return events, err
+}
+
+// Parse the string into an edn element.
+func Parse(data string) (Element, error) {
+	return defaultParser.Parse(data)
+}
+
+// ParseCollection will parse a collection.
+func ParseCollection(data string) (CollectionElement, error) {
+	return defaultParser.ParseCollection(data)
+}
+
+// ParseDocument will parse the string into a document that keeps the text around the elements.
+func ParseDocument(data string) (*Document, error) {
+	return defaultParser.ParseDocument(data)
+}
+
+// NewEventReader creates a reader of the events of the values in the string.
+func NewEventReader(data string) (EventReader, error) {
+	return defaultParser.NewEventReader(data)
+}
@@ -0,0 +1,193 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import "strings"
+
+const (
+
+	// ErrInvalidParserOption defines the error for a parser option that can not be applied.
+	ErrInvalidParserOption = ErrorMessage("Invalid parser option")
+
+	// ErrUnknownTag defines the error for a tag that a strict parser does not know how to handle.
+	ErrUnknownTag = ErrorMessage("Unknown tag")
+)
+
+// CollectionStyle defines how the parser builds maps and sets.
+type CollectionStyle int
+
+const (
+
+	// HashedCollections builds maps and sets on top of a hash, iteration order is not defined. This is the default.
+	HashedCollections CollectionStyle = iota
+
+	// OrderedCollections builds maps and sets that iterate and serialize in the order they appeared in the source.
+	OrderedCollections
+
+	// PersistentCollections builds collections that can not be modified once parsed, which makes the parsed tree safe
+	// to share between goroutines.
+	PersistentCollections
+)
+
+// NumberPolicy defines how the parser maps EDN numbers onto go types.
+type NumberPolicy int
+
+const (
+
+	// NativeNumbers parses integers into int64 and floats into float64, the N and M precision suffixes are ignored.
+	// This is the default.
+	NativeNumbers NumberPolicy = iota
+
+	// ExactNumbers keeps the precision of the source: integers with the N suffix or that overflow an int64 become
+	// *big.Int elements and floats with the M suffix become *big.Float elements.
+	ExactNumbers
+)
+
+// TagHandler interprets a tagged element. The returned element replaces the tagged one in the parsed result.
+type TagHandler func(tag string, elem Element) (Element, error)
+
+// TagRegistry maps tags (without the # prefix) to the handlers that interpret them. A nil handler marks the tag as
+// known, the element is then left as the lexer produced it.
+type TagRegistry map[string]TagHandler
+
+// builtInTags are the tags the lexer interprets by itself.
+var builtInTags = map[string]bool{
+	InstantElementTag: true,
+	UUIDElementTag:    true,
+}
+
+// ParserOption configures a parser.
+type ParserOption func(config *parserConfig) error
+
+// parserConfig holds the options of a parser.
+type parserConfig struct {
+	tags        TagRegistry
+	strictTags  bool
+	collections CollectionStyle
+	numbers     NumberPolicy
+	limits      Limits
+}
+
+// newParserConfig creates the default configuration and applies the options to it.
+func newParserConfig(options ...ParserOption) (config *parserConfig, err error) {
+	config = &parserConfig{
+		tags:        TagRegistry{},
+		collections: HashedCollections,
+		numbers:     NativeNumbers,
+		limits:      DefaultLimits,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			err = MakeError(ErrInvalidParserOption, "nil option")
+		} else {
+			err = option(config)
+		}
+
+		if err != nil {
+			config = nil
+			break
+		}
+	}
+
+	return config, err
+}
+
+// WithTagHandler registers the handler for the tag. A nil handler marks the tag as known without interpreting it.
+func WithTagHandler(tag string, handler TagHandler) ParserOption {
+	return func(config *parserConfig) (err error) {
+		tag = strings.TrimPrefix(tag, TagPrefix)
+
+		var prefix, name string
+		if prefix, name, err = decodeSymbol(tag); err == nil {
+			config.tags[encodeSymbol(prefix, name)] = handler
+		} else {
+			err = MakeErrorWithFormat(ErrInvalidParserOption, "tag: %#v", tag)
+		}
+
+		return err
+	}
+}
+
+// WithTagRegistry registers all the handlers within the registry.
+func WithTagRegistry(registry TagRegistry) ParserOption {
+	return func(config *parserConfig) (err error) {
+		for tag, handler := range registry {
+			if err = WithTagHandler(tag, handler)(config); err != nil {
+				break
+			}
+		}
+
+		return err
+	}
+}
+
+// WithStrictTags sets if the parser reports tags that are neither built in nor registered. A lenient parser, the
+// default, keeps the tag on the element.
+func WithStrictTags(strict bool) ParserOption {
+	return func(config *parserConfig) error {
+		config.strictTags = strict
+		return nil
+	}
+}
+
+// WithCollections sets the collection style of the parser.
+func WithCollections(style CollectionStyle) ParserOption {
+	return func(config *parserConfig) (err error) {
+		switch style {
+		case HashedCollections, OrderedCollections, PersistentCollections:
+			config.collections = style
+		default:
+			err = MakeErrorWithFormat(ErrInvalidParserOption, "collection style: %d", style)
+		}
+
+		return err
+	}
+}
+
+// WithNumbers sets the number policy of the parser.
+func WithNumbers(policy NumberPolicy) ParserOption {
+	return func(config *parserConfig) (err error) {
+		switch policy {
+		case NativeNumbers, ExactNumbers:
+			config.numbers = policy
+		default:
+			err = MakeErrorWithFormat(ErrInvalidParserOption, "number policy: %d", policy)
+		}
+
+		return err
+	}
+}
+
+// interpret runs the tag handler registered for the tag of the element.
+func (config *parserConfig) interpret(tag string, elem Element) (Element, error) {
+
+	var err error
+	if len(tag) > 0 {
+		if handler, has := config.tags[tag]; has {
+			if handler != nil {
+				elem, err = handler(tag, elem)
+			}
+		} else if config.strictTags && !builtInTags[tag] {
+			err = MakeErrorWithFormat(ErrUnknownTag, "%s%s", TagPrefix, tag)
+		}
+	}
+
+	if err != nil {
+		elem = nil
+	}
+
+	return elem, err
+}
@@ -0,0 +1,198 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parser options", func() {
+
+	Context("construction", func() {
+		It("should create a default parser", func() {
+			parser, err := NewParser()
+			Ω(err).Should(BeNil())
+			Ω(parser).ShouldNot(BeNil())
+
+			elem, err := parser.Parse("[1 2 3]")
+			Ω(err).Should(BeNil())
+			Ω(elem.ElementType()).Should(BeEquivalentTo(VectorType))
+		})
+
+		It("should reject invalid options", func() {
+			for _, option := range []ParserOption{
+				nil,
+				WithCollections(CollectionStyle(42)),
+				WithNumbers(NumberPolicy(42)),
+				WithTagHandler("bad tag", nil),
+				WithTagRegistry(TagRegistry{"#bad tag": nil}),
+			} {
+				parser, err := NewParser(option)
+				Ω(err).Should(test.HaveMessage(ErrInvalidParserOption))
+				Ω(parser).Should(BeNil())
+			}
+		})
+
+		It("should parse collections", func() {
+			parser, err := NewParser()
+			Ω(err).Should(BeNil())
+
+			coll, err := parser.ParseCollection("{:a 1}")
+			Ω(err).Should(BeNil())
+			Ω(coll.Len()).Should(BeEquivalentTo(1))
+
+			coll, err = parser.ParseCollection(":a")
+			Ω(err).Should(test.HaveMessage(ErrParserError))
+			Ω(coll).Should(BeNil())
+		})
+
+		It("should be safe to use concurrently", func() {
+			parser, err := NewParser(WithCollections(OrderedCollections))
+			Ω(err).Should(BeNil())
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 16)
+			for i := 0; i < 16; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					expected := fmt.Sprintf("{:a %d, :b [%d]}", i, i)
+					elem, e := parser.Parse(expected)
+					if e == nil && elem.String() != expected {
+						e = fmt.Errorf("expected %s, got %s", expected, elem.String())
+					}
+					errs <- e
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+
+			for e := range errs {
+				Ω(e).Should(BeNil())
+			}
+		})
+	})
+
+	Context("tags", func() {
+		It("should keep unknown tags when lenient", func() {
+			parser, err := NewParser()
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("#my/tag [1]")
+			Ω(err).Should(BeNil())
+			Ω(elem.Tag()).Should(BeEquivalentTo("my/tag"))
+		})
+
+		It("should reject unknown tags when strict", func() {
+			parser, err := NewParser(WithStrictTags(true), WithTagHandler("#db/id", nil))
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("[#my/tag 1]")
+			Ω(err).Should(test.HaveMessage(ErrUnknownTag))
+			Ω(elem).Should(BeNil())
+
+			elem, err = parser.Parse("#db/id [:db.part/user -1]")
+			Ω(err).Should(BeNil())
+			Ω(elem.Tag()).Should(BeEquivalentTo("db/id"))
+
+			elem, err = parser.Parse(`#inst "2018-01-02T03:04:05Z"`)
+			Ω(err).Should(BeNil())
+			Ω(elem.ElementType()).Should(BeEquivalentTo(InstantType))
+		})
+
+		It("should run the registered handlers", func() {
+			parser, err := NewParser(WithTagRegistry(TagRegistry{
+				"my/upper": func(tag string, elem Element) (Element, error) {
+					return NewStringElement(fmt.Sprintf("%s:%s", tag, elem.ElementType())), nil
+				},
+				"my/fail": func(tag string, elem Element) (Element, error) {
+					return nil, MakeError(ErrorMessage("expected"), nil)
+				},
+			}))
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("[#my/upper 1 #my/upper :a]")
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo(`["my/upper::db.type/long" "my/upper::db.type/keyword"]`))
+
+			elem, err = parser.Parse("[#my/fail 1]")
+			Ω(err).Should(test.HaveMessage(ErrorMessage("expected")))
+			Ω(elem).Should(BeNil())
+		})
+	})
+
+	Context("collections", func() {
+		It("should keep the source order when ordered", func() {
+			parser, err := NewParser(WithCollections(OrderedCollections))
+			Ω(err).Should(BeNil())
+
+			source := "{:z 1, :y #{3 2 1}, :x {:c 1, :b 2, :a 3}, :w 4, :v 5}"
+			elem, err := parser.Parse(source)
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo("{:z 1, :y #{3 2 1}, :x {:c 1, :b 2, :a 3}, :w 4, :v 5}"))
+
+			coll := elem.(CollectionElement)
+			Ω(coll.Append(NewStringElement("u"), NewIntegerElement(6))).Should(BeNil())
+			Ω(coll.Prepend(NewStringElement("a"), NewIntegerElement(0))).Should(BeNil())
+			Ω(coll.String()).Should(BeEquivalentTo(`{"a" 0, :z 1, :y #{3 2 1}, :x {:c 1, :b 2, :a 3}, :w 4, :v 5, "u" 6}`))
+
+			value, err := coll.Get(":w")
+			Ω(err).Should(BeNil())
+			Ω(value.Value()).Should(BeEquivalentTo(4))
+		})
+
+		It("should not allow persistent collections to change", func() {
+			parser, err := NewParser(WithCollections(PersistentCollections))
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("{:a [1 2]}")
+			Ω(err).Should(BeNil())
+
+			coll := elem.(CollectionElement)
+			Ω(coll.Append(NewStringElement("b"), NewIntegerElement(2))).Should(test.HaveMessage(ErrImmutableCollection))
+			Ω(coll.SetTag("my/tag")).Should(test.HaveMessage(ErrImmutableCollection))
+
+			child, err := coll.Get(":a")
+			Ω(err).Should(BeNil())
+			Ω(child.(CollectionElement).Prepend(NewIntegerElement(0))).Should(test.HaveMessage(ErrImmutableCollection))
+			Ω(coll.String()).Should(BeEquivalentTo("{:a [1 2]}"))
+		})
+	})
+
+	Context("numbers", func() {
+		It("should use native numbers by default", func() {
+			parser, err := NewParser()
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("[1N 1.5M]")
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo("[1 1.5E+00]"))
+		})
+
+		It("should keep the precision with exact numbers", func() {
+			parser, err := NewParser(WithNumbers(ExactNumbers))
+			Ω(err).Should(BeNil())
+
+			elem, err := parser.Parse("[1 1N 1.5 1.5M #my/tag 99999999999999999999]")
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo("[1 1N 1.5E+00 1.5M #my/tag 99999999999999999999N]"))
+		})
+	})
+})
@@ -0,0 +1,384 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The primitive types a token can be, by the way the token starts.
+var (
+	stringTokenTypes    = []PrimitiveType{StringPrimitive}
+	characterTokenTypes = []PrimitiveType{CharacterPrimitive}
+	numberTokenTypes    = []PrimitiveType{IntegerPrimitive, FloatPrimitive}
+	symbolTokenTypes    = []PrimitiveType{LiteralPrimitive, SymbolPrimitive}
+)
+
+// reader reads the elements from the input in a single pass, descending into the collections as they are found.
+type reader struct {
+	lexer   *lexerImpl
+	session *parseSession
+	data    string
+	pos     int
+}
+
+// newReader creates a reader for the data.
+func newReader(lexer *lexerImpl, session *parseSession, data string) *reader {
+	return &reader{
+		lexer:   lexer,
+		session: session,
+		data:    data,
+	}
+}
+
+// isSpace indicates that the byte separates elements and is otherwise ignored.
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f', '\v', ',':
+		return true
+	}
+	return false
+}
+
+// isDelimiter indicates that the byte ends a token.
+func isDelimiter(c byte) bool {
+	switch c {
+	case ';', '"', '(', ')', '[', ']', '{', '}':
+		return true
+	}
+	return isSpace(c)
+}
+
+// isTagStart indicates that the byte can start a tag.
+func isTagStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isTagPart indicates that the byte can be within a tag.
+func isTagPart(c byte) bool {
+	switch c {
+	case '-', '_', '/', '.':
+		return true
+	}
+	return isTagStart(c) || (c >= '0' && c <= '9')
+}
+
+// isDigit indicates that the byte is a decimal digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// readAll reads the single element the data holds.
+func (r *reader) readAll() (elem Element, err error) {
+
+	var count int
+	for done := false; !done && err == nil; {
+		var next Element
+		var closing string
+		if next, closing, err = r.read(); err == nil {
+			switch {
+			case next != nil:
+				elem = next
+				count++
+			case len(closing) > 0:
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s'", closing)
+			default:
+				done = true
+			}
+		}
+	}
+
+	if err == nil && count != 1 {
+		err = MakeErrorWithFormat(ErrParserError, "Expected one result, got: %d", count)
+	}
+
+	if err != nil {
+		elem = nil
+	}
+
+	return elem, err
+}
+
+// skip the spaces, commas and comments.
+func (r *reader) skip() {
+	for r.pos < len(r.data) {
+		switch c := r.data[r.pos]; {
+		case isSpace(c):
+			r.pos++
+		case c == ';':
+			if end := strings.IndexByte(r.data[r.pos:], '\n'); end >= 0 {
+				r.pos += end + 1
+			} else {
+				r.pos = len(r.data)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// read the next element. When the next token closes a collection the closing literal is returned instead, when the
+// data is exhausted neither is returned.
+func (r *reader) read() (elem Element, closing string, err error) {
+
+	if r.skip(); r.pos < len(r.data) {
+		if closing = r.closing(); len(closing) > 0 {
+			r.pos += len(closing)
+		} else {
+			var tag string
+			if tag, err = r.readTag(); err == nil {
+				elem, err = r.readValue(tag)
+			}
+		}
+	}
+
+	return elem, closing, err
+}
+
+// closing returns the end literal at the current position, if there is one.
+func (r *reader) closing() (end string) {
+	for _, def := range r.lexer.collections {
+		if strings.HasPrefix(r.data[r.pos:], def.end) {
+			end = def.end
+			break
+		}
+	}
+
+	return end
+}
+
+// collection returns the collection that starts at the current position, if there is one.
+func (r *reader) collection() (found *collProcDef) {
+	for _, def := range r.lexer.collections {
+		if strings.HasPrefix(r.data[r.pos:], def.start) {
+			found = def
+			break
+		}
+	}
+
+	return found
+}
+
+// readTag reads the tag at the current position, if there is one.
+func (r *reader) readTag() (tag string, err error) {
+
+	if strings.HasPrefix(r.data[r.pos:], TagPrefix) && r.collection() == nil {
+		start := r.pos + len(TagPrefix)
+		end := start
+		if end < len(r.data) && isTagStart(r.data[end]) {
+			for end++; end < len(r.data) && isTagPart(r.data[end]); end++ {
+			}
+		}
+
+		switch {
+		case end == start, end < len(r.data) && !isDelimiter(r.data[end]):
+			err = MakeErrorWithFormat(ErrParserError, "Invalid tag: '%s'", r.token(r.pos))
+		default:
+			tag = r.data[start:end]
+			r.pos = end
+
+			// tags can not be nested, nor can they tag nothing.
+			switch r.skip(); {
+			case r.pos >= len(r.data):
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input after tag: '%s'", tag)
+			case len(r.closing()) > 0:
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s' after tag: '%s'", r.closing(), tag)
+			case strings.HasPrefix(r.data[r.pos:], TagPrefix) && r.collection() == nil:
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected tag: '%s' after tag: '%s'", r.token(r.pos), tag)
+			}
+		}
+	}
+
+	return tag, err
+}
+
+// readValue reads the collection or primitive at the current position.
+func (r *reader) readValue(tag string) (elem Element, err error) {
+	if def := r.collection(); def != nil {
+		elem, err = r.readCollection(def, tag)
+	} else {
+		elem, err = r.readPrimitive(tag)
+	}
+
+	return elem, err
+}
+
+// readCollection reads the children of the collection up to its end literal.
+func (r *reader) readCollection(def *collProcDef, tag string) (elem Element, err error) {
+	r.pos += len(def.start)
+
+	// the depth is checked before descending so deeply nested input can not exhaust the stack.
+	if err = r.session.enter(); err == nil {
+		var children []Element
+		for done := false; !done && err == nil; {
+			var child Element
+			var closing string
+			if child, closing, err = r.read(); err == nil {
+				if child != nil {
+					children = append(children, child)
+					err = r.session.checkElements(len(children))
+				} else {
+					done, err = collectionEnd(def, closing)
+				}
+			}
+		}
+
+		r.session.leave()
+
+		if err == nil {
+			elem, err = r.session.collection(def.processor, tag, children)
+		}
+	}
+
+	return elem, err
+}
+
+// collectionEnd checks that the closing literal, found in place of a child, ends the collection.
+func collectionEnd(def *collProcDef, closing string) (done bool, err error) {
+	switch {
+	case closing == def.end:
+		done = true
+	case len(closing) > 0:
+		err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s' instead of '%s'", closing, def.end)
+	default:
+		err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input, expected: '%s'", def.end)
+	}
+
+	return done, err
+}
+
+// readPrimitive reads the primitive token at the current position and hands it to its processor.
+func (r *reader) readPrimitive(tag string) (elem Element, err error) {
+
+	start := r.pos
+
+	var types []PrimitiveType
+	if types, err = r.skipPrimitive(); err == nil {
+		token := r.data[start:r.pos]
+
+		var processor PrimitiveProcessor
+		var priority PrimitiveType
+		var found bool
+		for _, priority = range types {
+			if processor, found = r.lexer.primitives[priority].match(token); found {
+				break
+			}
+		}
+
+		if found {
+			elem, err = r.session.primitive(priority, processor, tag, token)
+		} else {
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected token: '%s'", r.token(start))
+		}
+	}
+
+	return elem, err
+}
+
+// skipPrimitive moves past the primitive token at the current position, returning the primitive types it can be.
+func (r *reader) skipPrimitive() (types []PrimitiveType, err error) {
+
+	switch c := r.data[r.pos]; {
+	case c == '"':
+		types = stringTokenTypes
+		err = r.skipString()
+	case c == '\\':
+		types = characterTokenTypes
+		_, size := utf8.DecodeRuneInString(r.data[r.pos+1:])
+		r.pos += 1 + size
+		r.skipToken()
+	case isDigit(c), (c == '-' || c == '+') && r.pos+1 < len(r.data) && isDigit(r.data[r.pos+1]):
+		types = numberTokenTypes
+		r.skipToken()
+	default:
+		types = symbolTokenTypes
+		r.skipToken()
+	}
+
+	return types, err
+}
+
+// skipValue moves past the value at the current position without reading it into elements. Only the depth limit
+// applies to the skipped values.
+func (r *reader) skipValue() (err error) {
+
+	var ends []string
+	for done := false; !done && err == nil; done = len(ends) == 0 {
+		r.skip()
+		closing := r.closing()
+
+		switch {
+		case r.pos >= len(r.data):
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected end of input")
+		case len(closing) > 0 && len(ends) > 0:
+			if closing == ends[len(ends)-1] {
+				r.pos += len(closing)
+				ends = ends[:len(ends)-1]
+			} else {
+				err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s' instead of '%s'", closing, ends[len(ends)-1])
+			}
+		case len(closing) > 0:
+			err = MakeErrorWithFormat(ErrParserError, "Unexpected end token: '%s'", closing)
+		default:
+			if _, err = r.readTag(); err == nil {
+				if def := r.collection(); def != nil {
+					r.pos += len(def.start)
+					ends = append(ends, def.end)
+					err = r.session.limits.checkDepth(r.session.depth + len(ends))
+				} else {
+					_, err = r.skipPrimitive()
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+// skipToken moves past the rest of the token.
+func (r *reader) skipToken() {
+	for r.pos < len(r.data) && !isDelimiter(r.data[r.pos]) {
+		r.pos++
+	}
+}
+
+// skipString moves past the string, leaving the escapes to its processor.
+func (r *reader) skipString() (err error) {
+	start := r.pos
+	for r.pos++; r.pos < len(r.data) && r.data[r.pos] != '"'; r.pos++ {
+		if r.data[r.pos] == '\\' {
+			r.pos++
+		}
+	}
+
+	if r.pos < len(r.data) {
+		r.pos++
+	} else {
+		err = MakeErrorWithFormat(ErrParserError, "Unterminated string: '%s'", r.token(start))
+	}
+
+	return err
+}
+
+// token returns the text from the start to the end of its token, for the error messages.
+func (r *reader) token(start int) string {
+	end := start + 1
+	for end < len(r.data) && !isDelimiter(r.data[end]) {
+		end++
+	}
+
+	return r.data[start:end]
+}
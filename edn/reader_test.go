@@ -0,0 +1,218 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edn
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// sampleTransactResult creates a transaction result like the ones returned by the server with the number of datoms.
+func sampleTransactResult(datoms int) string {
+	var builder strings.Builder
+	builder.WriteString(`{:eva.client.service/tempids {#db/id [:db.part/user -1] 8796093023233}, `)
+	builder.WriteString(`:db-before #eva.client.service/snapshot-ref {:label "test-db", :as-of 1}, `)
+	builder.WriteString(`:db-after #eva.client.service/snapshot-ref {:label "test-db", :as-of 2}, `)
+	builder.WriteString(`:tx-data [`)
+	for i := 0; i < datoms; i++ {
+		fmt.Fprintf(&builder, `#datom [%d %d "Book number %d" 4398046511105 true] `, 8796093023233+i, 60+i%4, i)
+	}
+	builder.WriteString(`]}`)
+	return builder.String()
+}
+
+// sampleQueryResult creates a query result like the ones returned by the server with the number of rows.
+func sampleQueryResult(rows int) string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&builder, `[%d "Book number %d" :book.genre/fiction %d.5 #inst "2018-01-02T03:04:05Z"] `, 8796093023233+i, i, i)
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+// legacyCase is what the lexmachine lexer the reader replaced produced for an input, the corpus was recorded with it
+// before it was removed.
+type legacyCase struct {
+	Input   string `json:"input"`
+	Element string `json:"element,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// readLegacyCorpus reads the inputs the reader has to agree with the legacy lexer on.
+func readLegacyCorpus() (corpus []legacyCase) {
+	data, err := ioutil.ReadFile("testdata/legacy_lexer.json")
+	if err == nil {
+		err = json.Unmarshal(data, &corpus)
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return corpus
+}
+
+// goldenForm prints the element with the type of every value and with the maps and sets sorted, so two elements
+// print the same only if they are equal.
+func goldenForm(elem Element) (form string) {
+
+	if coll, is := elem.(CollectionElement); is {
+		var children []string
+		_ = coll.IterateChildren(func(k Element, v Element) error {
+			if elem.ElementType() == MapType {
+				children = append(children, goldenForm(k)+" "+goldenForm(v))
+			} else {
+				children = append(children, goldenForm(v))
+			}
+			return nil
+		})
+
+		var open, end string
+		switch elem.ElementType() {
+		case MapType:
+			open, end = "{", "}"
+			sort.Strings(children)
+		case SetType:
+			open, end = "#{", "}"
+			sort.Strings(children)
+		case ListType:
+			open, end = "(", ")"
+		default:
+			open, end = "[", "]"
+		}
+
+		if elem.HasTag() {
+			open = TagPrefix + elem.Tag() + " " + open
+		}
+		form = open + strings.Join(children, " ") + end
+	} else {
+		form = string(elem.ElementType()) + " " + elem.String()
+	}
+
+	return form
+}
+
+var _ = Describe("Reader", func() {
+
+	Context("equivalence with the legacy lexer", func() {
+		for _, golden := range readLegacyCorpus() {
+			golden := golden
+			It(fmt.Sprintf("should read %.80q like the legacy lexer", golden.Input), func() {
+				actual, err := Parse(golden.Input)
+
+				if len(golden.Error) > 0 {
+					if ednErr, is := err.(*Error); is {
+						Ω(ednErr).Should(test.HaveMessage(ErrorMessage(golden.Error)))
+					} else {
+						Ω(err).Should(MatchError(golden.Error))
+					}
+					Ω(actual).Should(BeNil())
+				} else {
+					Ω(err).Should(BeNil())
+					Ω(goldenForm(actual)).Should(BeEquivalentTo(golden.Element))
+				}
+			})
+		}
+	})
+
+	Context("errors", func() {
+		for data, message := range map[string]string{
+			`"unterminated`: "Unterminated string",
+			"[1 2":          "Unexpected end of input",
+			"[1 2}":         "Unexpected end token: '}' instead of ']'",
+			"]":             "Unexpected end token: ']'",
+			"#1 2":          "Invalid tag",
+			"#my/tag":       "Unexpected end of input after tag",
+			"#a #b 1":       "Unexpected tag",
+			"@":             "Unexpected token: '@'",
+			"1 2":           "Expected one result, got: 2",
+		} {
+			data, message := data, message
+			It(fmt.Sprintf("should describe the error in %q", data), func() {
+				elem, err := Parse(data)
+				Ω(err).Should(test.HaveMessage(ErrParserError))
+				Ω(err.Error()).Should(ContainSubstring(message))
+				Ω(elem).Should(BeNil())
+			})
+		}
+	})
+
+	Context("allocation budget", func() {
+
+		// the budgets are per element in the result and include the elements themselves.
+		It("should stay within the budget for query results", func() {
+			data := sampleQueryResult(100)
+			allocs := testing.AllocsPerRun(10, func() {
+				_, _ = Parse(data)
+			})
+			Ω(allocs / (100 * 6)).Should(BeNumerically("<=", queryResultAllocBudget))
+		})
+
+		It("should stay within the budget for transaction results", func() {
+			data := sampleTransactResult(100)
+			allocs := testing.AllocsPerRun(10, func() {
+				_, _ = Parse(data)
+			})
+			Ω(allocs / (100 * 6)).Should(BeNumerically("<=", transactResultAllocBudget))
+		})
+	})
+})
+
+// The allocation budgets, per element read, of typical results. The lexmachine lexer needed over 8 allocations per
+// element for both.
+const (
+	queryResultAllocBudget    = 6.0
+	transactResultAllocBudget = 5.0
+)
+
+func benchmarkRead(b *testing.B, data string) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadQueryResult(b *testing.B) {
+	benchmarkRead(b, sampleQueryResult(100))
+}
+
+func BenchmarkReadTransactResult(b *testing.B) {
+	benchmarkRead(b, sampleTransactResult(100))
+}
+
+func BenchmarkReadLargeTransactResult(b *testing.B) {
+	benchmarkRead(b, sampleTransactResult(5000))
+}
+
+func BenchmarkReadNested(b *testing.B) {
+	benchmarkRead(b, strings.Repeat("[{:a ", 100)+"1"+strings.Repeat("}]", 100))
+}
+
+func BenchmarkReadLongString(b *testing.B) {
+	benchmarkRead(b, `"`+strings.Repeat("lorem ipsum dolor sit amet ", 10000)+`"`)
+}
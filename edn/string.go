@@ -16,6 +16,7 @@ package edn
 
 import (
 	"strconv"
+	"strings"
 )
 
 type stringProcessor func(string) (Element, error)
@@ -86,7 +87,7 @@ func initString(lexer Lexer) (err error) {
 		}
 		return elem, e
 	}); err == nil {
-		lexer.AddPattern(StringPrimitive, "\"(\\w|\\d| |[-+*!?$%&=<>.#:()\\[\\]@^;,/{}'|`~]|\\\\([tbnrf\"'\\\\]|u[0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f]))*\"", func(tag string, tokenValue string) (el Element, e error) {
+		lexer.AddMatcher(StringPrimitive, "\"(\\w|\\d| |[-+*!?$%&=<>.#:()\\[\\]@^;,/{}'|`~]|\\\\([tbnrf\"'\\\\]|u[0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f][0-9A-Fa-f]))*\"", isStringToken, func(tag string, tokenValue string) (el Element, e error) {
 			var proc stringProcessor
 			var has bool
 
@@ -107,6 +108,30 @@ func initString(lexer Lexer) (err error) {
 	return err
 }
 
+// stringBytes are the bytes the string pattern accepts without an escape.
+var stringBytes = newByteClass(" -+*!?$%&=<>.#:()[]@^;,/{}'|`~", true)
+
+// isStringToken is the hand-written matcher of the string pattern.
+func isStringToken(token string) (ok bool) {
+	if ok = len(token) >= 2 && token[0] == '"' && token[len(token)-1] == '"'; ok {
+		content := token[1 : len(token)-1]
+		for i := 0; ok && i < len(content); i++ {
+			switch c := content[i]; {
+			case c != '\\':
+				ok = stringBytes.has(c)
+			case i+1 < len(content) && strings.IndexByte(`tbnrf"'\`, content[i+1]) >= 0:
+				i++
+			case strings.HasPrefix(content[i:], `\u`) && i+6 <= len(content) && isHexQuad(content[i+2:i+6]):
+				i += 5
+			default:
+				ok = false
+			}
+		}
+	}
+
+	return ok
+}
+
 // NewStringElement creates a new string element or an error.
 func NewStringElement(value string) (elem Element) {
 
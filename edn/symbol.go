@@ -46,12 +46,12 @@ const (
 	// Symbols begin with a non-numeric character and can contain alphanumeric characters and . * + ! - _ ? $ % & = < >.
 	// If -, + or . are the first character, the second character (if any) must be non-numeric. Additionally, : # are
 	// allowed as constituent characters in symbols other than as the first character.
-	symbolRegex = `^((` + numericModifierSymbols + `)|((((` + numericModifierSymbols + `)(` + legalFirstSymbols + `|[[:alpha:]]))|(` + legalFirstSymbols + `|[[:alpha:]]))+(` + numericModifierSymbols + `|` + legalFirstSymbols + `|` + specialSymbols + `|[[:alnum:]])*))$`
+	symbolRegex = `^((` + numericModifierSymbols + `)|((((` + numericModifierSymbols + `)(` + numericModifierSymbols + `|` + legalFirstSymbols + `|[[:alpha:]]))|(` + legalFirstSymbols + `|[[:alpha:]]))+(` + numericModifierSymbols + `|` + legalFirstSymbols + `|` + specialSymbols + `|[[:alnum:]])*))$`
 )
 
 // init will add the element factory to the collection of factories
 func initSymbol(lexer Lexer) (err error) {
-	lexer.AddPattern(SymbolPrimitive, "[*!?$%&=<>_a-zA-Z.]([-+*!?$%&=<>_.#]|\\w)*(/([-+*!?$%&=<>_.#]|\\w)*)?", func(tag string, tokenValue string) (el Element, e error) {
+	lexer.AddMatcher(SymbolPrimitive, "[*!?$%&=<>_a-zA-Z.]([-+*!?$%&=<>_.#]|\\w)*(/([-+*!?$%&=<>_.#]|\\w)*)?", isSymbolToken, func(tag string, tokenValue string) (el Element, e error) {
 		if el, e = NewSymbolElement(tokenValue); e == nil {
 			e = el.SetTag(tag)
 		}
@@ -61,6 +61,17 @@ func initSymbol(lexer Lexer) (err error) {
 	return err
 }
 
+// The bytes the symbol pattern accepts.
+var (
+	symbolFirstBytes = newByteClass("*!?$%&=<>_.abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", false)
+	symbolPartBytes  = newByteClass("-+*!?$%&=<>_.#", true)
+)
+
+// isSymbolToken is the hand-written matcher of the symbol pattern.
+func isSymbolToken(token string) bool {
+	return isNamed(token, symbolFirstBytes, symbolPartBytes)
+}
+
 // symbolMatcher is the matching mechanism for symbols
 var symbolMatcher = regexp.MustCompile(symbolRegex).MatchString
 
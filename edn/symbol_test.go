@@ -292,9 +292,8 @@ var _ = Describe("Symbol in EDN", func() {
 			&testDefinition{"foo", &keywordValue{"", "foo"}},
 			&testDefinition{"bar/foo", &keywordValue{"bar", "foo"}},
 
-			// TODO: These are not working...
-			// &testDefinition{".", &keywordValue{"", "."}},
-			// &testDefinition{"...", &keywordValue{"", "..."}},
+			&testDefinition{".", &keywordValue{"", "."}},
+			&testDefinition{"...", &keywordValue{"", "..."}},
 
 			&testDefinition{"*", &keywordValue{"", "*"}},
 			&testDefinition{"!", &keywordValue{"", "!"}},
@@ -126,12 +126,12 @@ var typeDefinitions = []*elementDefinition{
 	{VectorType, initVector},
 	{MapType, initMap},
 	{SetType, initSet},
+	{BigIntType, initBigInt},
+	{BigDecType, initBigDec},
 
 	// TODO
 	{URIType, nil},
 	{BytesType, nil},
-	{BigIntType, nil},
-	{BigDecType, nil},
 	{DoubleType, nil},
 	{RefType, nil},
 }
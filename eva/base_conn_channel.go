@@ -14,18 +14,24 @@
 
 package eva
 
-import "github.com/Workiva/eva-client-go/edn"
+import (
+	"context"
+	"sync"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
 
 // ConnectionChannel defines the channel to the eva connection
 type BaseConnectionChannel struct {
 	*BaseChannel
 	transactImpl      TransactImpl
 	asOfSnapshotImpl  AsOfSnapshotImpl
-	tenantsWithSchema map[string]bool
+	schemaLock        sync.Mutex
+	tenantsWithSchema map[string]map[string]bool
 }
 
-type AsOfSnapshotImpl func(asOf edn.Serializable) (SnapshotChannel, error)
-type TransactImpl func(transaction edn.Serializable) (Result, error)
+type AsOfSnapshotImpl func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error)
+type TransactImpl func(ctx context.Context, transaction edn.Serializable) (Result, error)
 
 func NewBaseConnectionChannel(label edn.Serializable, source Source, transactImpl TransactImpl, asOfSnapshotImpl AsOfSnapshotImpl) (channel *BaseConnectionChannel, err error) {
 
@@ -40,7 +46,7 @@ func NewBaseConnectionChannel(label edn.Serializable, source Source, transactImp
 				BaseChannel:       base,
 				transactImpl:      transactImpl,
 				asOfSnapshotImpl:  asOfSnapshotImpl,
-				tenantsWithSchema: make(map[string]bool),
+				tenantsWithSchema: make(map[string]map[string]bool),
 			}
 		}
 	} else {
@@ -50,34 +56,40 @@ func NewBaseConnectionChannel(label edn.Serializable, source Source, transactImp
 	return channel, err
 }
 
-// Transact the data to the channel
-func (channel *BaseConnectionChannel) Transact(data ...interface{}) (result Result, err error) {
+// Transact the data to the channel, the report is the one of the last transaction submitted.
+func (channel *BaseConnectionChannel) Transact(data ...interface{}) (TransactReport, error) {
+	return channel.TransactContext(context.Background(), data...)
+}
 
-	var transactions []edn.Serializable
-	if len(data) > 0 {
-		for _, item := range data {
+// TransactContext transacts the data to the channel, giving up when the context is done. The transactions are
+// submitted in order and stop at the first one that fails or that the service rejects, its report is returned so the
+// rejection is read from report.Error() like for a single transaction. The basis t of the database after each
+// transaction is recorded for the snapshots of the latest database, see SnapshotChannel.BasisT.
+func (channel *BaseConnectionChannel) TransactContext(ctx context.Context, data ...interface{}) (report TransactReport, err error) {
 
-			switch typedItem := item.(type) {
-			case string:
-				transactions = append(transactions, RawString(typedItem))
-			case edn.Serializable:
-				transactions = append(transactions, typedItem)
-			default:
-				err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Unsupported type: %T", typedItem)
-			}
-		}
-
-	} else {
-		err = edn.MakeError(edn.ErrInvalidInput, "No data")
-	}
+	var transactions []edn.Serializable
+	transactions, err = transactionsOf(data)
 
 	if err == nil && len(transactions) > 0 {
 		for _, trx := range transactions {
-			result, err = channel.transactImpl(trx)
+			if err = ctx.Err(); err == nil {
+				var result Result
+				if result, err = channel.transactImpl(ctx, trx); err == nil {
+					report, err = newTransactReport(channel, result)
+				}
+			}
+
+			if err != nil {
+				break
+			} else if _, rejected := report.Error(); rejected {
+				break
+			} else if t := report.AfterT(); t > 0 {
+				bases.observe(databaseKey(channel), t)
+			}
 		}
 	}
 
-	return result, err
+	return report, err
 }
 
 // Label to this particular channel
@@ -85,8 +97,9 @@ func (channel *BaseConnectionChannel) Label() string {
 	return channel.BaseChannel.Label()
 }
 
-// AsOfSnapshot returns the snapshot channel as of the rules provided.
-func (channel *BaseConnectionChannel) AsOfSnapshot(data interface{}) (snap SnapshotChannel, err error) {
+// AsOfSnapshot returns the snapshot channel as of a t, the entity id of a transaction or a time.Time, the options set
+// the view of the snapshot.
+func (channel *BaseConnectionChannel) AsOfSnapshot(data interface{}, options ...SnapshotOption) (snap SnapshotChannel, err error) {
 
 	var elem edn.Serializable
 	if data != nil {
@@ -94,13 +107,71 @@ func (channel *BaseConnectionChannel) AsOfSnapshot(data interface{}) (snap Snaps
 	}
 
 	if err == nil {
-		snap, err = channel.asOfSnapshotImpl(elem)
+		snap, err = channel.asOfSnapshotImpl(elem, options...)
 	}
 
 	return snap, err
 }
 
+// HistorySnapshot returns the latest snapshot channel that sees all the facts ever asserted or retracted.
+func (channel *BaseConnectionChannel) HistorySnapshot() (SnapshotChannel, error) {
+	return channel.AsOfSnapshot(nil, WithHistory())
+}
+
+// SinceSnapshot returns the latest snapshot channel restricted to the facts added after the basis.
+func (channel *BaseConnectionChannel) SinceSnapshot(basis interface{}) (SnapshotChannel, error) {
+	return channel.AsOfSnapshot(nil, WithSince(basis))
+}
+
 // LatestSnapshot returns the latest snapshot channel.
 func (channel *BaseConnectionChannel) LatestSnapshot() (SnapshotChannel, error) {
 	return channel.AsOfSnapshot(nil)
 }
+
+// Subscribe streams the basis t of the database from the t on, polling the latest snapshot until the context is done.
+// The basis t of the latest snapshot is the one observed by the process, see SnapshotChannel.BasisT, so that the
+// transactions committed by the other writers are not streamed. To resume a subscription, subscribe from its
+// checkpoint.
+func (channel *BaseConnectionChannel) Subscribe(ctx context.Context, fromT int64, options ...SubscribeOption) (Subscription, error) {
+	return newSubscription(ctx, channel, fromT, options...)
+}
+
+// schemaEnsured checks if EnsureSchema ensured the attribute definition for the tenant.
+func (channel *BaseConnectionChannel) schemaEnsured(tenant string, def string) bool {
+	channel.schemaLock.Lock()
+	defer channel.schemaLock.Unlock()
+	return channel.tenantsWithSchema[tenant][def]
+}
+
+// recordSchema records that EnsureSchema ensured the attribute definition for the tenant.
+func (channel *BaseConnectionChannel) recordSchema(tenant string, def string) {
+	channel.schemaLock.Lock()
+	defer channel.schemaLock.Unlock()
+	if channel.tenantsWithSchema[tenant] == nil {
+		channel.tenantsWithSchema[tenant] = make(map[string]bool)
+	}
+	channel.tenantsWithSchema[tenant][def] = true
+}
+
+// transactionsOf reads the transactions, which are strings or serializable values.
+func transactionsOf(data []interface{}) (transactions []edn.Serializable, err error) {
+
+	if len(data) > 0 {
+		for _, item := range data {
+
+			switch typedItem := item.(type) {
+			case string:
+				transactions = append(transactions, RawString(typedItem))
+			case edn.Serializable:
+				transactions = append(transactions, typedItem)
+			default:
+				err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Unsupported type: %T", typedItem)
+			}
+		}
+
+	} else {
+		err = edn.MakeError(edn.ErrInvalidInput, "No data")
+	}
+
+	return transactions, err
+}
@@ -15,6 +15,9 @@
 package eva
 
 import (
+	"context"
+	"errors"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/test"
 	. "github.com/onsi/ginkgo"
@@ -42,7 +45,7 @@ var _ = Describe("base connection channel test", func() {
 			Ω(conn).ShouldNot(BeNil())
 			Ω(conn.Label()).Should(BeEquivalentTo("label"))
 
-			var result Result
+			var result TransactReport
 			result, err = conn.Transact("foo")
 			Ω(err).Should(BeNil())
 			Ω(result).ShouldNot(BeNil())
@@ -51,7 +54,7 @@ var _ = Describe("base connection channel test", func() {
 			var has bool
 			str, has = result.String()
 			Ω(has).Should(BeTrue())
-			Ω(str).Should(BeEquivalentTo("test"))
+			Ω(str).Should(BeEquivalentTo(mockTransactPayload))
 
 			result, err = conn.Transact(edn.NewStringElement("trx"))
 			Ω(err).Should(BeNil())
@@ -59,7 +62,7 @@ var _ = Describe("base connection channel test", func() {
 
 			str, has = result.String()
 			Ω(has).Should(BeTrue())
-			Ω(str).Should(BeEquivalentTo("test"))
+			Ω(str).Should(BeEquivalentTo(mockTransactPayload))
 
 			result, err = conn.Transact()
 			Ω(err).ShouldNot(BeNil())
@@ -67,4 +70,82 @@ var _ = Describe("base connection channel test", func() {
 			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
 		})
 	})
+
+	Context("with a context", func() {
+
+		type ctxKey string
+
+		var seen []context.Context
+		var reject bool
+		var conn ConnectionChannel
+
+		BeforeEach(func() {
+			seen = nil
+			reject = false
+
+			config, err := NewConfiguration("{\"category\": \"foo\"}")
+			Ω(err).Should(BeNil())
+
+			tenant, err := NewTenant("foo")
+			Ω(err).Should(BeNil())
+
+			source, err := NewBaseSource(config, tenant, &mockSource{}, makeMockConnChannel, mockQuery)
+			Ω(err).Should(BeNil())
+
+			conn, err = NewBaseConnectionChannel(
+				edn.NewStringElement("label"),
+				source,
+				func(ctx context.Context, transaction edn.Serializable) (Result, error) {
+					seen = append(seen, ctx)
+					if reject {
+						return newMockFailedResult(), nil
+					}
+					return newMockPayloadResult(mockTransactPayload), nil
+				},
+				func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error) {
+					return nil, nil
+				})
+			Ω(err).Should(BeNil())
+		})
+
+		It("should hand the context to the implementation", func() {
+			ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+
+			report, err := conn.TransactContext(ctx, "foo", "bar")
+			Ω(err).Should(BeNil())
+			Ω(report).ShouldNot(BeNil())
+			Ω(seen).Should(HaveLen(2))
+			Ω(seen[0].Value(ctxKey("key"))).Should(BeEquivalentTo("value"))
+		})
+
+		It("should not transact once the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			report, err := conn.TransactContext(ctx, "foo")
+			Ω(err).ShouldNot(BeNil())
+			Ω(errors.Is(err, context.Canceled)).Should(BeTrue())
+			Ω(report).Should(BeNil())
+			Ω(seen).Should(BeEmpty())
+		})
+
+		It("should stop at the first rejected transaction", func() {
+			reject = true
+
+			report, err := conn.TransactContext(context.Background(), "foo", "bar")
+			Ω(err).Should(BeNil())
+			Ω(seen).Should(HaveLen(1))
+
+			rejection, failed := report.Error()
+			Ω(failed).Should(BeTrue())
+			Ω(rejection).Should(test.HaveMessage(ErrSourceError))
+		})
+
+		It("should use the background context without one", func() {
+			_, err := conn.Transact("foo")
+			Ω(err).Should(BeNil())
+			Ω(seen).Should(HaveLen(1))
+			Ω(seen[0]).Should(BeIdenticalTo(context.Background()))
+		})
+	})
 })
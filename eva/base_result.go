@@ -0,0 +1,89 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"sync"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+// BaseResult holds the payload of a result and parses it the first time it is needed.
+type BaseResult struct {
+	payload []byte
+	parse   sync.Once
+	elem    edn.Element
+	err     error
+}
+
+// NewBaseResult creates the base result over the payload.
+func NewBaseResult(payload []byte) *BaseResult {
+	return &BaseResult{
+		payload: payload,
+	}
+}
+
+// String version of the call.
+func (result *BaseResult) String() (string, bool) {
+	return string(result.payload), len(result.payload) > 0
+}
+
+// Raw returns the payload as it was received.
+func (result *BaseResult) Raw() []byte {
+	return result.payload
+}
+
+// Element returns the parsed payload, it is parsed once and then cached.
+func (result *BaseResult) Element() (edn.Element, error) {
+	result.parse.Do(func() {
+		result.elem, result.err = responseParser.Parse(string(result.payload))
+	})
+
+	return result.elem, result.err
+}
+
+// Decode the payload into the value pointed to by v, see edn.Decode.
+func (result *BaseResult) Decode(v interface{}) (err error) {
+	var elem edn.Element
+	if elem, err = result.Element(); err == nil {
+		err = edn.Decode(elem, v)
+	}
+
+	return err
+}
+
+// elementResult is the result of an element the client built, i.e. of the entities pulled one id at a time.
+type elementResult struct {
+	*BaseResult
+}
+
+// newElementResult creates the result of the element, its payload is the edn of the element.
+func newElementResult(elem edn.Element) (result Result, err error) {
+	var payload string
+	if payload, err = elem.Serialize(edn.EvaEdnMimeType); err == nil {
+		base := NewBaseResult([]byte(payload))
+		base.parse.Do(func() {
+			base.elem = elem
+		})
+		result = &elementResult{BaseResult: base}
+	}
+
+	return result, err
+}
+
+// Error from the call, the element holds none.
+func (result *elementResult) Error() (error, bool) {
+	return nil, false
+}
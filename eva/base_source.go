@@ -14,12 +14,16 @@
 
 package eva
 
-import "github.com/Workiva/eva-client-go/edn"
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
 
 type ConnectionChannelMaker func(label edn.Serializable, source Source) (channel ConnectionChannel, err error)
 
 // QueryImplementation defines the query implementation function.
-type QueryImplementation func(interface{}, ...interface{}) (Result, error)
+type QueryImplementation func(context.Context, interface{}, ...interface{}) (Result, error)
 
 // BaseSource defines the base source.
 type BaseSource struct {
@@ -98,17 +102,28 @@ func (source *BaseSource) LatestSnapshot(label interface{}) (channel SnapshotCha
 	return channel, err
 }
 
-// AsOfSnapshot returns the snapshot channel of the specified category as of the rules provided.
-func (source *BaseSource) AsOfSnapshot(label interface{}, asOf interface{}) (channel SnapshotChannel, err error) {
+// AsOfSnapshot returns the snapshot channel of the specified category as of a t, the entity id of a transaction or a
+// time.Time, the options set the view of the snapshot.
+func (source *BaseSource) AsOfSnapshot(label interface{}, asOf interface{}, options ...SnapshotOption) (channel SnapshotChannel, err error) {
 	var conn ConnectionChannel
 	if conn, err = source.Connection(label); err == nil {
-		channel, err = conn.AsOfSnapshot(asOf)
+		channel, err = conn.AsOfSnapshot(asOf, options...)
 	}
 
 	return channel, err
 }
 
-// Query the source for data.
-func (source *BaseSource) Query(query interface{}, parameters ...interface{}) (result Result, err error) {
-	return source.query(query, parameters...)
+// Query the source for data, the result is read according to the find spec of the query.
+func (source *BaseSource) Query(query interface{}, parameters ...interface{}) (QueryResult, error) {
+	return source.QueryContext(context.Background(), query, parameters...)
+}
+
+// QueryContext queries the source for data, giving up when the context is done.
+func (source *BaseSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (queryResult QueryResult, err error) {
+	var result Result
+	if result, err = source.query(ctx, query, parameters...); err == nil {
+		queryResult = newQueryResult(query, result)
+	}
+
+	return queryResult, err
 }
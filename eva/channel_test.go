@@ -15,6 +15,8 @@
 package eva
 
 import (
+	"context"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/test"
 	. "github.com/onsi/ginkgo"
@@ -39,14 +41,14 @@ var _ = Describe("Base Channel", func() {
 
 	tenant := "org"
 
-	asOfSnapshot := func(edn.Serializable) (channel SnapshotChannel, err error) {
+	asOfSnapshot := func(edn.Serializable, ...SnapshotOption) (channel SnapshotChannel, err error) {
 
 		channel = &BaseSnapshotChannel{}
 
 		return channel, err
 	}
 
-	goodTransact := func(data edn.Serializable) (result Result, err error) {
+	goodTransact := func(ctx context.Context, data edn.Serializable) (result Result, err error) {
 		return nil, nil
 	}
 
@@ -0,0 +1,79 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+)
+
+// StatusError is implemented by the errors of calls the service answered with a failing status.
+type StatusError interface {
+	error
+
+	// StatusCode returns the status the service answered with.
+	StatusCode() int
+}
+
+// IsRetryable checks if the call that failed with the error can be made again as it is: network failures, timeouts,
+// throttling and unavailable services. Client errors, invalid input and parse failures are not retryable. The failures
+// are classified by the status the service answered with, except for a malformed transaction which fails again
+// whatever the status.
+func IsRetryable(err error) (retryable bool) {
+
+	var statusErr StatusError
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrIncorrectTransactSyntax):
+	case errors.As(err, &statusErr):
+		switch code := statusErr.StatusCode(); code {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			retryable = true
+		default:
+			retryable = code >= http.StatusInternalServerError && code != http.StatusNotImplemented
+		}
+	case errors.As(err, &netErr):
+		retryable = true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		retryable = true
+	}
+
+	return retryable
+}
+
+// IsNotFound checks if the error is the service answering that what was asked for does not exist.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict checks if the error is the service answering that the call conflicts with the current state, i.e. a
+// concurrent transaction or a failed compare and swap.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// hasStatus checks if the error is a status error with the status.
+func hasStatus(err error, status int) (has bool) {
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		has = statusErr.StatusCode() == status
+	}
+
+	return has
+}
@@ -0,0 +1,94 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/Workiva/eva-client-go/edn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// statusError is a failed call the service answered with the status.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return http.StatusText(e.code)
+}
+
+func (e *statusError) StatusCode() int {
+	return e.code
+}
+
+var _ = Describe("error classification test", func() {
+
+	It("should retry the network failures", func() {
+		refused := &url.Error{
+			Op:  "Post",
+			URL: "http://localhost",
+			Err: &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connect: connection refused")},
+		}
+		Ω(IsRetryable(refused)).Should(BeTrue())
+		Ω(IsRetryable(edn.MakeError(ErrSourceError, refused))).Should(BeTrue())
+		Ω(IsRetryable(io.EOF)).Should(BeTrue())
+		Ω(IsRetryable(io.ErrUnexpectedEOF)).Should(BeTrue())
+	})
+
+	It("should classify the client errors by their status", func() {
+		unavailable := &statusError{code: http.StatusServiceUnavailable}
+		Ω(IsRetryable(ClientErrorWithCause(newClientError(-1, "", "", "", nil), unavailable))).Should(BeTrue())
+		Ω(IsRetryable(ClientErrorWithCause(newClientError(ErrIncorrectTransactSyntax.Code(), "", "", "", nil), unavailable))).Should(BeFalse())
+		Ω(IsRetryable(newClientError(-1, "", "", "", nil))).Should(BeFalse())
+
+		notFound := &statusError{code: http.StatusNotFound}
+		Ω(IsNotFound(ClientErrorWithCause(newClientError(-1, "", "", "", nil), notFound))).Should(BeTrue())
+		Ω(IsConflict(ClientErrorWithCause(newClientError(-1, "", "", "", nil), notFound))).Should(BeFalse())
+	})
+
+	It("should not classify the other failures", func() {
+		Ω(IsRetryable(nil)).Should(BeFalse())
+		Ω(IsRetryable(edn.MakeError(edn.ErrInvalidInput, nil))).Should(BeFalse())
+
+		_, parseErr := edn.Parse("[1")
+		Ω(IsRetryable(parseErr)).Should(BeFalse())
+
+		Ω(IsNotFound(nil)).Should(BeFalse())
+		Ω(IsConflict(io.EOF)).Should(BeFalse())
+	})
+
+	It("should classify the cumulative errors", func() {
+		conflict := ClientErrorWithCause(newClientError(-1, "", "", "", nil), &statusError{code: http.StatusConflict})
+		cumulative := edn.AppendError(io.EOF, conflict)
+		Ω(IsConflict(cumulative)).Should(BeTrue())
+	})
+
+	It("should find the cause of the client errors", func() {
+		cause := &url.Error{Op: "Post", URL: "http://localhost", Err: io.EOF}
+		clientErr := ClientErrorWithCause(newClientError(-1, "", "", "", nil), cause)
+
+		var urlErr *url.Error
+		Ω(errors.As(clientErr, &urlErr)).Should(BeTrue())
+		Ω(urlErr).Should(BeIdenticalTo(cause))
+		Ω(clientErr.Error()).Should(BeEquivalentTo(newClientError(-1, "", "", "", nil).Error()))
+	})
+})
@@ -15,41 +15,94 @@
 package eva
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/Workiva/eva-client-go/edn"
 )
 
 type ClientErrorKeyword string
 
-//{
-//	:message "",
-//	:ex-info {
-//		:explanation "Malformed transact request.",
-//		:type "IncorrectTransactSyntax",
-//		:code 3000},
-//	:ex-data ""
-//}
+// The client service reports the errors as:
+//
+//	{
+//		:message "",
+//		:ex-info {
+//			:explanation "Malformed transact request.",
+//			:type "IncorrectTransactSyntax",
+//			:code 3000},
+//		:ex-data ""
+//	}
 
 const (
 	ErrSourceError = edn.ErrorMessage("source error")
 )
 
-// Get the error from just the code.
-// -- What about the other data?
-
+// ClientError is an error reported by the eva client service.
 type ClientError interface {
 	error
+
+	// Name of the error type, i.e. "IncorrectTransactSyntax".
 	Name() string
+
+	// Keyword naming the error type, when the service reports the `:type` as a keyword rather than a string.
 	Keyword() edn.SymbolElement
+
+	// Description of the error type, the `:explanation`.
 	Description() string
+
+	// Code of the error type.
 	Code() int
-	//	Details() [] // TODO
+
+	// Message returns the message of the error kind, ErrSourceError.
+	Message() string
+
+	// ServiceMessage returns the `:message` the service gave for this particular error.
+	ServiceMessage() string
+
+	// Data returns the `:ex-data` of the error, nil when there is none.
+	Data() edn.Element
 }
 
 // Error is the error type.
 type clientErrorImpl struct {
-	err  *edn.Error
-	code int64
-	//	details  // TODO
+	err         *edn.Error
+	message     string
+	name        string
+	keyword     edn.SymbolElement
+	explanation string
+	code        int
+	data        edn.Element
+	cause       error
+}
+
+// newClientError creates the client error, the name and explanation default to the ones in the catalog.
+func newClientError(code int, name string, explanation string, message string, data edn.Element) *clientErrorImpl {
+
+	if known, has := LookupErrorCode(code); has {
+		if len(name) == 0 {
+			name = known.Name()
+		}
+		if len(explanation) == 0 {
+			explanation = known.Explanation()
+		}
+	}
+
+	details := fmt.Sprintf("%s %d", name, code)
+	if len(message) > 0 {
+		details = fmt.Sprintf("%s (%s)", message, details)
+	} else if len(explanation) > 0 {
+		details = fmt.Sprintf("%s (%s)", explanation, details)
+	}
+
+	return &clientErrorImpl{
+		err:         edn.MakeError(ErrSourceError, details),
+		message:     message,
+		name:        name,
+		explanation: explanation,
+		code:        code,
+		data:        data,
+	}
 }
 
 // Error returns the error message.
@@ -57,14 +110,160 @@ func (e *clientErrorImpl) Error() string {
 	return e.err.Error()
 }
 
+// Name of the error type.
+func (e *clientErrorImpl) Name() string {
+	return e.name
+}
+
+// Keyword naming the error type, nil when the service named it with a string.
+func (e *clientErrorImpl) Keyword() edn.SymbolElement {
+	return e.keyword
+}
+
+// Description of the error type.
+func (e *clientErrorImpl) Description() string {
+	return e.explanation
+}
+
+// Code of the error type.
+func (e *clientErrorImpl) Code() int {
+	return e.code
+}
+
+// Message returns the message of the error kind.
+func (e *clientErrorImpl) Message() string {
+	return e.err.Message()
+}
+
+// ServiceMessage returns the `:message` the service gave for this particular error.
+func (e *clientErrorImpl) ServiceMessage() string {
+	return e.message
+}
+
+// Data returns the `:ex-data` of the error.
+func (e *clientErrorImpl) Data() edn.Element {
+	return e.data
+}
+
+// Unwrap returns the error code from the catalog, so that errors.Is matches the sentinels.
+func (e *clientErrorImpl) Unwrap() (err error) {
+	if known, has := LookupErrorCode(e.code); has {
+		err = known
+	}
+	return err
+}
+
+// As finds the target in the cause of the error, such as the failing response the service reported it with.
+func (e *clientErrorImpl) As(target interface{}) bool {
+	return e.cause != nil && errors.As(e.cause, target)
+}
+
+// Is matches the error codes by their code, so that codes missing from the catalog can still be matched, and the
+// ErrSourceError kind.
+func (e *clientErrorImpl) Is(target error) (is bool) {
+	switch t := target.(type) {
+	case *ErrorCode:
+		is = t.Code() == e.code
+	case edn.ErrorMessage:
+		is = e.err.Is(t)
+	}
+	return is
+}
+
+// ClientErrorWithCause returns the client error along with its cause, such as the failing response the service reported
+// it with, which errors.As finds so that the error can be classified by its status.
+func ClientErrorWithCause(clientErr ClientError, cause error) ClientError {
+	if impl, is := clientErr.(*clientErrorImpl); is && cause != nil {
+		withCause := *impl
+		withCause.cause = cause
+		clientErr = &withCause
+	}
+	return clientErr
+}
+
+// DecodeError creates the client error from just the code.
 func DecodeError(code edn.Element) (err error) {
 
 	if code.ElementType() == edn.IntegerType {
-		err = &clientErrorImpl{
-			err:  edn.MakeError(ErrSourceError, nil),
-			code: code.Value().(int64),
-		}
+		err = newClientError(int(code.Value().(int64)), "", "", "", nil)
 	}
 
 	return err
 }
+
+// DecodeClientError reads the error map the client service responds with, it returns a nil error when the map has no
+// `:ex-info`.
+func DecodeClientError(elem edn.Element) (clientErr ClientError, err error) {
+
+	if elem != nil && elem.ElementType() == edn.MapType {
+		var message, name, explanation string
+		var keyword edn.SymbolElement
+		var code int64
+		var data, exInfo edn.Element
+
+		err = elem.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) (e error) {
+			switch key.String() {
+			case ":message":
+				message, e = optionalString(key, value)
+			case ":ex-info":
+				exInfo = value
+			case ":ex-data":
+				data = value
+			}
+			return e
+		})
+
+		if err == nil && exInfo != nil {
+			if exInfo.ElementType() == edn.MapType {
+				err = exInfo.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) (e error) {
+					switch key.String() {
+					case ":explanation":
+						explanation, e = optionalString(key, value)
+					case ":type":
+						if value.ElementType() == edn.KeywordType {
+							keyword = value.(edn.SymbolElement)
+							name = keyword.Name()
+						} else {
+							name, e = optionalString(key, value)
+						}
+					case ":code":
+						if value.ElementType() == edn.IntegerType {
+							code = value.Value().(int64)
+						} else {
+							e = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Expected the %s to be an integer, got: %s", key.String(), value.String())
+						}
+					}
+					return e
+				})
+			} else {
+				err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Expected the :ex-info to be a map, got: %s", exInfo.String())
+			}
+
+			if err == nil {
+				if data != nil && data.ElementType() == edn.NilType {
+					data = nil
+				}
+
+				impl := newClientError(int(code), name, explanation, message, data)
+				impl.keyword = keyword
+				clientErr = impl
+			}
+		}
+	} else {
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Expected a map, got: %v", elem)
+	}
+
+	return clientErr, err
+}
+
+// optionalString reads a string or nil value.
+func optionalString(key edn.Element, value edn.Element) (str string, err error) {
+	switch value.ElementType() {
+	case edn.StringType:
+		str = value.Value().(string)
+	case edn.NilType:
+	default:
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Expected the %s to be a string, got: %s", key.String(), value.String())
+	}
+	return str, err
+}
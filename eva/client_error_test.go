@@ -0,0 +1,130 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"errors"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("client error test", func() {
+
+	decode := func(payload string) (ClientError, error) {
+		elem, err := edn.Parse(payload)
+		Ω(err).Should(BeNil())
+		return DecodeClientError(elem)
+	}
+
+	It("should decode the error map", func() {
+		clientErr, err := decode(`{
+			:message "Expected a vector of transaction statements.",
+			:ex-info {
+				:explanation "Malformed transact request.",
+				:type "IncorrectTransactSyntax",
+				:code 3000},
+			:ex-data {:statement "bad"}
+		}`)
+		Ω(err).Should(BeNil())
+		Ω(clientErr).ShouldNot(BeNil())
+
+		Ω(clientErr.ServiceMessage()).Should(BeEquivalentTo("Expected a vector of transaction statements."))
+		Ω(clientErr.Description()).Should(BeEquivalentTo("Malformed transact request."))
+		Ω(clientErr.Name()).Should(BeEquivalentTo("IncorrectTransactSyntax"))
+		Ω(clientErr.Code()).Should(BeEquivalentTo(3000))
+		Ω(clientErr.Keyword()).Should(BeNil())
+		Ω(clientErr.Data().ElementType()).Should(BeEquivalentTo(edn.MapType))
+
+		Ω(clientErr).Should(test.HaveMessage(ErrSourceError))
+		Ω(clientErr.Error()).Should(ContainSubstring("Expected a vector of transaction statements."))
+		Ω(clientErr.Error()).Should(ContainSubstring("IncorrectTransactSyntax 3000"))
+	})
+
+	It("should match the error codes", func() {
+		clientErr, err := decode(`{:message "" :ex-info {:type "IncorrectTransactSyntax" :code 3000} :ex-data ""}`)
+		Ω(err).Should(BeNil())
+
+		var asErr error = clientErr
+		Ω(errors.Is(asErr, ErrIncorrectTransactSyntax)).Should(BeTrue())
+		Ω(errors.Unwrap(asErr)).Should(BeIdenticalTo(ErrIncorrectTransactSyntax))
+
+		other := &ErrorCode{code: 1}
+		Ω(errors.Is(asErr, other)).Should(BeFalse())
+
+		var target ClientError
+		Ω(errors.As(asErr, &target)).Should(BeTrue())
+		Ω(target.Code()).Should(BeEquivalentTo(3000))
+	})
+
+	It("should fill in the catalog details", func() {
+		clientErr, err := decode(`{:ex-info {:code 3000}}`)
+		Ω(err).Should(BeNil())
+		Ω(clientErr.Name()).Should(BeEquivalentTo(ErrIncorrectTransactSyntax.Name()))
+		Ω(clientErr.Description()).Should(BeEquivalentTo(ErrIncorrectTransactSyntax.Explanation()))
+		Ω(clientErr.ServiceMessage()).Should(BeEmpty())
+		Ω(clientErr.Data()).Should(BeNil())
+		Ω(clientErr.Error()).Should(ContainSubstring("Malformed transact request."))
+	})
+
+	It("should keep the codes missing from the catalog", func() {
+		clientErr, err := decode(`{:message "boom" :ex-info {:type "SomethingNew" :code 123456} :ex-data nil}`)
+		Ω(err).Should(BeNil())
+		Ω(clientErr.Name()).Should(BeEquivalentTo("SomethingNew"))
+		Ω(clientErr.Code()).Should(BeEquivalentTo(123456))
+		Ω(clientErr.Data()).Should(BeNil())
+		Ω(errors.Unwrap(clientErr)).Should(BeNil())
+		Ω(errors.Is(clientErr, &ErrorCode{code: 123456})).Should(BeTrue())
+		Ω(errors.Is(clientErr, ErrIncorrectTransactSyntax)).Should(BeFalse())
+	})
+
+	It("should not decode a map without the exception information", func() {
+		clientErr, err := decode(`{:message "fine"}`)
+		Ω(err).Should(BeNil())
+		Ω(clientErr).Should(BeNil())
+	})
+
+	It("should fail with an invalid error map", func() {
+		for _, payload := range []string{
+			`[]`,
+			`{:message 1 :ex-info {:code 1}}`,
+			`{:ex-info []}`,
+			`{:ex-info {:code "1"}}`,
+			`{:ex-info {:type 1}}`,
+		} {
+			clientErr, err := decode(payload)
+			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+			Ω(clientErr).Should(BeNil())
+		}
+
+		_, err := DecodeClientError(nil)
+		Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+	})
+
+	It("should decode just the code", func() {
+		err := DecodeError(edn.NewIntegerElement(3000))
+		Ω(errors.Is(err, ErrIncorrectTransactSyntax)).Should(BeTrue())
+		Ω(DecodeError(edn.NewStringElement("3000"))).Should(BeNil())
+	})
+
+	It("should keep the keyword type", func() {
+		clientErr, err := decode(`{:message "" :ex-info {:type :incorrect-transact-syntax :code 3000} :ex-data nil}`)
+		Ω(err).Should(BeNil())
+		Ω(clientErr.Keyword().String()).Should(BeEquivalentTo(":incorrect-transact-syntax"))
+		Ω(clientErr.Name()).Should(BeEquivalentTo("incorrect-transact-syntax"))
+	})
+})
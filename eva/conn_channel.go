@@ -14,6 +14,8 @@
 
 package eva
 
+import "context"
+
 const (
 	// ConnectionReferenceType defines a new connection reference.
 	ConnectionReferenceType ChannelType = "eva.client.service/connection-ref"
@@ -24,11 +26,26 @@ type ConnectionChannel interface {
 	Channel
 
 	// Transact the data to the channel
-	Transact(data ...interface{}) (Result, error)
+	Transact(data ...interface{}) (TransactReport, error)
+
+	// TransactContext transacts the data to the channel, giving up when the context is done.
+	TransactContext(ctx context.Context, data ...interface{}) (TransactReport, error)
 
 	// LatestSnapshot returns the latest snapshot channel.
 	LatestSnapshot() (SnapshotChannel, error)
 
-	// AsOfSnapshot returns the snapshot channel as of the rules provided.
-	AsOfSnapshot(interface{}) (SnapshotChannel, error)
+	// AsOfSnapshot returns the snapshot channel as of a t, the entity id of a transaction or a time.Time, the options
+	// set the view of the snapshot.
+	AsOfSnapshot(asOf interface{}, options ...SnapshotOption) (SnapshotChannel, error)
+
+	// HistorySnapshot returns the latest snapshot channel that sees all the facts ever asserted or retracted.
+	HistorySnapshot() (SnapshotChannel, error)
+
+	// SinceSnapshot returns the latest snapshot channel restricted to the facts added after the basis, which can be a
+	// t, the entity id of a transaction or a time.Time.
+	SinceSnapshot(basis interface{}) (SnapshotChannel, error)
+
+	// Subscribe streams the basis t of the database from the t on, polling the latest snapshot until the context is
+	// done.
+	Subscribe(ctx context.Context, fromT int64, options ...SubscribeOption) (Subscription, error)
 }
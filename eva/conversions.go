@@ -14,7 +14,11 @@
 
 package eva
 
-import "github.com/Workiva/eva-client-go/edn"
+import (
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
 
 func decodeSerializable(item interface{}) (ser edn.Serializable, err error) {
 
@@ -48,6 +52,12 @@ func decodeSerializable(item interface{}) (ser edn.Serializable, err error) {
 		case rawIntImpl:
 			ser = val
 			bad = false
+		case time.Time:
+			ser = edn.NewInstantElement(val)
+			bad = false
+		case edn.Serializable:
+			ser = val
+			bad = false
 		}
 
 		if bad {
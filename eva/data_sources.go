@@ -0,0 +1,126 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrInvalidQueryInputs defines the error for parameters that do not line up with the `:in` of a query.
+	ErrInvalidQueryInputs = edn.ErrorMessage("Invalid query inputs")
+
+	// DefaultDataSource is the data source a query uses when it does not name one.
+	DefaultDataSource = "$"
+)
+
+// DataSources binds the named data sources of a query, such as `$a` or `$b`, to the snapshots or connections they
+// stand for. It is handed to SnapshotChannel.Query along with the other parameters:
+//
+//	snap.Query(`[:find ?e :in $a $b ?name :where [$a ?e :book/title ?name] [$b ?e :book/year 2017]]`,
+//	    eva.DataSources{"$a": before, "$b": after}, "\"First Book\"")
+type DataSources map[string]Channel
+
+// queryInputs returns the inputs of the query, a query without `:in` only has the default data source.
+func queryInputs(query interface{}) (inputs []edn.Element, err error) {
+
+	var elem edn.Element
+	if elem, err = parseQuery(query, ErrInvalidQueryInputs); err == nil {
+		var found bool
+		if inputs, found, err = clauseOf(elem, ":in", ErrInvalidQueryInputs); err == nil && !found {
+			var source edn.Element
+			if source, err = edn.NewSymbolElement(DefaultDataSource); err == nil {
+				inputs = []edn.Element{source}
+			}
+		}
+	}
+
+	return inputs, err
+}
+
+// bindInputs lines the parameters up with the inputs of the query. The default data source is bound to the channel,
+// the named data sources to the channels of the DataSources parameters, and the other inputs take the remaining
+// parameters in order. Channels are sent as their references.
+func bindInputs(query interface{}, channel Channel, parameters []interface{}) (args []interface{}, err error) {
+
+	named := DataSources{}
+	var positional []interface{}
+	for _, param := range parameters {
+		switch p := param.(type) {
+		case DataSources:
+			for name, source := range p {
+				named[name] = source
+			}
+		case Channel:
+			positional = append(positional, p.Reference())
+		default:
+			positional = append(positional, p)
+		}
+	}
+
+	var inputs []edn.Element
+	inputs, err = queryInputs(query)
+
+	bound := map[string]bool{}
+	for _, input := range inputs {
+		if err != nil {
+			break
+		}
+
+		name := input.String()
+		source, isNamed := named[name]
+		switch {
+		case isSymbol(input, DefaultDataSource):
+			args = append(args, channel.Reference())
+		case isNamed && input.ElementType() == edn.SymbolType:
+			if source != nil {
+				bound[name] = true
+				args = append(args, source.Reference())
+			} else {
+				err = edn.MakeErrorWithFormat(ErrInvalidQueryInputs, "No channel for the data source %s", name)
+			}
+		case len(positional) > 0:
+			args = append(args, positional[0])
+			positional = positional[1:]
+		default:
+			err = edn.MakeErrorWithFormat(ErrInvalidQueryInputs, "No parameter for the input %s", name)
+		}
+	}
+
+	if err == nil && len(positional) > 0 {
+		err = edn.MakeErrorWithFormat(ErrInvalidQueryInputs, "%d parameters are not inputs of the query", len(positional))
+	}
+
+	if err == nil && len(bound) < len(named) {
+		var unknown []string
+		for name := range named {
+			if !bound[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		err = edn.MakeErrorWithFormat(ErrInvalidQueryInputs, "Unknown data sources: %s", strings.Join(unknown, ", "))
+	}
+
+	if err != nil {
+		args = nil
+	}
+
+	return args, err
+}
@@ -0,0 +1,137 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data sources test", func() {
+
+	var source *mockSource
+
+	snapshot := func(label string, asOf int64) SnapshotChannel {
+		snap, err := NewBaseSnapshotChannel(
+			edn.NewStringElement(label),
+			source,
+			func(ctx context.Context, pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (Result, error) {
+				return newMockResult(), nil
+			},
+			func(ctx context.Context, function edn.Serializable, parameters ...interface{}) (Result, error) {
+				return newMockResult(), nil
+			},
+			edn.NewIntegerElement(asOf))
+		Ω(err).Should(BeNil())
+		return snap
+	}
+
+	BeforeEach(func() {
+		source = &mockSource{}
+	})
+
+	Context("reading the inputs", func() {
+		It("should default to the default data source", func() {
+			inputs, err := queryInputs("[:find ?e :where [?e :db/ident]]")
+			Ω(err).Should(BeNil())
+			Ω(inputs).Should(HaveLen(1))
+			Ω(inputs[0].String()).Should(BeEquivalentTo(DefaultDataSource))
+		})
+
+		It("should read the inputs of both forms", func() {
+			inputs, err := queryInputs("[:find ?e :in $ [?t ...] % :where [?e :book/title ?t]]")
+			Ω(err).Should(BeNil())
+			Ω(inputs).Should(HaveLen(3))
+			Ω(inputs[1].String()).Should(BeEquivalentTo("[?t ...]"))
+
+			inputs, err = queryInputs("{:find [?e] :in [$a $b] :where [[$a ?e :book/title]]}")
+			Ω(err).Should(BeNil())
+			Ω(inputs).Should(HaveLen(2))
+			Ω(inputs[1].String()).Should(BeEquivalentTo("$b"))
+		})
+
+		It("should reject the queries it cannot read", func() {
+			_, err := queryInputs(42)
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+
+			_, err = queryInputs("{:find [?e] :in $}")
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+		})
+	})
+
+	Context("querying a snapshot", func() {
+		It("should bind the default data source to the snapshot", func() {
+			snap := snapshot("label", 1)
+
+			_, err := snap.Query("[:find ?e :where [?e :db/ident]]")
+			Ω(err).Should(BeNil())
+			Ω(source.query).Should(BeEquivalentTo("[:find ?e :where [?e :db/ident]]"))
+			Ω(source.parameters).Should(Equal([]interface{}{snap.Reference()}))
+
+			_, err = snap.Query("[:find ?e :in ?t $ :where [?e :book/title ?t]]", "\"First Book\"")
+			Ω(err).Should(BeNil())
+			Ω(source.parameters).Should(Equal([]interface{}{"\"First Book\"", snap.Reference()}))
+		})
+
+		It("should bind the named data sources", func() {
+			snap := snapshot("label", 3)
+			before := snapshot("label", 1)
+			after := snapshot("other", 2)
+
+			_, err := snap.Query(
+				"[:find ?e :in $ $a ?t $b :where [$a ?e :book/title ?t] [$b ?e :book/year 2017] [?e :book/author]]",
+				DataSources{"$a": before, "$b": after},
+				"\"First Book\"")
+			Ω(err).Should(BeNil())
+			Ω(source.parameters).Should(Equal([]interface{}{
+				snap.Reference(),
+				before.Reference(),
+				"\"First Book\"",
+				after.Reference(),
+			}))
+		})
+
+		It("should send the channel parameters as references", func() {
+			snap := snapshot("label", 2)
+			other := snapshot("label", 1)
+
+			_, err := snap.QueryContext(context.Background(), "[:find ?e :in $ $other :where [$other ?e :book/title]]", other)
+			Ω(err).Should(BeNil())
+			Ω(source.parameters).Should(Equal([]interface{}{snap.Reference(), other.Reference()}))
+		})
+
+		It("should report the parameters which do not line up", func() {
+			snap := snapshot("label", 1)
+
+			_, err := snap.Query("[:find ?e :in $ ?t :where [?e :book/title ?t]]")
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+
+			_, err = snap.Query("[:find ?e :where [?e :book/title]]", "\"First Book\"")
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+
+			_, err = snap.Query("[:find ?e :where [?e :book/title]]", DataSources{"$a": snap})
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+
+			_, err = snap.Query("[:find ?e :in $a :where [$a ?e :book/title]]", DataSources{"$a": nil})
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryInputs))
+
+			Ω(source.query).Should(BeNil())
+		})
+	})
+})
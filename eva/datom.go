@@ -0,0 +1,113 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"fmt"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// DatomTag defines the tag the service puts on the datoms in the transaction data.
+	DatomTag = "datom"
+
+	// ErrInvalidDatom defines the error for a datom that could not be read.
+	ErrInvalidDatom = edn.ErrorMessage("Invalid datom")
+)
+
+// Datom is a single fact asserted or retracted by a transaction.
+type Datom struct {
+
+	// E is the entity id.
+	E int64
+
+	// A is the attribute id.
+	A int64
+
+	// V is the value.
+	V edn.Element
+
+	// Tx is the transaction entity id.
+	Tx int64
+
+	// Added is true for an assertion and false for a retraction.
+	Added bool
+}
+
+// String of the datom, in the same form the service sends it.
+func (datom Datom) String() string {
+	var v string
+	if datom.V != nil {
+		v = datom.V.String()
+	}
+	return fmt.Sprintf("#%s [%d %d %s %d %t]", DatomTag, datom.E, datom.A, v, datom.Tx, datom.Added)
+}
+
+// decodeDatom reads a `#datom [e a v tx added]` element.
+func decodeDatom(elem edn.Element) (datom Datom, err error) {
+	if elem.Tag() == DatomTag && elem.ElementType() == edn.VectorType {
+		coll := elem.(edn.CollectionElement)
+		if coll.Len() == 5 {
+			var parts [5]edn.Element
+			for i := range parts {
+				if parts[i], err = coll.Get(i); err != nil {
+					break
+				}
+			}
+
+			if err == nil {
+				if datom.E, err = decodeDatomInt(parts[0]); err == nil {
+					if datom.A, err = decodeDatomInt(parts[1]); err == nil {
+						if datom.Tx, err = decodeDatomInt(parts[3]); err == nil {
+							datom.V = parts[2]
+							if parts[4].ElementType() == edn.BooleanType {
+								datom.Added = parts[4].Value().(bool)
+							} else {
+								err = edn.MakeErrorWithFormat(ErrInvalidDatom, "Expected a boolean, got: %s", parts[4].String())
+							}
+						}
+					}
+				}
+			}
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidDatom, "Expected 5 parts in the datom, got: %d", coll.Len())
+		}
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidDatom, "Expected a #%s vector, got: %s", DatomTag, elem.String())
+	}
+
+	return datom, err
+}
+
+// decodeDatomInt reads the entity, attribute or transaction id of a datom.
+func decodeDatomInt(elem edn.Element) (value int64, err error) {
+	if elem.ElementType() == edn.IntegerType {
+		value = elem.Value().(int64)
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidDatom, "Expected an integer, got: %s", elem.String())
+	}
+	return value, err
+}
+
+// decodeInt reads an integer element.
+func decodeInt(elem edn.Element) (value int64, err error) {
+	if elem != nil && elem.ElementType() == edn.IntegerType {
+		value = elem.Value().(int64)
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected an integer, got: %v", elem)
+	}
+	return value, err
+}
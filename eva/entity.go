@@ -0,0 +1,349 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrEntityNotFound defines the error for an entity id, ident or lookup ref that does not resolve.
+	ErrEntityNotFound = edn.ErrorMessage("Entity not found")
+
+	// ErrInvalidAttribute defines the error for an attribute that is not a keyword.
+	ErrInvalidAttribute = edn.ErrorMessage("Invalid attribute")
+
+	// EntityIdAttribute is the attribute holding the id of an entity.
+	EntityIdAttribute = ":db/id"
+)
+
+// Entity is a lazy view of an entity in a snapshot. The attributes are pulled the first time they are asked for and
+// cached by the snapshot, so that the entities of a snapshot share what was pulled.
+type Entity interface {
+
+	// Id of the entity.
+	Id() int64
+
+	// Get returns the value of the attribute, or nil when the entity does not have it. References to other entities
+	// are returned as entities, the values of cardinality many attributes and of reverse references, such as
+	// `:book/_author`, as a []interface{} and the other values as elements.
+	Get(attr string) (interface{}, error)
+
+	// Decode decodes the value of the attribute into v with edn.Decode.
+	Decode(attr string, v interface{}) error
+
+	// Keys returns the attributes of the entity, this touches the entity.
+	Keys() ([]string, error)
+
+	// Touch pulls all the attributes of the entity at once.
+	Touch() error
+}
+
+// entityCache holds the attributes pulled for the entities of a snapshot, a nil value records a missing attribute.
+type entityCache struct {
+	lock       sync.Mutex
+	attributes map[int64]map[string]edn.Element
+	touched    map[int64]bool
+}
+
+// newEntityCache creates an empty cache.
+func newEntityCache() *entityCache {
+	return &entityCache{
+		attributes: make(map[int64]map[string]edn.Element),
+		touched:    make(map[int64]bool),
+	}
+}
+
+// lookup returns the attribute of the entity and if it is known.
+func (cache *entityCache) lookup(id int64, attr string) (value edn.Element, known bool) {
+	cache.lock.Lock()
+	value, known = cache.attributes[id][attr]
+	known = known || (cache.touched[id] && !isReverseAttribute(attr))
+	cache.lock.Unlock()
+	return value, known
+}
+
+// store records the attribute of the entity.
+func (cache *entityCache) store(id int64, attr string, value edn.Element) {
+	cache.lock.Lock()
+	if _, has := cache.attributes[id]; !has {
+		cache.attributes[id] = make(map[string]edn.Element)
+	}
+	cache.attributes[id][attr] = value
+	cache.lock.Unlock()
+}
+
+// touch records that all the attributes of the entity are known.
+func (cache *entityCache) touch(id int64) {
+	cache.lock.Lock()
+	cache.touched[id] = true
+	cache.lock.Unlock()
+}
+
+// isTouched checks if all the attributes of the entity are known.
+func (cache *entityCache) isTouched(id int64) (touched bool) {
+	cache.lock.Lock()
+	touched = cache.touched[id]
+	cache.lock.Unlock()
+	return touched
+}
+
+// keys returns the attributes known for the entity, the reverse references aside.
+func (cache *entityCache) keys(id int64) (keys []string) {
+	cache.lock.Lock()
+	for attr, value := range cache.attributes[id] {
+		if value != nil && !isReverseAttribute(attr) {
+			keys = append(keys, attr)
+		}
+	}
+	cache.lock.Unlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// entityImpl is the entity of a snapshot.
+type entityImpl struct {
+	id       int64
+	snapshot SnapshotChannel
+	cache    *entityCache
+}
+
+// newEntity resolves the id, ident or lookup ref to the entity of the snapshot.
+func newEntity(snapshot SnapshotChannel, cache *entityCache, id interface{}) (entity Entity, err error) {
+
+	var eid int64
+	var resolved bool
+	switch val := id.(type) {
+	case int:
+		eid, resolved = int64(val), true
+	case int64:
+		eid, resolved = val, true
+	case rawIntImpl:
+		eid, resolved = val.Int(), true
+	case edn.Element:
+		if val.ElementType() == edn.IntegerType {
+			eid, resolved = val.Value().(int64), true
+		}
+	}
+
+	if !resolved {
+		impl := &entityImpl{snapshot: snapshot, cache: cache}
+
+		var elem edn.Element
+		if elem, err = impl.pull(EntityIdAttribute, id); err == nil {
+			var idElem edn.Element
+			if idElem, err = attributeOf(elem, EntityIdAttribute); err == nil {
+				if idElem != nil {
+					eid, err = decodeInt(idElem)
+				} else {
+					err = edn.MakeErrorWithFormat(ErrEntityNotFound, "%v", id)
+				}
+			}
+		}
+	}
+
+	if err == nil {
+		entity = &entityImpl{
+			id:       eid,
+			snapshot: snapshot,
+			cache:    cache,
+		}
+	}
+
+	return entity, err
+}
+
+// Id of the entity.
+func (entity *entityImpl) Id() int64 {
+	return entity.id
+}
+
+// Get returns the value of the attribute, or nil when the entity does not have it.
+func (entity *entityImpl) Get(attr string) (value interface{}, err error) {
+	var elem edn.Element
+	if elem, err = entity.attribute(attr); err == nil && elem != nil {
+		value = entity.valueOf(elem)
+	}
+	return value, err
+}
+
+// Decode decodes the value of the attribute into v with edn.Decode.
+func (entity *entityImpl) Decode(attr string, v interface{}) (err error) {
+	var elem edn.Element
+	if elem, err = entity.attribute(attr); err == nil {
+		if elem != nil {
+			err = edn.Decode(elem, v)
+		} else {
+			err = edn.Decode(edn.NewNilElement(), v)
+		}
+	}
+	return err
+}
+
+// Keys returns the attributes of the entity, this touches the entity.
+func (entity *entityImpl) Keys() (keys []string, err error) {
+	if err = entity.Touch(); err == nil {
+		keys = entity.cache.keys(entity.id)
+	}
+	return keys, err
+}
+
+// Touch pulls all the attributes of the entity at once.
+func (entity *entityImpl) Touch() (err error) {
+	if !entity.cache.isTouched(entity.id) {
+		var elem edn.Element
+		if elem, err = entity.pull("*", edn.NewIntegerElement(entity.id)); err == nil {
+			if err = entity.seed(entity.id, elem); err == nil {
+				entity.cache.touch(entity.id)
+			}
+		}
+	}
+	return err
+}
+
+// attribute returns the element of the attribute, pulling it when it is not known yet.
+func (entity *entityImpl) attribute(attr string) (value edn.Element, err error) {
+
+	var keyword edn.Element
+	if keyword, err = edn.Parse(attr); err == nil && keyword.ElementType() != edn.KeywordType {
+		err = edn.MakeErrorWithFormat(ErrInvalidAttribute, "Expected a keyword, got: %s", attr)
+	} else if err != nil {
+		err = edn.MakeError(ErrInvalidAttribute, err)
+	}
+
+	if err == nil {
+		attr = keyword.String()
+
+		var known bool
+		if value, known = entity.cache.lookup(entity.id, attr); !known {
+			var elem edn.Element
+			if elem, err = entity.pull(attr, edn.NewIntegerElement(entity.id)); err == nil {
+				if value, err = attributeOf(elem, attr); err == nil {
+					entity.cache.store(entity.id, attr, value)
+					if value != nil {
+						entity.seedValue(value)
+					}
+				}
+			}
+		}
+	}
+
+	return value, err
+}
+
+// pull the attribute, or `*`, of the entity.
+func (entity *entityImpl) pull(attr string, id interface{}) (elem edn.Element, err error) {
+
+	var result Result
+	if result, err = entity.snapshot.Pull("["+attr+"]", id); err == nil {
+		if resultErr, failed := result.Error(); failed {
+			err = resultErr
+		} else {
+			elem, err = result.Element()
+		}
+	}
+
+	if err == nil && elem.ElementType() == edn.NilType {
+		err = edn.MakeErrorWithFormat(ErrEntityNotFound, "%v", id)
+	}
+
+	return elem, err
+}
+
+// seed caches the attributes of the entity found in the map, and the ones of the entities it refers to.
+func (entity *entityImpl) seed(id int64, elem edn.Element) (err error) {
+	if elem.ElementType() == edn.MapType {
+		err = elem.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) error {
+			if attr := key.String(); attr != EntityIdAttribute {
+				entity.cache.store(id, attr, value)
+				entity.seedValue(value)
+			}
+			return nil
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrEntityNotFound, "Expected a map, got: %s", elem.String())
+	}
+	return err
+}
+
+// seedValue caches the attributes of the entities the value refers to.
+func (entity *entityImpl) seedValue(value edn.Element) {
+	if id, is := entityIdOf(value); is {
+		_ = entity.seed(id, value)
+	} else if children, isSeq := sequenceOf(value); isSeq {
+		for _, child := range children {
+			entity.seedValue(child)
+		}
+	}
+}
+
+// valueOf turns the element into the value of an attribute.
+func (entity *entityImpl) valueOf(elem edn.Element) (value interface{}) {
+	if id, is := entityIdOf(elem); is {
+		value = &entityImpl{
+			id:       id,
+			snapshot: entity.snapshot,
+			cache:    entity.cache,
+		}
+	} else if children, isSeq := sequenceOf(elem); isSeq {
+		values := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			values = append(values, entity.valueOf(child))
+		}
+		value = values
+	} else {
+		value = elem
+	}
+
+	return value
+}
+
+// entityIdOf returns the id of a `{:db/id 123 ...}` map.
+func entityIdOf(elem edn.Element) (id int64, is bool) {
+	if elem.ElementType() == edn.MapType {
+		if idElem, err := attributeOf(elem, EntityIdAttribute); err == nil && idElem != nil {
+			var e error
+			id, e = decodeInt(idElem)
+			is = e == nil
+		}
+	}
+	return id, is
+}
+
+// attributeOf returns the value of the attribute in the pulled map, or nil when it is not there.
+func attributeOf(elem edn.Element, attr string) (value edn.Element, err error) {
+	if elem.ElementType() == edn.MapType {
+		err = elem.(edn.CollectionElement).IterateChildren(func(key edn.Element, v edn.Element) error {
+			if key.String() == attr {
+				value = v
+			}
+			return nil
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrEntityNotFound, "Expected a map, got: %s", elem.String())
+	}
+	return value, err
+}
+
+// isReverseAttribute checks if the attribute is a reverse reference, such as `:book/_author`.
+func isReverseAttribute(attr string) bool {
+	return strings.Contains(attr, "/_")
+}
@@ -0,0 +1,195 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("entity test", func() {
+
+	payloads := map[string]string{
+		`[:book/title] 1`:             `{:book/title "First Book"}`,
+		`[:book/year] 1`:              `{:book/year 2017}`,
+		`[:book/author] 1`:            `{:book/author {:db/id 2}}`,
+		`[:book/missing] 1`:           `{}`,
+		`[:person/name] 2`:            `{:person/name "James Madison"}`,
+		`[:book/_author] 2`:           `{:book/_author [{:db/id 1} {:db/id 3}]}`,
+		`[:book/title] 3`:             `{:book/title "Second Book"}`,
+		`[*] 1`:                       `{:db/id 1 :book/title "First Book" :book/year 2017 :book/author {:db/id 2 :person/name "James Madison"} :book/tags ["a" "b"]}`,
+		`[:db/id] [:book/isbn "123"]`: `{:db/id 1}`,
+		`[:db/id] [:book/isbn "999"]`: `nil`,
+	}
+
+	var pulls []string
+	var snap SnapshotChannel
+
+	BeforeEach(func() {
+		pulls = nil
+
+		var err error
+		snap, err = NewBaseSnapshotChannel(
+			edn.NewStringElement("label"),
+			&mockSource{},
+			func(ctx context.Context, pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (Result, error) {
+				p, e := pattern.Serialize(edn.EvaEdnMimeType)
+				Ω(e).Should(BeNil())
+				i, e := ids.Serialize(edn.EvaEdnMimeType)
+				Ω(e).Should(BeNil())
+
+				key := p + " " + i
+				pulls = append(pulls, key)
+
+				payload, has := payloads[key]
+				if !has {
+					return nil, fmt.Errorf("unexpected pull: %s", key)
+				}
+				return newMockPayloadResult(payload), nil
+			},
+			nil,
+			nil)
+		Ω(err).Should(BeNil())
+	})
+
+	It("should pull the attributes lazily", func() {
+		book, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+		Ω(book.Id()).Should(BeEquivalentTo(1))
+		Ω(pulls).Should(BeEmpty())
+
+		title, err := book.Get(":book/title")
+		Ω(err).Should(BeNil())
+		Ω(title.(edn.Element).Value()).Should(BeEquivalentTo("First Book"))
+		Ω(pulls).Should(HaveLen(1))
+
+		var year int
+		Ω(book.Decode(":book/year", &year)).Should(Succeed())
+		Ω(year).Should(BeEquivalentTo(2017))
+
+		_, err = book.Get(":book/title")
+		Ω(err).Should(BeNil())
+		Ω(pulls).Should(HaveLen(2))
+	})
+
+	It("should report the missing attributes once", func() {
+		book, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+
+		missing, err := book.Get(":book/missing")
+		Ω(err).Should(BeNil())
+		Ω(missing).Should(BeNil())
+
+		var str string
+		Ω(book.Decode(":book/missing", &str)).Should(Succeed())
+		Ω(str).Should(BeEmpty())
+		Ω(pulls).Should(HaveLen(1))
+	})
+
+	It("should navigate the references", func() {
+		book, err := snap.Entity(edn.NewIntegerElement(1))
+		Ω(err).Should(BeNil())
+
+		author, err := book.Get(":book/author")
+		Ω(err).Should(BeNil())
+		Ω(author).Should(BeAssignableToTypeOf(&entityImpl{}))
+		Ω(author.(Entity).Id()).Should(BeEquivalentTo(2))
+
+		name, err := author.(Entity).Get(":person/name")
+		Ω(err).Should(BeNil())
+		Ω(name.(edn.Element).Value()).Should(BeEquivalentTo("James Madison"))
+
+		books, err := author.(Entity).Get(":book/_author")
+		Ω(err).Should(BeNil())
+		Ω(books).Should(HaveLen(2))
+
+		var titles []string
+		for _, b := range books.([]interface{}) {
+			var title string
+			Ω(b.(Entity).Decode(":book/title", &title)).Should(Succeed())
+			titles = append(titles, title)
+		}
+		Ω(titles).Should(Equal([]string{"First Book", "Second Book"}))
+	})
+
+	It("should share the cache of the snapshot", func() {
+		first, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+		_, err = first.Get(":book/title")
+		Ω(err).Should(BeNil())
+
+		second, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+		_, err = second.Get(":book/title")
+		Ω(err).Should(BeNil())
+
+		Ω(pulls).Should(HaveLen(1))
+	})
+
+	It("should touch all the attributes", func() {
+		book, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+
+		keys, err := book.Keys()
+		Ω(err).Should(BeNil())
+		Ω(keys).Should(Equal([]string{":book/author", ":book/tags", ":book/title", ":book/year"}))
+
+		tags, err := book.Get(":book/tags")
+		Ω(err).Should(BeNil())
+		Ω(tags).Should(HaveLen(2))
+
+		missing, err := book.Get(":book/missing")
+		Ω(err).Should(BeNil())
+		Ω(missing).Should(BeNil())
+
+		author, err := book.Get(":book/author")
+		Ω(err).Should(BeNil())
+		name, err := author.(Entity).Get(":person/name")
+		Ω(err).Should(BeNil())
+		Ω(name.(edn.Element).Value()).Should(BeEquivalentTo("James Madison"))
+
+		Ω(book.Touch()).Should(Succeed())
+		Ω(pulls).Should(Equal([]string{"[*] 1"}))
+	})
+
+	It("should resolve the lookup refs", func() {
+		book, err := snap.Entity(`[:book/isbn "123"]`)
+		Ω(err).Should(BeNil())
+		Ω(book.Id()).Should(BeEquivalentTo(1))
+
+		_, err = snap.Entity(`[:book/isbn "999"]`)
+		Ω(err).Should(test.HaveMessage(ErrEntityNotFound))
+	})
+
+	It("should reject the bad attributes", func() {
+		book, err := snap.Entity(1)
+		Ω(err).Should(BeNil())
+
+		_, err = book.Get("book/title")
+		Ω(err).Should(test.HaveMessage(ErrInvalidAttribute))
+
+		_, err = book.Get("[:book/title")
+		Ω(err).Should(test.HaveMessage(ErrInvalidAttribute))
+
+		_, err = book.Get(":book/unknown")
+		Ω(err).ShouldNot(BeNil())
+		Ω(pulls).Should(Equal([]string{"[:book/unknown] 1"}))
+	})
+})
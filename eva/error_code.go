@@ -0,0 +1,94 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ErrorCode is an Eva error code. The client errors carrying the code match it with errors.Is:
+//
+//	if errors.Is(err, eva.ErrIncorrectTransactSyntax) {
+//		// fix the transaction
+//	}
+type ErrorCode struct {
+	code        int
+	name        string
+	explanation string
+}
+
+// ErrIncorrectTransactSyntax is returned for a malformed transaction. It is the error the client service documents in
+// the error map it responds with, see ClientError, the codes it does not document can be registered by the
+// applications that meet them.
+var ErrIncorrectTransactSyntax = RegisterErrorCode(3000, "IncorrectTransactSyntax", "Malformed transact request.")
+
+// errorCodes is the catalog of the known error codes.
+var errorCodes = map[int]*ErrorCode{}
+
+// RegisterErrorCode adds the error code to the catalog and returns it, registering a code twice returns the error code
+// registered first. Like the source factories, the codes are meant to be registered while the program initializes.
+func RegisterErrorCode(code int, name string, explanation string) (errorCode *ErrorCode) {
+	var has bool
+	if errorCode, has = errorCodes[code]; !has {
+		errorCode = &ErrorCode{
+			code:        code,
+			name:        name,
+			explanation: explanation,
+		}
+		errorCodes[code] = errorCode
+	}
+
+	return errorCode
+}
+
+// LookupErrorCode returns the error code from the catalog.
+func LookupErrorCode(code int) (errorCode *ErrorCode, has bool) {
+	errorCode, has = errorCodes[code]
+	return errorCode, has
+}
+
+// ErrorCodes returns the catalog of error codes, ordered by code.
+func ErrorCodes() (codes []*ErrorCode) {
+	for _, code := range errorCodes {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].code < codes[j].code
+	})
+
+	return codes
+}
+
+// Code returns the numeric code.
+func (code *ErrorCode) Code() int {
+	return code.code
+}
+
+// Name returns the name of the error type, i.e. "IncorrectTransactSyntax".
+func (code *ErrorCode) Name() string {
+	return code.name
+}
+
+// Explanation returns the explanation of the error type.
+func (code *ErrorCode) Explanation() string {
+	return code.explanation
+}
+
+// Error returns the error message.
+func (code *ErrorCode) Error() string {
+	return fmt.Sprintf("[%s %d]: %s", code.name, code.code, code.explanation)
+}
@@ -0,0 +1,82 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Workiva/eva-client-go/edn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("error code test", func() {
+
+	It("should describe the code", func() {
+		Ω(ErrIncorrectTransactSyntax.Code()).Should(BeEquivalentTo(3000))
+		Ω(ErrIncorrectTransactSyntax.Name()).Should(BeEquivalentTo("IncorrectTransactSyntax"))
+		Ω(ErrIncorrectTransactSyntax.Explanation()).Should(BeEquivalentTo("Malformed transact request."))
+		Ω(ErrIncorrectTransactSyntax.Error()).Should(BeEquivalentTo("[IncorrectTransactSyntax 3000]: Malformed transact request."))
+	})
+
+	It("should decode each known code", func() {
+		registered := RegisterErrorCode(-42, "TestOnly", "Registered by the tests.")
+		defer delete(errorCodes, -42)
+
+		known := []*ErrorCode{ErrIncorrectTransactSyntax, registered}
+
+		for _, code := range known {
+			elem, err := edn.Parse(fmt.Sprintf(`{:message "failed" :ex-info {:code %d} :ex-data nil}`, code.Code()))
+			Ω(err).Should(BeNil())
+
+			clientErr, err := DecodeClientError(elem)
+			Ω(err).Should(BeNil())
+			Ω(clientErr.Name()).Should(BeEquivalentTo(code.Name()))
+			Ω(clientErr.Description()).Should(BeEquivalentTo(code.Explanation()))
+			Ω(errors.Is(clientErr, code)).Should(BeTrue())
+
+			for _, other := range known {
+				if other != code {
+					Ω(errors.Is(clientErr, other)).Should(BeFalse())
+				}
+			}
+		}
+
+		Ω(ErrorCodes()).Should(HaveLen(len(known)))
+	})
+
+	It("should keep the catalog", func() {
+		known, has := LookupErrorCode(3000)
+		Ω(has).Should(BeTrue())
+		Ω(known).Should(BeIdenticalTo(ErrIncorrectTransactSyntax))
+
+		_, has = LookupErrorCode(-42)
+		Ω(has).Should(BeFalse())
+
+		Ω(RegisterErrorCode(3000, "Other", "Other")).Should(BeIdenticalTo(ErrIncorrectTransactSyntax))
+
+		registered := RegisterErrorCode(-42, "TestOnly", "Registered by the tests.")
+		defer delete(errorCodes, -42)
+
+		known, has = LookupErrorCode(-42)
+		Ω(has).Should(BeTrue())
+		Ω(known).Should(BeIdenticalTo(registered))
+
+		codes := ErrorCodes()
+		Ω(codes[0]).Should(BeIdenticalTo(registered))
+		Ω(codes).Should(ContainElement(ErrIncorrectTransactSyntax))
+	})
+})
@@ -21,14 +21,16 @@ import (
 func init() {
 	PanicOnError(func() (err error) {
 		if exInfoKeyword, err = edn.NewKeywordElement("ex-info"); err == nil {
-			codeKeyword, err = edn.NewKeywordElement("code")
+			responseParser, err = edn.NewParser(edn.WithLimits(edn.DefaultLimits))
 		}
 		return err
 	})
 }
 
 var exInfoKeyword edn.SymbolElement
-var codeKeyword edn.SymbolElement
+
+// responseParser reads the payloads returned by the server, the limits guard against hostile or broken responses.
+var responseParser edn.Parser
 
 // ErrorExaminer will retrieve the error from the payload
 type ErrorExaminer func([]byte) error
@@ -50,23 +52,52 @@ func GetErrorExaminer(serializer edn.Serializer) (examiner ErrorExaminer, err er
 	return examiner, err
 }
 
+// ElementExaminer will retrieve the error from the parsed payload
+type ElementExaminer func(edn.Element) error
+
+// GetElementExaminer will return the element examiner for the payloads of the serializer, or an error
+func GetElementExaminer(serializer edn.Serializer) (examiner ElementExaminer, err error) {
+
+	if serializer != nil {
+		switch serializer.MimeType() {
+		case edn.EvaEdnMimeType:
+			examiner = ExamineElement
+		default:
+			err = edn.MakeError(ErrInvalidSerializer, serializer)
+		}
+	} else {
+		examiner = ExamineElement
+	}
+
+	return examiner, err
+}
+
 // ednErrorExaminer will examine the payload for an error.
 func ednErrorExaminer(body []byte) (err error) {
 	var elem edn.Element
-	if elem, err = edn.Parse(string(body)); elem != nil {
-		if elem.ElementType() == edn.MapType {
-			coll := elem.(edn.CollectionElement)
-			var exceptionElem edn.Element
-			if exceptionElem, err = coll.Get(exInfoKeyword); err == nil {
-				if elem.ElementType() == edn.MapType {
-					innerMap := exceptionElem.(edn.CollectionElement)
-
-					var code edn.Element
-					if code, err = innerMap.Get(codeKeyword); err == nil {
-						err = DecodeError(code)
-					}
+	if elem, err = responseParser.Parse(string(body)); err == nil {
+		err = ExamineElement(elem)
+	}
+	return err
+}
+
+// ExamineElement returns the client error the parsed payload reports, nil for a successful result, so that a result
+// which already parsed its payload does not parse it again.
+func ExamineElement(elem edn.Element) (err error) {
+	if elem != nil && elem.ElementType() == edn.MapType {
+		coll := elem.(edn.CollectionElement)
+		var exceptionElem edn.Element
+		if exceptionElem, err = coll.Get(exInfoKeyword); err == nil {
+			if exceptionElem.ElementType() == edn.MapType {
+				var clientErr ClientError
+				if clientErr, err = DecodeClientError(elem); err == nil {
+					err = clientErr
 				}
 			}
+		} else if edn.ErrNoValue.IsEquivalent(err) {
+
+			// a map without the exception information is a successful result.
+			err = nil
 		}
 	}
 	return err
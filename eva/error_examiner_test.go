@@ -15,6 +15,8 @@
 package eva
 
 import (
+	"strings"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/test"
 	. "github.com/onsi/ginkgo"
@@ -45,6 +47,27 @@ var _ = Describe("Error examiner", func() {
 		})
 	})
 
+	Context("GetElementExaminer", func() {
+
+		It("with nil", func() {
+			examiner, err := GetElementExaminer(nil)
+			Ω(examiner).ShouldNot(BeNil())
+			Ω(err).Should(BeNil())
+		})
+
+		It("with bad mime type", func() {
+			examiner, err := GetElementExaminer(edn.SerializerMimeType("nothing"))
+			Ω(examiner).Should(BeNil())
+			Ω(err).Should(test.HaveMessage(ErrInvalidSerializer))
+		})
+
+		It("with good mime type", func() {
+			examiner, err := GetElementExaminer(edn.EvaEdnMimeType)
+			Ω(examiner).ShouldNot(BeNil())
+			Ω(err).Should(BeNil())
+		})
+	})
+
 	Context("ednErrorExaminer", func() {
 		It("with nil", func() {
 			err := ednErrorExaminer(nil)
@@ -56,6 +79,16 @@ var _ = Describe("Error examiner", func() {
 			Ω(err).ShouldNot(BeNil())
 		})
 
+		It("with a successful result", func() {
+			err := ednErrorExaminer([]byte("{:tempids {-1 8796093023233}}"))
+			Ω(err).Should(BeNil())
+		})
+
+		It("with a payload past the limits", func() {
+			err := ednErrorExaminer([]byte(strings.Repeat("[", edn.DefaultLimits.MaxDepth+1)))
+			Ω(err).Should(test.HaveMessage(edn.ErrLimitExceeded))
+		})
+
 		It("with empty", func() {
 
 			example := []byte(`{
@@ -75,6 +108,20 @@ var _ = Describe("Error examiner", func() {
 			Ω(clientErr).ShouldNot(BeNil())
 
 			Ω(clientErr.Error()).Should(ContainSubstring(ErrSourceError.Message()))
+			Ω(clientErr.Code()).Should(BeEquivalentTo(3000))
+			Ω(clientErr.Name()).Should(BeEquivalentTo("IncorrectTransactSyntax"))
+		})
+
+		It("with a parsed payload", func() {
+			elem, err := edn.Parse(`{:message "" :ex-info {:code 3000} :ex-data nil}`)
+			Ω(err).Should(BeNil())
+			Ω(ExamineElement(elem)).Should(BeAssignableToTypeOf(&clientErrorImpl{}))
+			Ω(ExamineElement(nil)).Should(BeNil())
+		})
+
+		It("with an ex-info that is not a map", func() {
+			err := ednErrorExaminer([]byte(`{:message "" :ex-info "no"}`))
+			Ω(err).Should(BeNil())
 		})
 	})
 })
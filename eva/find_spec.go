@@ -0,0 +1,228 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrInvalidFindSpec defines the error for a query without a usable `:find`.
+	ErrInvalidFindSpec = edn.ErrorMessage("Invalid find spec")
+)
+
+// FindShape is the shape of the result a find spec asks for.
+type FindShape int
+
+const (
+
+	// UnknownShape is the shape of queries whose find spec could not be read.
+	UnknownShape FindShape = iota
+
+	// RelationShape is the shape of `:find ?a ?b`, a collection of tuples.
+	RelationShape
+
+	// CollectionShape is the shape of `:find [?a ...]`, a collection of values.
+	CollectionShape
+
+	// TupleShape is the shape of `:find [?a ?b]`, a single tuple.
+	TupleShape
+
+	// ScalarShape is the shape of `:find ?a .`, a single value.
+	ScalarShape
+)
+
+// findShapeNames holds the names of the shapes.
+var findShapeNames = map[FindShape]string{
+	UnknownShape:    "unknown",
+	RelationShape:   "relation",
+	CollectionShape: "collection",
+	TupleShape:      "tuple",
+	ScalarShape:     "scalar",
+}
+
+// String of the shape.
+func (shape FindShape) String() string {
+	return findShapeNames[shape]
+}
+
+// FindSpec is the `:find` of a query.
+type FindSpec struct {
+
+	// Shape of the result.
+	Shape FindShape
+
+	// Elements found, the variables, aggregates and pull expressions, in order.
+	Elements []edn.Element
+}
+
+// Columns returns the number of values in each row of the result.
+func (spec FindSpec) Columns() (columns int) {
+	switch spec.Shape {
+	case RelationShape, TupleShape:
+		columns = len(spec.Elements)
+	case CollectionShape, ScalarShape:
+		columns = 1
+	}
+	return columns
+}
+
+// ParseFindSpec reads the find spec of the query, which can be a string or an element in either the list form
+// `[:find ?a :where ...]` or the map form `{:find [?a] :where [...]}`.
+func ParseFindSpec(query interface{}) (spec FindSpec, err error) {
+
+	var elem edn.Element
+	elem, err = parseQuery(query, ErrInvalidFindSpec)
+
+	var items []edn.Element
+	if err == nil {
+		items, err = findItems(elem)
+	}
+
+	if err == nil {
+		spec, err = shapeOf(items)
+	}
+
+	return spec, err
+}
+
+// parseQuery reads the query, which can be a string or an element, the errors are of the kind provided.
+func parseQuery(query interface{}, kind edn.ErrorMessage) (elem edn.Element, err error) {
+
+	switch q := query.(type) {
+	case string:
+		elem, err = edn.Parse(q)
+	case rawStringImpl:
+		elem, err = edn.Parse(q.String())
+	case edn.Element:
+		elem = q
+	case *Query:
+		elem, err = q.Element()
+	default:
+		err = edn.MakeErrorWithFormat(kind, "Unsupported query type: %T", query)
+	}
+
+	return elem, err
+}
+
+// clauseOf returns the elements of a clause of the query, such as `:find` or `:in`, and if the query has the clause.
+// The errors are of the kind provided.
+func clauseOf(query edn.Element, clause string, kind edn.ErrorMessage) (items []edn.Element, found bool, err error) {
+
+	switch query.ElementType() {
+	case edn.VectorType, edn.ListType:
+		inClause := false
+		children, _ := sequenceOf(query)
+		for _, child := range children {
+			if child.ElementType() == edn.KeywordType {
+				inClause = child.String() == clause
+				found = found || inClause
+			} else if inClause {
+				items = append(items, child)
+			}
+		}
+	case edn.MapType:
+		err = query.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) (e error) {
+			if key.String() == clause {
+				found = true
+				var is bool
+				if items, is = sequenceOf(value); !is {
+					e = edn.MakeErrorWithFormat(kind, "Expected the %s to be a vector, got: %s", clause, value.String())
+				}
+			}
+			return e
+		})
+	}
+
+	return items, found, err
+}
+
+// findItems returns the elements following `:find`.
+func findItems(query edn.Element) (items []edn.Element, err error) {
+
+	var found bool
+	items, found, err = clauseOf(query, ":find", ErrInvalidFindSpec)
+
+	if err == nil && !found {
+		err = edn.MakeErrorWithFormat(ErrInvalidFindSpec, "No :find in the query: %s", query.String())
+	}
+
+	return items, err
+}
+
+// shapeOf works out the shape from the find elements.
+func shapeOf(items []edn.Element) (spec FindSpec, err error) {
+
+	switch {
+	case len(items) == 0:
+		err = edn.MakeError(ErrInvalidFindSpec, "Nothing to find")
+
+	case len(items) == 2 && isSymbol(items[1], "."):
+		spec = FindSpec{Shape: ScalarShape, Elements: items[:1]}
+
+	case len(items) == 1 && items[0].ElementType() == edn.VectorType:
+		inner, _ := sequenceOf(items[0])
+		if len(inner) == 2 && isSymbol(inner[1], "...") {
+			spec = FindSpec{Shape: CollectionShape, Elements: inner[:1]}
+		} else {
+			spec = FindSpec{Shape: TupleShape, Elements: inner}
+		}
+
+	default:
+		spec = FindSpec{Shape: RelationShape, Elements: items}
+	}
+
+	if err == nil {
+		if len(spec.Elements) == 0 {
+			err = edn.MakeError(ErrInvalidFindSpec, "Nothing to find")
+		}
+
+		for _, elem := range spec.Elements {
+			if isSymbol(elem, ".") || isSymbol(elem, "...") {
+				err = edn.MakeErrorWithFormat(ErrInvalidFindSpec, "Misplaced '%s'", elem.String())
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		spec = FindSpec{}
+	}
+
+	return spec, err
+}
+
+// isSymbol checks if the element is the symbol.
+func isSymbol(elem edn.Element, symbol string) bool {
+	return elem.ElementType() == edn.SymbolType && elem.String() == symbol
+}
+
+// sequenceOf returns the children of a list, vector or set.
+func sequenceOf(elem edn.Element) (children []edn.Element, is bool) {
+	if elem != nil {
+		switch elem.ElementType() {
+		case edn.ListType, edn.VectorType, edn.SetType:
+			is = true
+			children = make([]edn.Element, 0, elem.(edn.CollectionElement).Len())
+			_ = elem.(edn.CollectionElement).IterateChildren(func(_ edn.Element, child edn.Element) error {
+				children = append(children, child)
+				return nil
+			})
+		}
+	}
+
+	return children, is
+}
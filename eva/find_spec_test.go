@@ -0,0 +1,108 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("find spec test", func() {
+
+	Context("with the list form", func() {
+		for query, shape := range map[string]FindShape{
+			"[:find ?a ?b :where [?e :a ?a] [?e :b ?b]]":       RelationShape,
+			"[:find ?a :in $ ?x :where [?e :a ?a]]":            RelationShape,
+			"[:find [?a ...] :where [?e :a ?a]]":               CollectionShape,
+			"[:find [?a ?b] :where [?e :a ?a] [?e :b ?b]]":     TupleShape,
+			"[:find ?a . :where [?e :a ?a]]":                   ScalarShape,
+			"[:find (count ?e) . :where [?e :a]]":              ScalarShape,
+			"[:find (pull ?e [*]) ?a :where [?e :a ?a]]":       RelationShape,
+			"(:find ?a :where [?e :a ?a])":                     RelationShape,
+			"{:find [?a .] :where [[?e :a ?a]]}":               ScalarShape,
+			"{:find [[?a ...]] :where [[?e :a ?a]]}":           CollectionShape,
+			"{:find [?a ?b] :in [$] :where [[?e :a ?a]]}":      RelationShape,
+			"[:find [(min ?a) (max ?a)] :where [?e :year ?a]]": TupleShape,
+		} {
+			query, shape := query, shape
+			It("should read "+query, func() {
+				spec, err := ParseFindSpec(query)
+				Ω(err).Should(BeNil())
+				Ω(spec.Shape).Should(BeEquivalentTo(shape))
+				Ω(spec.Shape.String()).Should(BeEquivalentTo(shape.String()))
+			})
+		}
+	})
+
+	It("should list the elements and columns", func() {
+		spec, err := ParseFindSpec("[:find ?a (count ?b) :where [?e :a ?a] [?e :b ?b]]")
+		Ω(err).Should(BeNil())
+		Ω(spec.Elements).Should(HaveLen(2))
+		Ω(spec.Elements[0].String()).Should(BeEquivalentTo("?a"))
+		Ω(spec.Elements[1].ElementType()).Should(BeEquivalentTo(edn.ListType))
+		Ω(spec.Columns()).Should(BeEquivalentTo(2))
+
+		spec, err = ParseFindSpec("[:find [?a ...] :where [?e :a ?a]]")
+		Ω(err).Should(BeNil())
+		Ω(spec.Elements).Should(HaveLen(1))
+		Ω(spec.Columns()).Should(BeEquivalentTo(1))
+
+		Ω(FindSpec{}.Columns()).Should(BeZero())
+	})
+
+	It("should read the elements and raw strings", func() {
+		elem, err := edn.Parse("[:find ?a . :where [?e :a ?a]]")
+		Ω(err).Should(BeNil())
+
+		spec, err := ParseFindSpec(elem)
+		Ω(err).Should(BeNil())
+		Ω(spec.Shape).Should(BeEquivalentTo(ScalarShape))
+
+		spec, err = ParseFindSpec(RawString("[:find [?a ?b] :where [?e :a ?a]]"))
+		Ω(err).Should(BeNil())
+		Ω(spec.Shape).Should(BeEquivalentTo(TupleShape))
+	})
+
+	Context("with an invalid find spec", func() {
+		for _, query := range []string{
+			"[:where [?e :a ?a]]",
+			"[:find :where [?e :a ?a]]",
+			"[:find ?a ... :where [?e :a ?a]]",
+			"[:find [?a . ?b] :where [?e :a ?a]]",
+			"[:find [] :where [?e :a ?a]]",
+			"{:find ?a}",
+			"42",
+		} {
+			query := query
+			It("should fail with "+query, func() {
+				spec, err := ParseFindSpec(query)
+				Ω(err).Should(test.HaveMessage(ErrInvalidFindSpec))
+				Ω(spec.Shape).Should(BeEquivalentTo(UnknownShape))
+			})
+		}
+
+		It("should fail with an unsupported query", func() {
+			_, err := ParseFindSpec(42)
+			Ω(err).Should(test.HaveMessage(ErrInvalidFindSpec))
+		})
+
+		It("should fail with a query that does not parse", func() {
+			_, err := ParseFindSpec("[:find ?a")
+			Ω(err).ShouldNot(BeNil())
+		})
+	})
+})
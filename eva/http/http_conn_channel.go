@@ -15,6 +15,7 @@
 package http
 
 import (
+	"context"
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/eva"
 	"net/http"
@@ -41,13 +42,13 @@ func newHttpConnChannel(label edn.Serializable, source eva.Source) (channel eva.
 }
 
 // asOfSnapshot is the implementation for getting a snapshot at a particular reference.
-func (connChan *httpConnChanImpl) asOfSnapshot(t edn.Serializable) (channel eva.SnapshotChannel, err error) {
-	return newHttpSnapChannel(connChan, t)
+func (connChan *httpConnChanImpl) asOfSnapshot(t edn.Serializable, options ...eva.SnapshotOption) (channel eva.SnapshotChannel, err error) {
+	return newHttpSnapChannel(connChan, t, options...)
 }
 
 // transact will transact an edn to the eva database.
 // Submits a transaction, blocking until a result is available.
-func (connChan *httpConnChanImpl) transact(transaction edn.Serializable) (result eva.Result, err error) {
+func (connChan *httpConnChanImpl) transact(ctx context.Context, transaction edn.Serializable) (result eva.Result, err error) {
 	form := url.Values{}
 
 	var serializer edn.Serializer
@@ -67,7 +68,7 @@ func (connChan *httpConnChanImpl) transact(transaction edn.Serializable) (result
 			switch source := connChan.Source().(type) {
 			case *httpSourceImpl:
 				uri := source.formulateUrl("transact")
-				result, err = source.call(http.MethodPost, uri, form)
+				result, err = source.call(ctx, http.MethodPost, uri, form)
 			default:
 				err = edn.MakeErrorWithFormat(ErrUnsupportedType, "source type: %T", source)
 			}
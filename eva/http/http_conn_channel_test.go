@@ -74,7 +74,11 @@ var _ = Describe("Connection Channel Test", func() {
 			Ω(source).ShouldNot(BeNil())
 
 			if httpSource, is := source.(*httpSourceImpl); is {
-				httpSource.callClient = fakeGoodCaller(edn.EvaEdnMimeType.String())
+				httpSource.callClient = fakeBodyCaller(edn.EvaEdnMimeType.String(), `{
+					:tempids {-1 8796093023233}
+					:db-before #eva.client.service/snapshot-ref {:label "test" :as-of 1}
+					:db-after #eva.client.service/snapshot-ref {:label "test" :as-of 2}
+					:tx-data (#datom [8796093023233 8 "First Book" 4398046511106 true])}`)
 				label := edn.NewStringElement("test")
 
 				channel, err := newHttpConnChannel(label, source)
@@ -84,6 +88,13 @@ var _ = Describe("Connection Channel Test", func() {
 				result, err := channel.Transact("")
 				Ω(err).Should(BeNil())
 				Ω(result).ShouldNot(BeNil())
+				Ω(result.TempIds()).Should(HaveKeyWithValue(int64(-1), int64(8796093023233)))
+				Ω(result.AfterT()).Should(BeEquivalentTo(2))
+				Ω(result.Datoms()).Should(HaveLen(1))
+
+				snap, err := result.DbAfter()
+				Ω(err).Should(BeNil())
+				Ω(*snap.AsOf()).Should(BeEquivalentTo(2))
 			} else {
 				Fail("Expected the binding to be a *httpSourceImpl")
 			}
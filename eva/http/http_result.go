@@ -15,27 +15,89 @@
 package http
 
 import (
+	"fmt"
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/eva"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
 
 	// ErrServiceError defines a service error.
 	ErrServiceError = edn.ErrorMessage("Service call failed")
+
+	// RequestIdHeader is the response header holding the id the service gave the request.
+	RequestIdHeader = "X-Request-Id"
+
+	// correlationIdHeader is the request header holding the correlation id of the tenant.
+	correlationIdHeader = "_cid"
+
+	// maxErrorBody is the most of the body shown in the message of a ResponseError.
+	maxErrorBody = 512
 )
 
+// Result is the result of a call to the eva client service, along with the details of the http response.
+type Result interface {
+	eva.Result
+
+	// StatusCode returns the http status code of the response.
+	StatusCode() int
+
+	// ContentType returns the content type of the response.
+	ContentType() string
+
+	// Duration returns the time between sending the request and reading the response.
+	Duration() time.Duration
+
+	// RequestId returns the id the service gave the request, or else the correlation id it was sent with.
+	RequestId() string
+}
+
+// ResponseError is the cause of a call the service answered with a failing status.
+type ResponseError struct {
+	code      int
+	body      []byte
+	requestId string
+}
+
+// StatusCode returns the http status code of the response.
+func (e *ResponseError) StatusCode() int {
+	return e.code
+}
+
+// Body returns the body of the response.
+func (e *ResponseError) Body() []byte {
+	return e.body
+}
+
+// RequestId returns the id of the request.
+func (e *ResponseError) RequestId() string {
+	return e.requestId
+}
+
+// Error returns the error message.
+func (e *ResponseError) Error() string {
+	body := string(e.body)
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody] + "..."
+	}
+
+	return fmt.Sprintf("status %d %s, request id: '%s', body: %s", e.code, http.StatusText(e.code), e.requestId, body)
+}
+
 type httpResult struct {
-	body        []byte
+	*eva.BaseResult
 	code        int
 	contentType string
-	examine     eva.ErrorExaminer
+	duration    time.Duration
+	requestId   string
+	examine     eva.ElementExaminer
 }
 
-func newHttpResult(req *http.Request, form url.Values, resp *http.Response) (result eva.Result, err error) {
+func newHttpResult(req *http.Request, form url.Values, resp *http.Response, started time.Time) (result eva.Result, err error) {
 
 	var data []byte
 	if resp.Body != nil {
@@ -53,16 +115,18 @@ func newHttpResult(req *http.Request, form url.Values, resp *http.Response) (res
 		serializer = edn.DefaultMimeType
 	}
 
-	var examiner eva.ErrorExaminer
+	var examiner eva.ElementExaminer
 	if err == nil {
-		examiner, err = eva.GetErrorExaminer(serializer)
+		examiner, err = eva.GetElementExaminer(serializer)
 	}
 
 	if err == nil {
 		result = &httpResult{
-			body:        data,
+			BaseResult:  eva.NewBaseResult(data),
 			code:        resp.StatusCode,
 			contentType: contentType,
+			duration:    time.Since(started),
+			requestId:   requestId(req, resp),
 			examine:     examiner,
 		}
 	}
@@ -91,22 +155,29 @@ func log(req *http.Request, form url.Values, resp *http.Response, body []byte) {
 	//fmt.Printf("\tBody: %s\n", string(body))
 }
 
-// String this result.
-func (result *httpResult) String() (string, bool) {
-	body := string(result.body)
-	return body, len(body) > 0
-}
-
 // Error of this result.
 func (result *httpResult) Error() (err error, _ bool) {
 
 	if result.examine != nil {
-		if result.code < http.StatusOK || result.code >= http.StatusBadRequest {
-			err = edn.MakeError(ErrServiceError, result)
+		var elem edn.Element
+		if elem, err = result.Element(); err == nil {
+			err = result.examine(elem)
 		}
 
-		if err == nil {
-			err = result.examine(result.body)
+		// the client service reports its errors with a failing status, keep those and report the rest as failed calls.
+		// the client errors keep the response as their cause, so that they are classified by their status as well.
+		if result.code < http.StatusOK || result.code >= http.StatusBadRequest {
+			responseErr := &ResponseError{
+				code:      result.code,
+				body:      result.Raw(),
+				requestId: result.requestId,
+			}
+
+			if clientErr, is := err.(eva.ClientError); is {
+				err = eva.ClientErrorWithCause(clientErr, responseErr)
+			} else {
+				err = edn.MakeError(ErrServiceError, responseErr)
+			}
 		}
 	} else {
 		err = edn.MakeErrorWithFormat(eva.ErrInvalidSerializer, "Unsupported return type: %s", result.contentType)
@@ -114,3 +185,32 @@ func (result *httpResult) Error() (err error, _ bool) {
 
 	return err, err != nil
 }
+
+// StatusCode returns the http status code of the response.
+func (result *httpResult) StatusCode() int {
+	return result.code
+}
+
+// ContentType returns the content type of the response.
+func (result *httpResult) ContentType() string {
+	return result.contentType
+}
+
+// Duration returns the time between sending the request and reading the response.
+func (result *httpResult) Duration() time.Duration {
+	return result.duration
+}
+
+// RequestId returns the id the service gave the request, or else the correlation id it was sent with.
+func (result *httpResult) RequestId() string {
+	return result.requestId
+}
+
+// requestId reads the id of the request from the response, or else from the request.
+func requestId(req *http.Request, resp *http.Response) (id string) {
+	if id = resp.Header.Get(RequestIdHeader); len(id) == 0 && req != nil {
+		id = req.Header.Get(correlationIdHeader)
+	}
+
+	return id
+}
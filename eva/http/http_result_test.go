@@ -0,0 +1,214 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Http result", func() {
+
+	newResult := func(status int, body string) Result {
+		req, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+		Ω(err).Should(BeNil())
+
+		resp := &http.Response{
+			StatusCode: status,
+			Header: map[string][]string{
+				"Content-Type":  {edn.EvaEdnMimeType.String()},
+				RequestIdHeader: {"request-1"},
+			},
+			Body: ioutil.NopCloser(strings.NewReader(body)),
+		}
+
+		result, err := newHttpResult(req, url.Values{}, resp, time.Now().Add(-time.Second))
+		Ω(err).Should(BeNil())
+		Ω(result).Should(BeAssignableToTypeOf(&httpResult{}))
+		return result.(Result)
+	}
+
+	It("should have the response details", func() {
+		result := newResult(http.StatusOK, `[["First Book" 1999]]`)
+		Ω(result.StatusCode()).Should(BeEquivalentTo(http.StatusOK))
+		Ω(result.ContentType()).Should(BeEquivalentTo(edn.EvaEdnMimeType.String()))
+		Ω(result.Duration()).Should(BeNumerically(">=", time.Second))
+		Ω(string(result.Raw())).Should(BeEquivalentTo(`[["First Book" 1999]]`))
+	})
+
+	It("should parse the payload once", func() {
+		result := newResult(http.StatusOK, `[["First Book" 1999]]`)
+
+		elem, err := result.Element()
+		Ω(err).Should(BeNil())
+		Ω(elem.ElementType()).Should(BeEquivalentTo(edn.VectorType))
+
+		again, err := result.Element()
+		Ω(err).Should(BeNil())
+		Ω(again).Should(BeIdenticalTo(elem))
+	})
+
+	It("should examine the payload it parsed", func() {
+		result := newResult(http.StatusOK, `{:tempids {}}`)
+
+		_, err := result.Element()
+		Ω(err).Should(BeNil())
+
+		// the payload is not parsed again, so changing it goes unnoticed.
+		copy(result.Raw(), "[unbalanced ")
+		_, failed := result.Error()
+		Ω(failed).Should(BeFalse())
+	})
+
+	It("should decode the payload", func() {
+		result := newResult(http.StatusOK, `[["First Book" 1999]]`)
+
+		var tuples [][]interface{}
+		Ω(result.Decode(&tuples)).Should(BeNil())
+		Ω(tuples).Should(BeEquivalentTo([][]interface{}{{"First Book", int64(1999)}}))
+	})
+
+	It("should keep the parse error", func() {
+		result := newResult(http.StatusOK, `[1`)
+
+		_, err := result.Element()
+		Ω(err).ShouldNot(BeNil())
+
+		var v interface{}
+		Ω(result.Decode(&v)).Should(Equal(err))
+	})
+
+	It("should report the service error", func() {
+		result := newResult(http.StatusBadRequest, `{}`)
+		err, failed := result.Error()
+		Ω(failed).Should(BeTrue())
+		Ω(err).Should(test.HaveMessage(ErrServiceError))
+		Ω(errors.Is(err, ErrServiceError)).Should(BeTrue())
+		Ω(result.StatusCode()).Should(BeEquivalentTo(http.StatusBadRequest))
+
+		var respErr *ResponseError
+		Ω(errors.As(err, &respErr)).Should(BeTrue())
+		Ω(respErr.StatusCode()).Should(BeEquivalentTo(http.StatusBadRequest))
+		Ω(string(respErr.Body())).Should(BeEquivalentTo("{}"))
+		Ω(respErr.RequestId()).Should(BeEquivalentTo("request-1"))
+		Ω(respErr.Error()).Should(ContainSubstring("status 400 Bad Request"))
+		Ω(eva.IsRetryable(err)).Should(BeFalse())
+	})
+
+	It("should classify the failing statuses", func() {
+		err, _ := newResult(http.StatusNotFound, `not here`).Error()
+		Ω(eva.IsNotFound(err)).Should(BeTrue())
+
+		err, _ = newResult(http.StatusConflict, `conflict`).Error()
+		Ω(eva.IsConflict(err)).Should(BeTrue())
+
+		err, _ = newResult(http.StatusServiceUnavailable, strings.Repeat("x", 1000)).Error()
+		Ω(eva.IsRetryable(err)).Should(BeTrue())
+		Ω(len(err.Error())).Should(BeNumerically("<", 700))
+	})
+
+	It("should fall back on the correlation id", func() {
+		req, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+		Ω(err).Should(BeNil())
+		req.Header.Add(correlationIdHeader, "correlation")
+
+		resp := &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+		}
+		Ω(requestId(req, resp)).Should(BeEquivalentTo("correlation"))
+
+		resp.Header.Set(RequestIdHeader, "request")
+		Ω(requestId(req, resp)).Should(BeEquivalentTo("request"))
+	})
+
+	It("should report the client error of a failed call", func() {
+		result := newResult(http.StatusBadRequest, `{:message "bad" :ex-info {:explanation "Malformed transact request." :type "IncorrectTransactSyntax" :code 3000} :ex-data ""}`)
+		err, failed := result.Error()
+		Ω(failed).Should(BeTrue())
+		Ω(errors.Is(err, eva.ErrIncorrectTransactSyntax)).Should(BeTrue())
+
+		var clientErr eva.ClientError
+		Ω(errors.As(err, &clientErr)).Should(BeTrue())
+		Ω(clientErr.ServiceMessage()).Should(BeEquivalentTo("bad"))
+	})
+
+	It("should classify the client errors by their status", func() {
+		exInfo := func(code int) string {
+			return fmt.Sprintf(`{:message "failed" :ex-info {:code %d} :ex-data nil}`, code)
+		}
+
+		err, _ := newResult(http.StatusServiceUnavailable, exInfo(-1)).Error()
+		Ω(eva.IsRetryable(err)).Should(BeTrue())
+
+		err, _ = newResult(http.StatusConflict, exInfo(-1)).Error()
+		Ω(eva.IsConflict(err)).Should(BeTrue())
+		Ω(eva.IsRetryable(err)).Should(BeFalse())
+
+		err, _ = newResult(http.StatusNotFound, exInfo(-1)).Error()
+		Ω(eva.IsNotFound(err)).Should(BeTrue())
+
+		var clientErr eva.ClientError
+		Ω(errors.As(err, &clientErr)).Should(BeTrue())
+
+		var respErr *ResponseError
+		Ω(errors.As(err, &respErr)).Should(BeTrue())
+		Ω(respErr.StatusCode()).Should(BeEquivalentTo(http.StatusNotFound))
+		Ω(respErr.RequestId()).Should(BeEquivalentTo("request-1"))
+	})
+
+	It("should classify the client errors by their status and code", func() {
+		exInfo := func(code *eva.ErrorCode) string {
+			return fmt.Sprintf(`{:message "failed" :ex-info {:code %d} :ex-data nil}`, code.Code())
+		}
+
+		err, _ := newResult(http.StatusInternalServerError, exInfo(eva.ErrIncorrectTransactSyntax)).Error()
+		Ω(errors.Is(err, eva.ErrIncorrectTransactSyntax)).Should(BeTrue())
+		Ω(eva.IsRetryable(err)).Should(BeFalse())
+
+		err, _ = newResult(http.StatusServiceUnavailable, `{:message "failed" :ex-info {:code -42} :ex-data nil}`).Error()
+		Ω(eva.IsRetryable(err)).Should(BeTrue())
+
+		err, _ = newResult(http.StatusConflict, exInfo(eva.ErrIncorrectTransactSyntax)).Error()
+		Ω(eva.IsConflict(err)).Should(BeTrue())
+
+		err, _ = newResult(http.StatusNotFound, exInfo(eva.ErrIncorrectTransactSyntax)).Error()
+		Ω(eva.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("should not classify the successful results", func() {
+		err, failed := newResult(http.StatusOK, `{:tempids {}}`).Error()
+		Ω(failed).Should(BeFalse())
+		Ω(eva.IsRetryable(err) || eva.IsConflict(err) || eva.IsNotFound(err)).Should(BeFalse())
+	})
+
+	It("should be usable as an eva result", func() {
+		var result eva.Result = newResult(http.StatusOK, `"text"`)
+		var text string
+		Ω(result.Decode(&text)).Should(BeNil())
+		Ω(text).Should(BeEquivalentTo("text"))
+	})
+})
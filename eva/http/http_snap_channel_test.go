@@ -15,6 +15,12 @@
 package http
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/eva"
 	. "github.com/onsi/ginkgo"
@@ -68,17 +74,17 @@ var _ = Describe("Connection Channel Test", func() {
 				Ω(tenant).ShouldNot(BeNil())
 
 				httpSnap := snap.(*httpSnapChanImpl)
-				result, err := httpSnap.invoke(f)
+				result, err := httpSnap.invoke(context.Background(), f)
 				Ω(err).Should(BeNil())
 				Ω(result).ShouldNot(BeNil())
 
 				pattern := edn.NewStringElement("f")
 
-				result, err = httpSnap.pull(pattern, eva.RawString("param"))
+				result, err = httpSnap.pull(context.Background(), pattern, eva.RawString("param"))
 				Ω(err).Should(BeNil())
 				Ω(result).ShouldNot(BeNil())
 
-				result, err = httpSnap.pull(pattern, eva.RawString("params"), &struct{}{})
+				result, err = httpSnap.pull(context.Background(), pattern, eva.RawString("params"), &struct{}{})
 				Ω(err).ShouldNot(BeNil())
 				Ω(result).Should(BeNil())
 			} else {
@@ -86,4 +92,52 @@ var _ = Describe("Connection Channel Test", func() {
 			}
 		})
 	})
+
+	Context("with a view of the database", func() {
+		It("should send the view in the reference", func() {
+			config, err := eva.NewConfiguration(`{
+				"source": {
+					"type":   "http",
+					"server": "localhost"
+				},
+				"category": "test"
+			}`)
+			Ω(err).Should(BeNil())
+
+			tenant, err := eva.NewTenant("tenant")
+			Ω(err).Should(BeNil())
+
+			source, err := initHttpSource(config, tenant)
+			Ω(err).Should(BeNil())
+
+			var form url.Values
+			good := fakeGoodCaller(edn.EvaEdnMimeType.String())
+			source.(*httpSourceImpl).callClient = func(c httpDoer, r *http.Request) (*http.Response, error) {
+				body, e := ioutil.ReadAll(r.Body)
+				Ω(e).Should(BeNil())
+				form, e = url.ParseQuery(string(body))
+				Ω(e).Should(BeNil())
+				return good(c, r)
+			}
+
+			at := time.Date(2019, 1, 2, 3, 4, 5, 6000000, time.UTC)
+			snap, err := source.AsOfSnapshot("test", at, eva.WithSince(4398046511106))
+			Ω(err).Should(BeNil())
+			Ω(snap).Should(BeAssignableToTypeOf(&httpSnapChanImpl{}))
+
+			history, err := snap.History()
+			Ω(err).Should(BeNil())
+			Ω(history).Should(BeAssignableToTypeOf(&httpSnapChanImpl{}))
+
+			_, err = history.Pull("[*]", edn.NewIntegerElement(1))
+			Ω(err).Should(BeNil())
+
+			ref := form.Get("reference")
+			Ω(ref).Should(HavePrefix("#eva.client.service/snapshot-ref {"))
+			Ω(ref).Should(ContainSubstring(`:as-of #inst "2019-01-02T03:04:05.006Z"`))
+			Ω(ref).Should(ContainSubstring(":since 4398046511106"))
+			Ω(ref).Should(ContainSubstring(":history true"))
+			Ω(ref).Should(ContainSubstring(`:label "test"`))
+		})
+	})
 })
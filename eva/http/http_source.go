@@ -15,6 +15,7 @@
 package http
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -163,8 +164,8 @@ func (source *httpSourceImpl) formulateUrl(operation string) string {
 		source.BaseSource.Category())
 }
 
-// call the uri with the provided form.
-func (source *httpSourceImpl) call(method string, uri string, form url.Values) (result eva.Result, err error) {
+// call the uri with the provided form, the context bounds the requests and the pauses between them.
+func (source *httpSourceImpl) call(ctx context.Context, method string, uri string, form url.Values) (result eva.Result, err error) {
 
 	if source.callClient != nil {
 		var req *http.Request
@@ -172,10 +173,10 @@ func (source *httpSourceImpl) call(method string, uri string, form url.Values) (
 
 		var serializer edn.Serializer
 		if serializer, err = source.Serializer(); err == nil {
-			if req, err = http.NewRequest(method, uri, strings.NewReader(form.Encode())); err == nil {
+			if req, err = http.NewRequestWithContext(ctx, method, uri, strings.NewReader(form.Encode())); err == nil {
 
 				if corrId, has := source.Tenant().CorrelationId(); has {
-					req.Header.Add("_cid", corrId)
+					req.Header.Add(correlationIdHeader, corrId)
 				}
 
 				req.Header.Add("Content-Type", XFormContentType)
@@ -196,10 +197,11 @@ func (source *httpSourceImpl) call(method string, uri string, form url.Values) (
 			for tries := 0; tries < source.retryTimes && err == nil && !done; tries++ {
 
 				var resp *http.Response
+				started := time.Now()
 				if resp, err = source.callClient(client, req); err == nil {
 
 					done = true // At this point the request was made and server responded.
-					result, err = newHttpResult(req, form, resp)
+					result, err = newHttpResult(req, form, resp, started)
 				}
 
 				switch e := err.(type) {
@@ -217,12 +219,14 @@ func (source *httpSourceImpl) call(method string, uri string, form url.Values) (
 						strings.Contains(errMsg, "connect: connection refused"):
 
 						// For all these cases, just pause and try again.
-						time.Sleep(source.retryPause)
+						if pauseErr := source.pause(ctx); pauseErr != nil {
+							err = pauseErr
+						}
 					}
 				}
 
-				// clear the error if needed.
-				if err != nil {
+				// clear the error if needed, there is no point trying again once the context is done.
+				if err != nil && ctx.Err() == nil {
 					if tries+1 < source.retryTimes {
 						err = nil
 					}
@@ -237,8 +241,21 @@ func (source *httpSourceImpl) call(method string, uri string, form url.Values) (
 	return result, err
 }
 
+// pause between two tries, unless the context is done first.
+func (source *httpSourceImpl) pause(ctx context.Context) (err error) {
+	timer := time.NewTimer(source.retryPause)
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+		err = ctx.Err()
+	}
+
+	return err
+}
+
 // queryImpl implements the query.
-func (source *httpSourceImpl) queryImpl(query interface{}, parameters ...interface{}) (result eva.Result, err error) {
+func (source *httpSourceImpl) queryImpl(ctx context.Context, query interface{}, parameters ...interface{}) (result eva.Result, err error) {
 	form := url.Values{}
 
 	if err == nil {
@@ -258,7 +275,7 @@ func (source *httpSourceImpl) queryImpl(query interface{}, parameters ...interfa
 			form.Add("query", trx)
 			if err = source.fillForm(form, parameters...); err == nil {
 				uri := source.formulateUrl("q")
-				result, err = source.call(http.MethodPost, uri, form)
+				result, err = source.call(ctx, http.MethodPost, uri, form)
 			}
 		}
 	}
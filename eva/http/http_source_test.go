@@ -15,6 +15,8 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -32,6 +34,7 @@ import (
 type fakeClient struct {
 	contentType string
 	status      int
+	body        string
 }
 
 type fakeCaller struct {
@@ -42,13 +45,18 @@ type fakeCaller struct {
 }
 
 func (c *fakeClient) Do(req *http.Request) (*http.Response, error) {
+	body := c.body
+	if len(body) == 0 {
+		body = "[]"
+	}
+
 	resp := &http.Response{
 		Status:     "Testing",
 		StatusCode: c.status,
 		Header: map[string][]string{
 			"test": {"val"},
 		},
-		Body: ioutil.NopCloser(strings.NewReader("[]")),
+		Body: ioutil.NopCloser(strings.NewReader(body)),
 	}
 
 	if len(c.contentType) > 0 {
@@ -102,6 +110,17 @@ func fakeGoodCaller(contentType string) func(c httpDoer, r *http.Request) (*http
 	}
 }
 
+func fakeBodyCaller(contentType string, body string) func(c httpDoer, r *http.Request) (*http.Response, error) {
+	return func(c httpDoer, r *http.Request) (*http.Response, error) {
+		f := &fakeClient{
+			status:      http.StatusOK,
+			contentType: contentType,
+			body:        body,
+		}
+		return f.Do(r)
+	}
+}
+
 var (
 	fakeBadCaller = func(c httpDoer, r *http.Request) (*http.Response, error) {
 		f := &fakeClient{
@@ -482,7 +501,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).ShouldNot(BeNil())
 				Ω(err).Should(test.HaveMessage(ErrNoServiceImpl))
 				Ω(res).Should(BeNil())
@@ -522,7 +541,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 
@@ -565,7 +584,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 
@@ -609,7 +628,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 
@@ -658,7 +677,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 			} else {
@@ -703,7 +722,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 				Ω(f.callCount).Should(BeEquivalentTo(tries))
@@ -745,7 +764,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 
@@ -785,7 +804,7 @@ var _ = Describe("Binding Test", func() {
 				form := url.Values{}
 
 				form.Add("foo", "bar")
-				res, err := httpSource.call("GET", "http://localhost", form)
+				res, err := httpSource.call(context.Background(), "GET", "http://localhost", form)
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 
@@ -826,7 +845,7 @@ var _ = Describe("Binding Test", func() {
 			if httpSource, is := source.(*httpSourceImpl); is {
 				httpSource.callClient = fakeGoodCaller(edn.EvaEdnMimeType.String())
 
-				res, err := httpSource.queryImpl(edn.NewStringElement("foo"))
+				res, err := httpSource.queryImpl(context.Background(), edn.NewStringElement("foo"))
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 			} else {
@@ -862,7 +881,7 @@ var _ = Describe("Binding Test", func() {
 			if httpSource, is := source.(*httpSourceImpl); is {
 				httpSource.callClient = fakeGoodCaller(edn.EvaEdnMimeType.String())
 
-				res, err := httpSource.queryImpl("\"foo\"")
+				res, err := httpSource.queryImpl(context.Background(), "\"foo\"")
 				Ω(err).Should(BeNil())
 				Ω(res).ShouldNot(BeNil())
 			} else {
@@ -900,7 +919,7 @@ var _ = Describe("Binding Test", func() {
 				tenant, err := eva.NewTenant("tenant")
 				Ω(err).Should(BeNil())
 
-				res, err := httpSource.queryImpl(tenant, 42)
+				res, err := httpSource.queryImpl(context.Background(), tenant, 42)
 				Ω(err).ShouldNot(BeNil())
 				Ω(err).Should(test.HaveMessage(ErrUnsupportedType))
 				Ω(res).Should(BeNil())
@@ -1052,4 +1071,76 @@ var _ = Describe("Binding Test", func() {
 			Ω(err).Should(BeNil())
 		})
 	})
+
+	Context("with a context", func() {
+
+		type ctxKey string
+
+		newSource := func(retries string) *httpSourceImpl {
+			config, err := eva.NewConfiguration(fmt.Sprintf(`{
+				"source": {
+					"type":    "http",
+					"server":  "localhost:1",
+					"retries": "%s"
+				},
+				"category": "test"
+			}`, retries))
+			Ω(err).Should(BeNil())
+
+			tenant, err := eva.NewTenant("tenant")
+			Ω(err).Should(BeNil())
+
+			source, err := initHttpSource(config, tenant)
+			Ω(err).Should(BeNil())
+			Ω(source).Should(BeAssignableToTypeOf(&httpSourceImpl{}))
+
+			return source.(*httpSourceImpl)
+		}
+
+		It("should send the requests with the context", func() {
+			httpSource := newSource("1")
+
+			var seen context.Context
+			good := fakeGoodCaller(edn.EvaEdnMimeType.String())
+			httpSource.callClient = func(c httpDoer, r *http.Request) (*http.Response, error) {
+				seen = r.Context()
+				return good(c, r)
+			}
+
+			ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+			res, err := httpSource.QueryContext(ctx, "[:find ?e :where [?e :db/ident]]")
+			Ω(err).Should(BeNil())
+			Ω(res).ShouldNot(BeNil())
+			Ω(seen).ShouldNot(BeNil())
+			Ω(seen.Value(ctxKey("key"))).Should(BeEquivalentTo("value"))
+		})
+
+		It("should stop pausing between the tries once the context is done", func() {
+			httpSource := newSource("3@100000")
+
+			f := fakeRetryCaller(3)
+			httpSource.callClient = f.clientFunc
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			started := time.Now()
+			res, err := httpSource.call(ctx, http.MethodPost, "http://localhost", url.Values{})
+			Ω(time.Since(started)).Should(BeNumerically("<", 5*time.Second))
+			Ω(errors.Is(err, context.DeadlineExceeded)).Should(BeTrue())
+			Ω(res).Should(BeNil())
+			Ω(f.callCount).Should(BeEquivalentTo(1))
+		})
+
+		It("should not try again once the context is cancelled", func() {
+			httpSource := newSource("3@100000")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			res, err := httpSource.QueryContext(ctx, "[:find ?e :where [?e :db/ident]]")
+			Ω(errors.Is(err, context.Canceled)).Should(BeTrue())
+			Ω(res).Should(BeNil())
+		})
+	})
 })
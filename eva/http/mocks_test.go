@@ -15,6 +15,8 @@
 package http
 
 import (
+	"context"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/eva"
 )
@@ -38,12 +40,17 @@ func (source *mockSource) LatestSnapshot(label interface{}) (channel eva.Snapsho
 }
 
 // AsOfSnapshot returns the snapshot channel of the specified category as of the rules provided.
-func (source *mockSource) AsOfSnapshot(label interface{}, asOf interface{}) (channel eva.SnapshotChannel, err error) {
+func (source *mockSource) AsOfSnapshot(label interface{}, asOf interface{}, options ...eva.SnapshotOption) (channel eva.SnapshotChannel, err error) {
 	return nil, nil
 }
 
 // Query the source for data.
-func (source *mockSource) Query(query interface{}, parameters ...interface{}) (result eva.Result, err error) {
+func (source *mockSource) Query(query interface{}, parameters ...interface{}) (result eva.QueryResult, err error) {
+	return nil, nil
+}
+
+// QueryContext queries the source for data.
+func (source *mockSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result eva.QueryResult, err error) {
 	return nil, nil
 }
 
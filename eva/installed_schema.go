@@ -0,0 +1,162 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// InstallAttribute is the attribute of the database partition referring to the installed attributes.
+	InstallAttribute = ":db.install/attribute"
+
+	// SchemaCacheSize is the number of schemas, one per database and basis t, kept by the process.
+	SchemaCacheSize = 32
+)
+
+// InstalledSchema is the model of the attributes installed in a snapshot, see SnapshotChannel.Schema. It is read-only
+// and safe to share.
+type InstalledSchema struct {
+	attributes map[string]AttributeDef
+	idents     map[int64]string
+}
+
+// NewInstalledSchema makes the model of the attributes, i.e. to validate transactions against a declared schema.
+func NewInstalledSchema(defs ...AttributeDef) *InstalledSchema {
+	schema := &InstalledSchema{
+		attributes: make(map[string]AttributeDef, len(defs)),
+		idents:     make(map[int64]string, len(defs)),
+	}
+
+	for _, def := range defs {
+		schema.attributes[def.Ident] = def
+		if def.Id != 0 {
+			schema.idents[def.Id] = def.Ident
+		}
+	}
+
+	return schema
+}
+
+// Attribute returns the definition of the attribute, given by its ident or its entity id.
+func (schema *InstalledSchema) Attribute(attr interface{}) (def AttributeDef, has bool) {
+	switch typed := attr.(type) {
+	case string:
+		def, has = schema.attributes[typed]
+	case int64:
+		if ident, known := schema.idents[typed]; known {
+			def, has = schema.attributes[ident]
+		}
+	case int:
+		def, has = schema.Attribute(int64(typed))
+	}
+	return def, has
+}
+
+// Attributes returns the definitions of the attributes, in the order of their idents.
+func (schema *InstalledSchema) Attributes() (defs []AttributeDef) {
+	defs = make([]AttributeDef, 0, len(schema.attributes))
+	for _, def := range schema.attributes {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Ident < defs[j].Ident
+	})
+	return defs
+}
+
+// Len returns the number of attributes.
+func (schema *InstalledSchema) Len() int {
+	return len(schema.attributes)
+}
+
+// loadSchema reads the attributes installed in the snapshot.
+func loadSchema(ctx context.Context, snap SnapshotChannel) (schema *InstalledSchema, err error) {
+
+	query := Find(PullOf("?a", installedPattern)).Collection().
+		Where(Clause(DbPartition, InstallAttribute, "?a"))
+
+	var result QueryResult
+	var values []edn.Element
+	if result, err = snap.QueryContext(ctx, query); err == nil {
+		values, err = result.Collection()
+	}
+
+	defs := make([]AttributeDef, 0, len(values))
+	for _, value := range values {
+		var def AttributeDef
+		if def, err = installedAttributeOf(value); err != nil {
+			break
+		}
+		defs = append(defs, def)
+	}
+
+	if err == nil {
+		schema = NewInstalledSchema(defs...)
+	}
+
+	return schema, err
+}
+
+// schemaCache keeps the most recent schemas read, by database and basis t.
+type schemaCache struct {
+	lock    sync.Mutex
+	size    int
+	keys    []string
+	schemas map[string]*InstalledSchema
+}
+
+// schemas are the schemas of the snapshots as of a t.
+var schemas = newSchemaCache(SchemaCacheSize)
+
+// newSchemaCache creates an empty cache of the size.
+func newSchemaCache(size int) *schemaCache {
+	return &schemaCache{
+		size:    size,
+		schemas: make(map[string]*InstalledSchema),
+	}
+}
+
+// get returns the schema of the key, nil if it is not cached.
+func (cache *schemaCache) get(key string) *InstalledSchema {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.schemas[key]
+}
+
+// put caches the schema of the key, the oldest schema making room for it.
+func (cache *schemaCache) put(key string, schema *InstalledSchema) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if _, has := cache.schemas[key]; !has {
+		if len(cache.keys) >= cache.size {
+			delete(cache.schemas, cache.keys[0])
+			cache.keys = cache.keys[1:]
+		}
+		cache.keys = append(cache.keys, key)
+	}
+	cache.schemas[key] = schema
+}
+
+// schemaKey returns the key of the schema of the database of the snapshot at the basis t.
+func schemaKey(channel *BaseSnapshotChannel, t int64) string {
+	return fmt.Sprintf("%s %s %d", tenantNameOf(channel), channel.Label(), t)
+}
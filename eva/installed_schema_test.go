@@ -0,0 +1,269 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// bookAttributes is the payload of the attributes installed in the book database.
+const bookAttributes = `[
+	{:db/id 10 :db/ident :book/title :db/valueType {:db/ident :db.type/string} :db/cardinality {:db/ident :db.cardinality/one}}
+	{:db/id 11 :db/ident :book/isbn :db/valueType {:db/ident :db.type/string} :db/cardinality {:db/ident :db.cardinality/one}
+	 :db/unique {:db/ident :db.unique/identity}}
+	{:db/id 12 :db/ident :book/year_published :db/valueType {:db/ident :db.type/long} :db/cardinality {:db/ident :db.cardinality/one}}
+	{:db/id 13 :db/ident :book/author :db/valueType {:db/ident :db.type/ref} :db/cardinality {:db/ident :db.cardinality/one}}
+	{:db/id 14 :db/ident :book/tags :db/valueType {:db/ident :db.type/keyword} :db/cardinality {:db/ident :db.cardinality/many}}
+	{:db/id 15 :db/ident :book/published_at :db/valueType {:db/ident :db.type/instant} :db/cardinality {:db/ident :db.cardinality/one}}
+	{:db/id 16 :db/ident :book/rating :db/valueType {:db/ident :db.type/double} :db/cardinality {:db/ident :db.cardinality/one}}
+	{:db/id 17 :db/ident :person/name :db/valueType {:db/ident :db.type/string} :db/cardinality {:db/ident :db.cardinality/one}
+	 :db/doc "Name of a person"}
+]`
+
+// installedSource answers the queries of the installed attributes and keeps the references they were made with, the
+// transactions are committed at the last t.
+type installedSource struct {
+	*mockSource
+	lock       sync.Mutex
+	payload    string
+	last       int64
+	references []Reference
+}
+
+// QueryContext answers the query with the installed attributes.
+func (source *installedSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	source.references = append(source.references, parameters[0].(Reference))
+	return newQueryResult(query, newMockPayloadResult(source.payload)), nil
+}
+
+// Connection to the database of the source.
+func (source *installedSource) Connection(label interface{}) (ConnectionChannel, error) {
+	return NewBaseConnectionChannel(
+		edn.NewStringElement(fmt.Sprint(label)),
+		source,
+		func(ctx context.Context, transaction edn.Serializable) (Result, error) {
+			source.lock.Lock()
+			defer source.lock.Unlock()
+			return newMockPayloadResult(fmt.Sprintf("{:db-after #eva.client.service/snapshot-ref {:as-of %d}}", source.last)), nil
+		},
+		func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error) {
+			return NewBaseSnapshotChannel(edn.NewStringElement(fmt.Sprint(label)), source, nil, nil, asOf, options...)
+		})
+}
+
+// queries returns the number of queries made.
+func (source *installedSource) queries() int {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	return len(source.references)
+}
+
+var _ = Describe("installed schema test", func() {
+
+	var source *installedSource
+	var conn ConnectionChannel
+
+	BeforeEach(func() {
+		schemas = newSchemaCache(SchemaCacheSize)
+		bases = newBasisTracker()
+		source = &installedSource{
+			mockSource: &mockSource{},
+			payload:    bookAttributes,
+			last:       1000,
+		}
+
+		var err error
+		conn, err = source.Connection("books")
+		Ω(err).Should(BeNil())
+	})
+
+	It("should load the installed attributes", func() {
+		snap, err := conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+
+		schema, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(schema.Len()).Should(BeEquivalentTo(8))
+
+		def, has := schema.Attribute(":person/name")
+		Ω(has).Should(BeTrue())
+		Ω(def).Should(Equal(AttributeDef{
+			Id:          17,
+			Ident:       ":person/name",
+			ValueType:   String,
+			Cardinality: One,
+			Doc:         "Name of a person",
+		}))
+
+		def, has = schema.Attribute(11)
+		Ω(has).Should(BeTrue())
+		Ω(def.Ident).Should(BeEquivalentTo(":book/isbn"))
+		Ω(def.Unique).Should(BeEquivalentTo(UniqueIdentity))
+
+		_, has = schema.Attribute(":book/titel")
+		Ω(has).Should(BeFalse())
+
+		attributes := schema.Attributes()
+		Ω(attributes).Should(HaveLen(8))
+		Ω(attributes[0].Ident).Should(BeEquivalentTo(":book/author"))
+		Ω(attributes[7].Ident).Should(BeEquivalentTo(":person/name"))
+	})
+
+	It("should cache the schema by the snapshot", func() {
+		snap, err := conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+
+		first, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		second, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(second).Should(BeIdenticalTo(first))
+		Ω(source.queries()).Should(BeEquivalentTo(1))
+
+		source.last = 1001
+		third, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(third).Should(BeIdenticalTo(first))
+		Ω(source.queries()).Should(BeEquivalentTo(1))
+	})
+
+	It("should cache the schema of the latest snapshots by their basis t", func() {
+		_, err := conn.Transact("[]")
+		Ω(err).Should(BeNil())
+
+		snap, err := conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+		first, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(basisT(source.references[0].GetProperty(AsOfReferenceProperty))).Should(Equal(&[]int{1000}[0]))
+
+		snap, err = conn.AsOfSnapshot(1000)
+		Ω(err).Should(BeNil())
+		second, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(second).Should(BeIdenticalTo(first))
+		Ω(source.queries()).Should(BeEquivalentTo(1))
+
+		source.last = 1001
+		_, err = conn.Transact("[]")
+		Ω(err).Should(BeNil())
+		snap, err = conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+		third, err := snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(third).ShouldNot(BeIdenticalTo(first))
+		Ω(source.queries()).Should(BeEquivalentTo(2))
+	})
+
+	It("should only cache the schema of the latest snapshots by the snapshots before a transaction", func() {
+		for i := 0; i < 2; i++ {
+			snap, err := conn.LatestSnapshot()
+			Ω(err).Should(BeNil())
+			_, err = snap.Schema()
+			Ω(err).Should(BeNil())
+		}
+		Ω(source.queries()).Should(BeEquivalentTo(2))
+		Ω(source.references[0].GetProperty(AsOfReferenceProperty)).Should(BeNil())
+
+		snap, err := conn.AsOfSnapshot(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+		Ω(err).Should(BeNil())
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(source.queries()).Should(BeEquivalentTo(3))
+
+		_, err = conn.Transact("[]")
+		Ω(err).Should(BeNil())
+		snap, err = conn.AsOfSnapshot(1000)
+		Ω(err).Should(BeNil())
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		snap, err = conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(source.queries()).Should(BeEquivalentTo(4))
+	})
+
+	It("should cache the schema by the basis t", func() {
+		for i := 0; i < 3; i++ {
+			snap, err := conn.AsOfSnapshot(1000)
+			Ω(err).Should(BeNil())
+			_, err = snap.Schema()
+			Ω(err).Should(BeNil())
+		}
+		Ω(source.queries()).Should(BeEquivalentTo(1))
+
+		snap, err := conn.AsOfSnapshot(1001)
+		Ω(err).Should(BeNil())
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(source.queries()).Should(BeEquivalentTo(2))
+
+		other, err := source.Connection("authors")
+		Ω(err).Should(BeNil())
+		snap, err = other.AsOfSnapshot(1000)
+		Ω(err).Should(BeNil())
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(source.queries()).Should(BeEquivalentTo(3))
+	})
+
+	It("should keep the most recent schemas", func() {
+		cache := newSchemaCache(2)
+		a, b, c := NewInstalledSchema(), NewInstalledSchema(), NewInstalledSchema()
+		cache.put("a", a)
+		cache.put("b", b)
+		cache.put("a", a)
+		cache.put("c", c)
+		Ω(cache.get("a")).Should(BeNil())
+		Ω(cache.get("b")).Should(BeIdenticalTo(b))
+		Ω(cache.get("c")).Should(BeIdenticalTo(c))
+	})
+
+	It("should read the attributes of the database a since snapshot views", func() {
+		snap, err := conn.AsOfSnapshot(1000, WithSince(900), WithHistory())
+		Ω(err).Should(BeNil())
+
+		_, err = snap.Schema()
+		Ω(err).Should(BeNil())
+		Ω(source.references).Should(HaveLen(1))
+
+		ref := source.references[0]
+		Ω(ref.GetProperty(SinceReferenceProperty)).Should(BeNil())
+		Ω(ref.GetProperty(HistoryReferenceProperty)).Should(BeNil())
+		Ω(basisT(ref.GetProperty(AsOfReferenceProperty))).Should(Equal(snap.AsOf()))
+	})
+
+	It("should reject the attributes it cannot read", func() {
+		source.payload = `[{:db/id 10 :db/doc "No ident"}]`
+		snap, err := conn.LatestSnapshot()
+		Ω(err).Should(BeNil())
+
+		schema, err := snap.Schema()
+		Ω(err).Should(test.HaveMessage(ErrInvalidSchema))
+		Ω(schema).Should(BeNil())
+	})
+})
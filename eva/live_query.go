@@ -0,0 +1,389 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidLiveQuery defines the error for a live query that cannot be started.
+	ErrInvalidLiveQuery = edn.ErrorMessage("Invalid live query")
+
+	// DefaultDebounce defines how long a live query waits for the transactions to settle before it is evaluated again.
+	DefaultDebounce = 100 * time.Millisecond
+
+	// DefaultRetryBackoff defines how long a live query waits to evaluate the query again after a failure that can be
+	// retried, the wait doubles with each failure in a row.
+	DefaultRetryBackoff = time.Second
+
+	// MaxRetryBackoff caps how long a live query waits to evaluate the query again after a failure.
+	MaxRetryBackoff = time.Minute
+)
+
+// ResultDiff is the change of the rows of a live query between two evaluations.
+type ResultDiff struct {
+
+	// T is the basis t the query was evaluated at, 0 when it was evaluated on the latest snapshot before a basis t was
+	// known.
+	T int64
+
+	// Added are the rows the evaluation found that the one before did not, all of them for the first evaluation.
+	Added [][]edn.Element
+
+	// Removed are the rows the evaluation before found that this one did not.
+	Removed [][]edn.Element
+}
+
+// LiveResult streams the changes of the rows of a live query.
+type LiveResult interface {
+
+	// Diffs returns the changes of the rows, the first one holds all the rows of the query. The channel is closed once
+	// the live query stops.
+	Diffs() <-chan ResultDiff
+
+	// Err returns the error that stopped the live query once the diffs are closed, the error of the context when it
+	// is done or closed.
+	Err() error
+
+	// Close stops the live query.
+	Close()
+}
+
+// LiveQueryOption sets how often a live query is evaluated, it is given along with the parameters of the query.
+type LiveQueryOption func(live *liveResultImpl) error
+
+// WithDebounce sets how long the live query waits once a basis t is observed, each new basis t restarting the wait,
+// before it is evaluated again.
+func WithDebounce(debounce time.Duration) LiveQueryOption {
+	return func(live *liveResultImpl) (err error) {
+		if debounce >= 0 {
+			live.debounce = debounce
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidLiveQuery, "Debounce must not be negative, got: %s", debounce)
+		}
+		return err
+	}
+}
+
+// WithMinInterval caps how often the live query is evaluated, at most once per interval.
+func WithMinInterval(interval time.Duration) LiveQueryOption {
+	return func(live *liveResultImpl) (err error) {
+		if interval >= 0 {
+			live.minInterval = interval
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidLiveQuery, "Minimum interval must not be negative, got: %s", interval)
+		}
+		return err
+	}
+}
+
+// WithRetryBackoff sets how long the live query waits to evaluate the query again after the first failure that can be
+// retried, the wait doubles with each failure in a row up to MaxRetryBackoff.
+func WithRetryBackoff(backoff time.Duration) LiveQueryOption {
+	return func(live *liveResultImpl) (err error) {
+		if backoff > 0 {
+			live.backoff = backoff
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidLiveQuery, "Retry backoff must be positive, got: %s", backoff)
+		}
+		return err
+	}
+}
+
+// liveResultImpl evaluates the query again as the basis t of the connection are observed.
+type liveResultImpl struct {
+	conn        ConnectionChannel
+	query       interface{}
+	parameters  []interface{}
+	debounce    time.Duration
+	minInterval time.Duration
+	backoff     time.Duration
+	cancel      context.CancelFunc
+	diffs       chan ResultDiff
+	rows        map[string][]edn.Element
+	order       []string
+	lock        sync.Mutex
+	err         error
+}
+
+// LiveQuery evaluates the query as of the basis t of the latest snapshot of the connection, then again as of each new
+// basis t its subscription observes, and streams the rows added and removed. The basis t are those the process
+// observes, see ConnectionChannel.Subscribe. The LiveQueryOption and SubscribeOption parameters set how often the
+// query is evaluated and the latest snapshot polled, the other parameters are those of SnapshotChannel.Query.
+func LiveQuery(conn ConnectionChannel, query interface{}, parameters ...interface{}) (LiveResult, error) {
+	return LiveQueryContext(context.Background(), conn, query, parameters...)
+}
+
+// LiveQueryContext evaluates the query as the basis t of the connection are observed, until the context is done or the
+// live query is closed.
+func LiveQueryContext(ctx context.Context, conn ConnectionChannel, query interface{}, parameters ...interface{}) (result LiveResult, err error) {
+
+	live := &liveResultImpl{
+		conn:     conn,
+		query:    query,
+		debounce: DefaultDebounce,
+		backoff:  DefaultRetryBackoff,
+		diffs:    make(chan ResultDiff),
+	}
+
+	var subOptions []SubscribeOption
+	for _, param := range parameters {
+		switch typed := param.(type) {
+		case LiveQueryOption:
+			if err == nil {
+				err = typed(live)
+			}
+		case SubscribeOption:
+			subOptions = append(subOptions, typed)
+		default:
+			live.parameters = append(live.parameters, param)
+		}
+	}
+
+	if err == nil && (conn == nil || query == nil) {
+		err = edn.MakeError(ErrInvalidLiveQuery, "connection or query are not valid")
+	}
+
+	// the first evaluation is made as of the basis t of the latest snapshot and the subscription starts from the next
+	// t, so that no transaction is missed in between. Until a basis t is known the latest snapshot is evaluated, and
+	// the first basis t observed is evaluated again.
+	var t int64
+	if err == nil {
+		var snap SnapshotChannel
+		if snap, err = conn.LatestSnapshot(); err == nil {
+			if t, err = snap.BasisT(); errors.Is(err, ErrUnknownBasis) {
+				err = nil
+			}
+		}
+	}
+
+	if err == nil {
+		ctx, live.cancel = context.WithCancel(ctx)
+
+		var sub Subscription
+		if sub, err = conn.Subscribe(ctx, t+1, subOptions...); err == nil {
+			go live.run(ctx, sub, t)
+			result = live
+		} else {
+			live.cancel()
+		}
+	}
+
+	return result, err
+}
+
+// Diffs returns the changes of the rows.
+func (live *liveResultImpl) Diffs() <-chan ResultDiff {
+	return live.diffs
+}
+
+// Err returns the error that stopped the live query.
+func (live *liveResultImpl) Err() error {
+	live.lock.Lock()
+	defer live.lock.Unlock()
+	return live.err
+}
+
+// Close stops the live query.
+func (live *liveResultImpl) Close() {
+	live.cancel()
+}
+
+// run evaluates the query as of the t, then again once the basis t observed settle, until the context is done or
+// an evaluation fails with an error that cannot be retried. The evaluations that fail with an error that can be retried
+// are made again after the backoff.
+func (live *liveResultImpl) run(ctx context.Context, sub Subscription, t int64) {
+
+	pending, evaluated := t, int64(0)
+	first := true
+	backoff := live.backoff
+	var last, retryAt time.Time
+	var fire <-chan time.Time
+	var timer *time.Timer
+
+	schedule := func(delay time.Duration) {
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.NewTimer(delay)
+		fire = timer.C
+	}
+
+	var err error
+	schedule(0)
+
+	for err == nil {
+		select {
+		case entry, ok := <-sub.Entries():
+			if ok {
+				pending = entry.T
+				delay := live.debounce
+				if wait := live.minInterval - time.Since(last); wait > delay {
+					delay = wait
+				}
+				if wait := time.Until(retryAt); wait > delay {
+					delay = wait
+				}
+				schedule(delay)
+			} else {
+				err = sub.Err()
+			}
+		case <-fire:
+			fire = nil
+			if first || pending > evaluated {
+				if err = live.evaluate(ctx, pending, first); err == nil {
+					evaluated, first = pending, false
+					backoff, retryAt = live.backoff, time.Time{}
+				} else if ctx.Err() == nil && IsRetryable(err) {
+					err = nil
+					retryAt = time.Now().Add(backoff)
+					schedule(backoff)
+					if backoff *= 2; backoff > MaxRetryBackoff {
+						backoff = MaxRetryBackoff
+					}
+				}
+				last = time.Now()
+			}
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	if timer != nil {
+		timer.Stop()
+	}
+
+	live.lock.Lock()
+	live.err = err
+	live.lock.Unlock()
+
+	live.cancel()
+	close(live.diffs)
+}
+
+// evaluate the query as of the t, or on the latest snapshot for 0, and send the rows that changed.
+func (live *liveResultImpl) evaluate(ctx context.Context, t int64, first bool) (err error) {
+
+	var snap SnapshotChannel
+	if t > 0 {
+		snap, err = live.conn.AsOfSnapshot(t)
+	} else {
+		snap, err = live.conn.LatestSnapshot()
+	}
+
+	var rows Rows
+	if err == nil {
+		var result QueryResult
+		if result, err = snap.QueryContext(ctx, live.query, live.parameters...); err == nil {
+			rows, err = result.Rows()
+		}
+	}
+
+	if err == nil {
+		diff := ResultDiff{T: t}
+
+		current := make(map[string][]edn.Element, rows.Len())
+		order := make([]string, 0, rows.Len())
+		for rows.Next() {
+			row := rows.Row()
+			key := rowKey(row)
+			if _, has := current[key]; !has {
+				current[key] = row
+				order = append(order, key)
+				if _, had := live.rows[key]; !had {
+					diff.Added = append(diff.Added, row)
+				}
+			}
+		}
+
+		for _, key := range live.order {
+			if _, has := current[key]; !has {
+				diff.Removed = append(diff.Removed, live.rows[key])
+			}
+		}
+
+		live.rows = current
+		live.order = order
+
+		if first || len(diff.Added) > 0 || len(diff.Removed) > 0 {
+			select {
+			case live.diffs <- diff:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+	}
+
+	return err
+}
+
+// rowKey identifies a row by the canonical edn of its values.
+func rowKey(row []edn.Element) string {
+	values := make([]string, 0, len(row))
+	for _, value := range row {
+		values = append(values, canonicalKey(value))
+	}
+	return strings.Join(values, " ")
+}
+
+// canonicalKey writes the edn of the element with the entries of its maps and the members of its sets sorted, so that
+// the elements which are equal have the same key whatever the order they were read in.
+func canonicalKey(elem edn.Element) (key string) {
+
+	if elem == nil {
+		key = "nil"
+	} else if coll, is := elem.(edn.CollectionElement); is {
+		var children []string
+		_ = coll.IterateChildren(func(k edn.Element, v edn.Element) error {
+			if elem.ElementType() == edn.MapType {
+				children = append(children, canonicalKey(k)+" "+canonicalKey(v))
+			} else {
+				children = append(children, canonicalKey(v))
+			}
+			return nil
+		})
+
+		var open, end string
+		switch elem.ElementType() {
+		case edn.MapType:
+			open, end = "{", "}"
+			sort.Strings(children)
+		case edn.SetType:
+			open, end = "#{", "}"
+			sort.Strings(children)
+		case edn.ListType:
+			open, end = "(", ")"
+		default:
+			open, end = "[", "]"
+		}
+
+		if elem.HasTag() {
+			open = "#" + elem.Tag() + " " + open
+		}
+		key = open + strings.Join(children, " ") + end
+	} else {
+		key = elem.String()
+	}
+
+	return key
+}
@@ -0,0 +1,321 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// liveSource answers the queries with the payload for the t of the snapshot queried.
+type liveSource struct {
+	*mockSource
+	lock     sync.Mutex
+	payloads map[int64]string
+	failures []error
+	ts       []int64
+}
+
+// QueryContext answers the query with the payload of the t.
+func (source *liveSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+
+	var t int64
+	if asOf := basisT(parameters[0].(Reference).GetProperty(AsOfReferenceProperty)); asOf != nil {
+		t = int64(*asOf)
+	}
+	source.ts = append(source.ts, t)
+
+	if len(source.failures) > 0 {
+		err = source.failures[0]
+		source.failures = source.failures[1:]
+	} else {
+		result = newQueryResult(query, newMockPayloadResult(source.payloads[t]))
+	}
+
+	return result, err
+}
+
+// evaluated returns the ts the queries were made at.
+func (source *liveSource) evaluated() []int64 {
+	source.lock.Lock()
+	defer source.lock.Unlock()
+	return append([]int64{}, source.ts...)
+}
+
+var _ = Describe("live query test", func() {
+
+	var source *liveSource
+	var conn ConnectionChannel
+
+	const query = "[:find ?title :where [_ :book/title ?title]]"
+
+	BeforeEach(func() {
+		source = &liveSource{
+			mockSource: &mockSource{},
+			payloads: map[int64]string{
+				0:    `[["First Book"] ["Second Book"]]`,
+				999:  `[["First Book"] ["Second Book"]]`,
+				1000: `[["First Book"] ["Second Book"] ["Third Book"]]`,
+				1001: `[["Second Book"] ["Third Book"]]`,
+				1002: `[["Second Book"] ["Third Book"]]`,
+			},
+		}
+
+		var err error
+		conn, err = (&mockDatabase{t: 999}).connection(source)
+		Ω(err).Should(BeNil())
+
+		bases = newBasisTracker()
+		_, err = conn.Transact("[]")
+		Ω(err).Should(BeNil())
+	})
+
+	// commit the transactions through the connection.
+	commit := func(count int) {
+		for i := 0; i < count; i++ {
+			_, err := conn.Transact("[]")
+			Ω(err).Should(BeNil())
+		}
+	}
+
+	titles := func(rows [][]edn.Element) (values []string) {
+		for _, row := range rows {
+			values = append(values, row[0].Value().(string))
+		}
+		return values
+	}
+
+	receive := func(live LiveResult) (diff ResultDiff) {
+		Eventually(live.Diffs()).Should(Receive(&diff))
+		return diff
+	}
+
+	It("should stream the rows that change", func() {
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(999))
+		Ω(titles(diff.Added)).Should(Equal([]string{"First Book", "Second Book"}))
+		Ω(diff.Removed).Should(BeEmpty())
+
+		commit(1)
+		diff = receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1000))
+		Ω(titles(diff.Added)).Should(Equal([]string{"Third Book"}))
+		Ω(diff.Removed).Should(BeEmpty())
+
+		commit(1)
+		diff = receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1001))
+		Ω(diff.Added).Should(BeEmpty())
+		Ω(titles(diff.Removed)).Should(Equal([]string{"First Book"}))
+	})
+
+	It("should not send the evaluations that change nothing", func() {
+		commit(2)
+
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		Ω(receive(live).T).Should(BeEquivalentTo(1001))
+		commit(1)
+		Eventually(source.evaluated).Should(ContainElement(BeEquivalentTo(1002)))
+		Consistently(live.Diffs()).ShouldNot(Receive())
+	})
+
+	It("should subscribe from the t after the first evaluation", func() {
+		commit(2)
+
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		Ω(receive(live).T).Should(BeEquivalentTo(1001))
+		Consistently(source.evaluated).Should(Equal([]int64{1001}))
+	})
+
+	It("should evaluate the latest snapshot until a basis t is known", func() {
+		bases = newBasisTracker()
+
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(0))
+		Ω(diff.Added).Should(HaveLen(2))
+
+		commit(1)
+		diff = receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1000))
+		Ω(titles(diff.Added)).Should(Equal([]string{"Third Book"}))
+		Ω(source.evaluated()).Should(Equal([]int64{0, 1000}))
+	})
+
+	It("should only evaluate the query once the transactions settle", func() {
+		live, err := LiveQuery(conn, query, WithDebounce(200*time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		receive(live)
+		commit(1)
+		time.Sleep(20 * time.Millisecond)
+		commit(1)
+
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1001))
+		Ω(source.evaluated()).Should(Equal([]int64{999, 1001}))
+	})
+
+	It("should cap how often the query is evaluated", func() {
+		live, err := LiveQuery(conn, query, WithDebounce(0), WithMinInterval(300*time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		start := time.Now()
+		receive(live)
+		commit(1)
+
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1000))
+		Ω(time.Since(start)).Should(BeNumerically(">=", 300*time.Millisecond))
+	})
+
+	It("should bind the parameters of the query", func() {
+		live, err := LiveQuery(conn, "[:find ?title :in $ ?year :where [?b :book/year ?year] [?b :book/title ?title]]",
+			edn.NewIntegerElement(2017), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		receive(live)
+		live.Close()
+		Eventually(live.Diffs()).Should(BeClosed())
+		Ω(live.Err()).Should(Equal(context.Canceled))
+	})
+
+	It("should keep going after the errors that are retryable", func() {
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		receive(live)
+		source.lock.Lock()
+		source.failures = []error{io.EOF}
+		source.lock.Unlock()
+
+		commit(1)
+		Eventually(source.evaluated).Should(HaveLen(2))
+		Consistently(live.Diffs()).ShouldNot(Receive())
+
+		commit(1)
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1001))
+		Ω(titles(diff.Added)).Should(Equal([]string{"Third Book"}))
+		Ω(titles(diff.Removed)).Should(Equal([]string{"First Book"}))
+	})
+
+	It("should evaluate the query again after the backoff when it fails", func() {
+		live, err := LiveQuery(conn, query, WithDebounce(time.Millisecond), WithRetryBackoff(50*time.Millisecond),
+			WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		receive(live)
+		source.lock.Lock()
+		source.failures = []error{io.EOF, io.EOF}
+		source.lock.Unlock()
+
+		start := time.Now()
+		commit(1)
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(1000))
+		Ω(titles(diff.Added)).Should(Equal([]string{"Third Book"}))
+		Ω(time.Since(start)).Should(BeNumerically(">=", 150*time.Millisecond))
+		Ω(source.evaluated()).Should(Equal([]int64{999, 1000, 1000, 1000}))
+	})
+
+	It("should retry the first evaluation", func() {
+		source.failures = []error{io.EOF}
+
+		live, err := LiveQuery(conn, query, WithRetryBackoff(time.Millisecond), WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		defer live.Close()
+
+		diff := receive(live)
+		Ω(diff.T).Should(BeEquivalentTo(999))
+		Ω(diff.Added).Should(HaveLen(2))
+	})
+
+	It("should identify the rows by their values whatever the order of their maps and sets", func() {
+		var keys []string
+		for _, str := range []string{`[{:a 1 :b #{1 2 3}} #tag {:x [1 2] :y (3 4)}]`, `[{:b #{3 1 2} :a 1} #tag {:y (3 4) :x [1 2]}]`} {
+			elem, err := edn.Parse(str)
+			Ω(err).Should(BeNil())
+
+			var row []edn.Element
+			err = elem.(edn.CollectionElement).IterateChildren(func(_ edn.Element, value edn.Element) error {
+				row = append(row, value)
+				return nil
+			})
+			Ω(err).Should(BeNil())
+			keys = append(keys, rowKey(row))
+		}
+
+		Ω(keys[0]).Should(Equal(keys[1]))
+		Ω(keys[0]).Should(Equal(`{:a 1 :b #{1 2 3}} #tag {:x [1 2] :y (3 4)}`))
+		Ω(rowKey([]edn.Element{nil})).Should(Equal("nil"))
+	})
+
+	It("should stop on the errors that are not retryable", func() {
+		failure := errors.New("failure")
+		source.failures = []error{failure}
+
+		live, err := LiveQuery(conn, query, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		Eventually(live.Diffs()).Should(BeClosed())
+		Ω(live.Err()).Should(Equal(failure))
+	})
+
+	It("should reject the bad options", func() {
+		_, err := LiveQuery(conn, query, WithDebounce(-time.Second))
+		Ω(err).Should(test.HaveMessage(ErrInvalidLiveQuery))
+
+		_, err = LiveQuery(conn, query, WithMinInterval(-time.Second))
+		Ω(err).Should(test.HaveMessage(ErrInvalidLiveQuery))
+
+		_, err = LiveQuery(conn, query, WithRetryBackoff(0))
+		Ω(err).Should(test.HaveMessage(ErrInvalidLiveQuery))
+
+		_, err = LiveQuery(nil, query)
+		Ω(err).Should(test.HaveMessage(ErrInvalidLiveQuery))
+
+		_, err = LiveQuery(conn, query, WithBuffer(-1))
+		Ω(err).Should(test.HaveMessage(ErrInvalidSubscription))
+	})
+})
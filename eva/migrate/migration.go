@@ -0,0 +1,173 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+const (
+	// ErrInvalidMigration defines the error for a migration that cannot be applied.
+	ErrInvalidMigration = edn.ErrorMessage("Invalid migration")
+
+	// MigrationExtension is the extension of the migration files LoadDir reads.
+	MigrationExtension = ".edn"
+)
+
+func init() {
+	eva.PanicOnError(func() (err error) {
+		dataParser, err = edn.NewParser(edn.WithLimits(edn.DefaultLimits))
+		return err
+	})
+}
+
+// dataParser reads the transaction data of the migrations, the limits guard against broken or hostile files.
+var dataParser edn.Parser
+
+// BuildFunc builds the transaction data of a migration from the latest snapshot when the migration is applied.
+type BuildFunc func(ctx context.Context, snap eva.SnapshotChannel) (interface{}, error)
+
+// Migration is a named transaction applied once to a database. The migrations are applied in the order they are given
+// to the migrator, and the name is recorded in the database in the same transaction.
+type Migration struct {
+
+	// Name identifies the migration in the database, it must not change once the migration is applied.
+	Name string
+
+	// Tx is the transaction data, a string, an edn element, an *eva.Tx, an *eva.Schema or any other serializable
+	// vector of operations.
+	Tx interface{}
+
+	// Build builds the transaction data instead of Tx, for the backfills which depend on the data of the database.
+	Build BuildFunc
+}
+
+// Rename renames the attribute or the ident, the data under the old ident keeping its entity.
+func Rename(from string, to string) *eva.Tx {
+	return eva.NewTx().Add(from, eva.IdentAttribute, eva.Ident(to))
+}
+
+// AddEnums installs the idents of enum values.
+func AddEnums(idents ...string) *eva.Tx {
+	tx := eva.NewTx()
+	for _, ident := range idents {
+		tx.Entity(map[string]interface{}{
+			eva.EntityIdAttribute: tx.TempID(),
+			eva.IdentAttribute:    eva.Ident(ident),
+		})
+	}
+	return tx
+}
+
+// Load reads the migration of the name from an edn vector of operations.
+func Load(name string, reader io.Reader) (migration Migration, err error) {
+
+	var data []byte
+	if data, err = ioutil.ReadAll(reader); err == nil {
+		var elem edn.Element
+		if elem, err = operationsOf(string(data)); err == nil {
+			migration = Migration{
+				Name: name,
+				Tx:   elem,
+			}
+		}
+	}
+
+	if err != nil {
+		err = edn.MakeErrorWithFormat(ErrInvalidMigration, "%s: %s", name, err.Error())
+	}
+
+	return migration, err
+}
+
+// LoadDir reads the `.edn` files of the directory in the order of their names, `001-books.edn` then
+// `002-authors.edn`, each being the migration named after the file without its extension.
+func LoadDir(dir string) (migrations []Migration, err error) {
+
+	var names []string
+	names, err = filepath.Glob(filepath.Join(dir, "*"+MigrationExtension))
+	sort.Strings(names)
+
+	for _, path := range names {
+		if err != nil {
+			break
+		}
+
+		var file *os.File
+		if file, err = os.Open(path); err == nil {
+			var migration Migration
+			if migration, err = Load(strings.TrimSuffix(filepath.Base(path), MigrationExtension), file); err == nil {
+				migrations = append(migrations, migration)
+			}
+			file.Close()
+		}
+	}
+
+	return migrations, err
+}
+
+// data returns the transaction data of the migration, built from the snapshot if it has a BuildFunc.
+func (migration Migration) data(ctx context.Context, snap eva.SnapshotChannel) (elem edn.Element, err error) {
+
+	data := migration.Tx
+	if migration.Build != nil {
+		data, err = migration.Build(ctx, snap)
+	}
+
+	if err == nil {
+		elem, err = operationsOf(data)
+	}
+
+	if err != nil {
+		err = edn.MakeErrorWithFormat(ErrInvalidMigration, "%s: %s", migration.Name, err.Error())
+	}
+
+	return elem, err
+}
+
+// operationsOf reads the vector of operations of transaction data, nil being none.
+func operationsOf(data interface{}) (elem edn.Element, err error) {
+
+	switch typed := data.(type) {
+	case nil:
+		elem, err = edn.NewVector()
+	case string:
+		elem, err = dataParser.Parse(typed)
+	case edn.Element:
+		elem = typed
+	case edn.Serializable:
+		var str string
+		if str, err = typed.Serialize(edn.EvaEdnMimeType); err == nil {
+			elem, err = dataParser.Parse(str)
+		}
+	default:
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Unsupported type: %T", data)
+	}
+
+	if err == nil && elem.ElementType() != edn.VectorType && elem.ElementType() != edn.ListType {
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Expected a vector of operations, got: %s", elem.String())
+	}
+
+	return elem, err
+}
@@ -0,0 +1,149 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("migration test", func() {
+
+	Context("building the migrations", func() {
+		It("should rename the idents", func() {
+			Ω(Rename(":book/title", ":book/name").String()).Should(BeEquivalentTo(`[[:db/add :book/title :db/ident :book/name]]`))
+		})
+
+		It("should add the enums", func() {
+			elem, err := AddEnums(":book.genre/poetry", ":book.genre/drama").Element()
+			Ω(err).Should(BeNil())
+
+			expected, err := edn.Parse(`[{:db/id #db/id [:db.part/user -1] :db/ident :book.genre/poetry}
+			                             {:db/id #db/id [:db.part/user -2] :db/ident :book.genre/drama}]`)
+			Ω(err).Should(BeNil())
+			Ω(elem.Equals(expected)).Should(BeTrue())
+		})
+
+		It("should read the transaction data", func() {
+			for _, data := range []interface{}{
+				`[[:db/add 1 :book/title "First Book"]]`,
+				eva.NewTx().Add(1, ":book/title", "First Book"),
+				eva.RawString(`[[:db/add 1 :book/title "First Book"]]`),
+			} {
+				elem, err := Migration{Name: "001", Tx: data}.data(context.Background(), nil)
+				Ω(err).Should(BeNil())
+				Ω(elem.String()).Should(BeEquivalentTo(`[[:db/add 1 :book/title "First Book"]]`))
+			}
+
+			elem, err := Migration{Name: "001"}.data(context.Background(), nil)
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo(`[]`))
+		})
+
+		It("should reject the data which is not a vector of operations", func() {
+			for _, data := range []interface{}{`{:db/id 1}`, `[unbalanced`, 42} {
+				_, err := Migration{Name: "001", Tx: data}.data(context.Background(), nil)
+				Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+				Ω(err.Error()).Should(ContainSubstring("001"))
+			}
+		})
+
+		It("should reject the data past the parser limits", func() {
+			_, err := Migration{Name: "001", Tx: strings.Repeat("[", edn.DefaultLimits.MaxDepth+1)}.data(context.Background(), nil)
+			Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+			Ω(err.Error()).Should(ContainSubstring(edn.ErrLimitExceeded.Message()))
+		})
+
+		It("should build the data from the snapshot", func() {
+			var seen eva.SnapshotChannel
+			migration := Migration{
+				Name: "001",
+				Build: func(ctx context.Context, snap eva.SnapshotChannel) (interface{}, error) {
+					seen = snap
+					return eva.NewTx().Retract(1, ":book/title", "First Book"), nil
+				},
+			}
+
+			db := newFakeDb()
+			snap, err := db.conn.LatestSnapshot()
+			Ω(err).Should(BeNil())
+
+			elem, err := migration.data(context.Background(), snap)
+			Ω(err).Should(BeNil())
+			Ω(seen).Should(BeIdenticalTo(snap))
+			Ω(elem.String()).Should(BeEquivalentTo(`[[:db/retract 1 :book/title "First Book"]]`))
+
+			migration.Build = func(ctx context.Context, snap eva.SnapshotChannel) (interface{}, error) {
+				return nil, edn.MakeError(eva.ErrSourceError, "unavailable")
+			}
+			_, err = migration.data(context.Background(), snap)
+			Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+		})
+	})
+
+	Context("loading the migrations", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "migrations")
+			Ω(err).Should(BeNil())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		write := func(name string, content string) {
+			Ω(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)).Should(BeNil())
+		}
+
+		It("should load a migration", func() {
+			migration, err := Load("001-books", strings.NewReader(`[[:db/add 1 :book/title "First Book"]]`))
+			Ω(err).Should(BeNil())
+			Ω(migration.Name).Should(BeEquivalentTo("001-books"))
+			Ω(migration.Tx.(edn.Element).String()).Should(BeEquivalentTo(`[[:db/add 1 :book/title "First Book"]]`))
+		})
+
+		It("should load the files in the order of their names", func() {
+			write("002-authors.edn", `[[:db/add 2 :author/name "James Madison"]]`)
+			write("001-books.edn", `[[:db/add 1 :book/title "First Book"]]`)
+			write("README.md", `not a migration`)
+
+			migrations, err := LoadDir(dir)
+			Ω(err).Should(BeNil())
+			Ω(migrations).Should(HaveLen(2))
+			Ω(migrations[0].Name).Should(BeEquivalentTo("001-books"))
+			Ω(migrations[1].Name).Should(BeEquivalentTo("002-authors"))
+		})
+
+		It("should reject the invalid files", func() {
+			write("001-books.edn", `{:db/id 1}`)
+
+			_, err := LoadDir(dir)
+			Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+			Ω(err.Error()).Should(ContainSubstring("001-books"))
+		})
+	})
+})
@@ -0,0 +1,265 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+const (
+	// ErrMigrationFailed defines the error for a migration the service rejected.
+	ErrMigrationFailed = edn.ErrorMessage("Migration failed")
+
+	// DefaultLease is how long a runner holds the lock of the migrations without applying one.
+	DefaultLease = 5 * time.Minute
+)
+
+// Option configures a migrator.
+type Option func(migrator *Migrator) error
+
+// WithDryRun makes Up build the transaction data of the pending migrations instead of committing them.
+func WithDryRun() Option {
+	return func(migrator *Migrator) error {
+		migrator.dryRun = true
+		return nil
+	}
+}
+
+// WithOwner sets the name of the runner holding the lock, the host and the process by default.
+func WithOwner(owner string) Option {
+	return func(migrator *Migrator) (err error) {
+		if owner != "" {
+			migrator.owner = owner
+		} else {
+			err = edn.MakeError(ErrInvalidMigration, "The owner must not be empty")
+		}
+		return err
+	}
+}
+
+// WithLease sets how long the lock is held after it is taken or a migration is applied, so that the lock of a runner
+// which died is eventually taken by another.
+func WithLease(lease time.Duration) Option {
+	return func(migrator *Migrator) (err error) {
+		if lease > 0 {
+			migrator.lease = lease
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidMigration, "Lease must be positive, got: %s", lease)
+		}
+		return err
+	}
+}
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Step is a migration applied by Up, or which would be in a dry run.
+type Step struct {
+	Name string
+
+	// Tx is the transaction data, along with the marker of the migration unless it is a dry run.
+	Tx edn.Element
+
+	// Report is the report of the transaction, nil in a dry run.
+	Report eva.TransactReport
+}
+
+// Migrator applies the migrations to the database of a connection. The names of the migrations applied are recorded
+// by marker entities of the database, in the same transaction as the migrations, so that every runner converges to
+// the same state. The runners take a lock in the database, the migrations of a runner which lost it failing.
+type Migrator struct {
+	conn       eva.ConnectionChannel
+	migrations []Migration
+	owner      string
+	lease      time.Duration
+	dryRun     bool
+}
+
+// New makes the migrator of the migrations, which are applied in their order.
+func New(conn eva.ConnectionChannel, migrations []Migration, options ...Option) (migrator *Migrator, err error) {
+
+	if conn == nil {
+		err = edn.MakeError(edn.ErrInvalidInput, "No connection")
+	}
+
+	names := map[string]bool{}
+	for _, migration := range migrations {
+		switch {
+		case err != nil:
+		case migration.Name == "":
+			err = edn.MakeError(ErrInvalidMigration, "A migration has no name")
+		case names[migration.Name]:
+			err = edn.MakeErrorWithFormat(ErrInvalidMigration, "%s is given twice", migration.Name)
+		case (migration.Tx == nil) == (migration.Build == nil):
+			err = edn.MakeErrorWithFormat(ErrInvalidMigration, "%s must have either a transaction or a build function", migration.Name)
+		}
+		names[migration.Name] = true
+	}
+
+	if err == nil {
+		hostname, _ := os.Hostname()
+		migrator = &Migrator{
+			conn:       conn,
+			migrations: migrations,
+			owner:      fmt.Sprintf("%s/%d/%d", hostname, os.Getpid(), time.Now().UnixNano()),
+			lease:      DefaultLease,
+		}
+
+		for _, option := range options {
+			if err = option(migrator); err != nil {
+				migrator = nil
+				break
+			}
+		}
+	}
+
+	return migrator, err
+}
+
+// Status returns the state of the migrations in the latest snapshot.
+func (migrator *Migrator) Status() ([]Status, error) {
+	return migrator.StatusContext(context.Background())
+}
+
+// StatusContext returns the state of the migrations in the latest snapshot, giving up when the context is done.
+func (migrator *Migrator) StatusContext(ctx context.Context) (statuses []Status, err error) {
+
+	var snap eva.SnapshotChannel
+	var applied map[string]time.Time
+	if snap, err = migrator.conn.LatestSnapshot(); err == nil {
+		applied, err = appliedMigrations(ctx, snap)
+	}
+
+	if err == nil {
+		for _, migration := range migrator.migrations {
+			at, has := applied[migration.Name]
+			statuses = append(statuses, Status{
+				Name:      migration.Name,
+				Applied:   has,
+				AppliedAt: at,
+			})
+		}
+	}
+
+	return statuses, err
+}
+
+// Up applies the migrations not applied yet, see UpContext.
+func (migrator *Migrator) Up() ([]Step, error) {
+	return migrator.UpContext(context.Background())
+}
+
+// UpContext installs the attributes recording the migrations, takes the lock and applies the migrations not applied
+// yet, one transaction each, stopping at the first which fails. The steps are the migrations applied. In a dry run,
+// nothing is committed and no lock is taken: the steps are the pending migrations with the data they would transact,
+// built against the latest snapshot.
+func (migrator *Migrator) UpContext(ctx context.Context) (steps []Step, err error) {
+
+	if migrator.dryRun {
+		steps, err = migrator.plan(ctx)
+	} else if _, err = eva.EnsureSchemaContext(ctx, migrator.conn, stateSchema()); err == nil {
+		if err = acquireLock(ctx, migrator.conn, migrator.owner, migrator.lease); err == nil {
+			steps, err = migrator.apply(ctx)
+
+			// the lock is released even when the context is done, other runners would wait for the lease otherwise
+			if releaseErr := releaseLock(context.Background(), migrator.conn, migrator.owner); err == nil {
+				err = releaseErr
+			}
+		}
+	}
+
+	return steps, err
+}
+
+// apply applies the pending migrations, the lock being held.
+func (migrator *Migrator) apply(ctx context.Context) (steps []Step, err error) {
+
+	var snap eva.SnapshotChannel
+	var applied map[string]time.Time
+	if snap, err = migrator.conn.LatestSnapshot(); err == nil {
+		applied, err = appliedMigrations(ctx, snap)
+	}
+
+	for _, migration := range migrator.migrations {
+		if _, has := applied[migration.Name]; err != nil || has {
+			continue
+		}
+
+		step := Step{
+			Name: migration.Name,
+		}
+
+		var operations edn.Element
+		if snap, err = migrator.conn.LatestSnapshot(); err == nil {
+			if operations, err = migration.data(ctx, snap); err == nil {
+				if step.Tx, err = marked(operations, migration.Name, migrator.owner, migrator.lease); err == nil {
+					if step.Report, err = transactState(ctx, migrator.conn, step.Tx); err != nil {
+						err = failed(migration, err)
+					}
+				}
+			}
+		}
+
+		if err == nil {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, err
+}
+
+// plan builds the data of the pending migrations against the latest snapshot.
+func (migrator *Migrator) plan(ctx context.Context) (steps []Step, err error) {
+
+	var snap eva.SnapshotChannel
+	var applied map[string]time.Time
+	if snap, err = migrator.conn.LatestSnapshot(); err == nil {
+		applied, err = appliedMigrations(ctx, snap)
+	}
+
+	for _, migration := range migrator.migrations {
+		if _, has := applied[migration.Name]; err != nil || has {
+			continue
+		}
+
+		step := Step{
+			Name: migration.Name,
+		}
+
+		step.Tx, err = migration.data(ctx, snap)
+
+		if err == nil {
+			steps = append(steps, step)
+		}
+	}
+
+	return steps, err
+}
+
+// failed reports the migration the service rejected, the error it was rejected with is kept so that it can still be
+// classified.
+func failed(migration Migration, err error) error {
+	return edn.MakeError(ErrMigrationFailed, fmt.Errorf("%s: %w", migration.Name, err))
+}
@@ -0,0 +1,252 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("migrator test", func() {
+
+	var db *fakeDb
+	var migrations []Migration
+
+	BeforeEach(func() {
+		db = newFakeDb()
+		migrations = []Migration{
+			{Name: "001-books", Tx: `[[:db/add 1 :book/title "First Book"]]`},
+			{Name: "002-rename", Tx: Rename(":book/title", ":book/name")},
+			{Name: "003-genres", Tx: AddEnums(":book.genre/poetry")},
+		}
+	})
+
+	names := func(steps []Step) (values []string) {
+		for _, step := range steps {
+			values = append(values, step.Name)
+		}
+		return values
+	}
+
+	Context("making the migrator", func() {
+		It("should reject the invalid migrations", func() {
+			for _, invalid := range [][]Migration{
+				{{Tx: `[]`}},
+				{{Name: "001", Tx: `[]`}, {Name: "001", Tx: `[]`}},
+				{{Name: "001"}},
+				{{Name: "001", Tx: `[]`, Build: func(context.Context, eva.SnapshotChannel) (interface{}, error) { return nil, nil }}},
+			} {
+				migrator, err := New(db.conn, invalid)
+				Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+				Ω(migrator).Should(BeNil())
+			}
+
+			_, err := New(nil, migrations)
+			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+		})
+
+		It("should reject the invalid options", func() {
+			for _, option := range []Option{WithOwner(""), WithLease(0)} {
+				migrator, err := New(db.conn, migrations, option)
+				Ω(err).Should(test.HaveMessage(ErrInvalidMigration))
+				Ω(migrator).Should(BeNil())
+			}
+		})
+	})
+
+	Context("applying the migrations", func() {
+		It("should apply the migrations in order, once", func() {
+			migrator, err := New(db.conn, migrations[:2], WithOwner("runner"))
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(names(steps)).Should(Equal([]string{"001-books", "002-rename"}))
+			Ω(steps[0].Report).ShouldNot(BeNil())
+			Ω(db.applied()).Should(Equal([]string{"001-books", "002-rename"}))
+			Ω(db.owner()).Should(BeEmpty())
+
+			migrator, err = New(db.conn, migrations, WithOwner("runner"))
+			Ω(err).Should(BeNil())
+
+			steps, err = migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(names(steps)).Should(Equal([]string{"003-genres"}))
+			Ω(db.applied()).Should(Equal([]string{"001-books", "002-rename", "003-genres"}))
+
+			steps, err = migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(steps).Should(BeEmpty())
+		})
+
+		It("should record the migrations in their transactions", func() {
+			migrator, err := New(db.conn, migrations[:1], WithOwner("runner"), WithLease(time.Minute))
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).Should(BeNil())
+
+			ops := steps[0].Tx.(edn.CollectionElement)
+			Ω(ops.Len()).Should(BeEquivalentTo(4))
+			Ω(db.transactions).Should(HaveLen(5))
+
+			first, err := ops.Get(0)
+			Ω(err).Should(BeNil())
+			Ω(first.String()).Should(BeEquivalentTo(`[:db/add 1 :book/title "First Book"]`))
+
+			marker, err := ops.Get(1)
+			Ω(err).Should(BeNil())
+			Ω(marker.ElementType()).Should(BeEquivalentTo(edn.MapType))
+			Ω(marker.String()).Should(ContainSubstring(`:eva.migration/name "001-books"`))
+			Ω(marker.String()).Should(ContainSubstring(`:db/id #db/id [:db.part/user]`))
+
+			cas, err := ops.Get(2)
+			Ω(err).Should(BeNil())
+			Ω(cas.String()).Should(BeEquivalentTo(`[:db.fn/cas [:eva.migration.lock/name "migrations"] :eva.migration.lock/owner "runner" "runner"]`))
+		})
+
+		It("should build the migrations from the latest snapshot", func() {
+			var built []string
+			migrator, err := New(db.conn, []Migration{
+				migrations[0],
+				{
+					Name: "002-backfill",
+					Build: func(ctx context.Context, snap eva.SnapshotChannel) (interface{}, error) {
+						built = db.applied()
+						return eva.NewTx().Add(1, ":book/year_published", 2017), nil
+					},
+				},
+			})
+			Ω(err).Should(BeNil())
+
+			_, err = migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(built).Should(Equal([]string{"001-books"}))
+			Ω(db.applied()).Should(Equal([]string{"001-books", "002-backfill"}))
+		})
+
+		It("should stop at the migration which fails", func() {
+			migrations[1].Tx = `[[:db/add 1 :test/fail true]]`
+			migrator, err := New(db.conn, migrations)
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).Should(test.HaveMessage(ErrMigrationFailed))
+			Ω(err.Error()).Should(ContainSubstring("002-rename"))
+			Ω(errors.Is(err, eva.ErrSourceError)).Should(BeTrue())
+			Ω(names(steps)).Should(Equal([]string{"001-books"}))
+			Ω(db.applied()).Should(Equal([]string{"001-books"}))
+			Ω(db.owner()).Should(BeEmpty())
+		})
+
+		It("should list the status of the migrations", func() {
+			migrator, err := New(db.conn, migrations[:1])
+			Ω(err).Should(BeNil())
+
+			statuses, err := migrator.Status()
+			Ω(err).Should(BeNil())
+			Ω(statuses).Should(Equal([]Status{{Name: "001-books"}}))
+
+			before := time.Now().Add(-time.Second)
+			_, err = migrator.Up()
+			Ω(err).Should(BeNil())
+
+			migrator, err = New(db.conn, migrations)
+			Ω(err).Should(BeNil())
+
+			statuses, err = migrator.Status()
+			Ω(err).Should(BeNil())
+			Ω(statuses).Should(HaveLen(3))
+			Ω(statuses[0].Applied).Should(BeTrue())
+			Ω(statuses[0].AppliedAt).Should(BeTemporally(">", before))
+			Ω(statuses[1:]).Should(Equal([]Status{{Name: "002-rename"}, {Name: "003-genres"}}))
+		})
+	})
+
+	Context("in a dry run", func() {
+		It("should commit nothing", func() {
+			migrator, err := New(db.conn, migrations, WithDryRun())
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(names(steps)).Should(Equal([]string{"001-books", "002-rename", "003-genres"}))
+			Ω(steps[0].Tx.String()).Should(BeEquivalentTo(`[[:db/add 1 :book/title "First Book"]]`))
+			Ω(steps[0].Report).Should(BeNil())
+			Ω(db.transactions).Should(BeEmpty())
+			Ω(db.applied()).Should(BeEmpty())
+		})
+
+		It("should report the migrations which cannot be built", func() {
+			migrations[2].Tx = `[[:db/add 1 :book/genre`
+			migrator, err := New(db.conn, migrations, WithDryRun())
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).ShouldNot(BeNil())
+			Ω(names(steps)).Should(Equal([]string{"001-books", "002-rename"}))
+			Ω(db.transactions).Should(BeEmpty())
+		})
+	})
+
+	Context("with concurrent runners", func() {
+		It("should not run while another runner holds the lock", func() {
+			db.take("other", time.Minute)
+			migrator, err := New(db.conn, migrations, WithOwner("runner"))
+			Ω(err).Should(BeNil())
+
+			steps, err := migrator.Up()
+			Ω(err).Should(test.HaveMessage(ErrMigrationLocked))
+			Ω(err.Error()).Should(ContainSubstring("other"))
+			Ω(steps).Should(BeEmpty())
+			Ω(db.applied()).Should(BeEmpty())
+			Ω(db.owner()).Should(BeEquivalentTo("other"))
+		})
+
+		It("should take the lock once it expires", func() {
+			db.take("other", -time.Minute)
+			migrator, err := New(db.conn, migrations, WithOwner("runner"))
+			Ω(err).Should(BeNil())
+
+			_, err = migrator.Up()
+			Ω(err).Should(BeNil())
+			Ω(db.applied()).Should(HaveLen(3))
+		})
+
+		It("should stop when another runner takes the lock", func() {
+			migrator, err := New(db.conn, migrations, WithOwner("runner"))
+			Ω(err).Should(BeNil())
+
+			db.beforeTransact = func() {
+				if len(db.applied()) == 1 {
+					db.take("other", time.Minute)
+				}
+			}
+
+			steps, err := migrator.Up()
+			Ω(err).Should(test.HaveMessage(ErrMigrationFailed))
+			Ω(names(steps)).Should(Equal([]string{"001-books"}))
+			Ω(db.applied()).Should(Equal([]string{"001-books"}))
+			Ω(db.owner()).Should(BeEquivalentTo("other"))
+		})
+	})
+})
@@ -0,0 +1,234 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+// FailAttribute makes the transactions asserting it fail.
+const FailAttribute = ":test/fail"
+
+type fakeResult struct {
+	*eva.BaseResult
+	err error
+}
+
+func newFakeResult(payload string, err error) *fakeResult {
+	return &fakeResult{
+		BaseResult: eva.NewBaseResult([]byte(payload)),
+		err:        err,
+	}
+}
+
+// Error from the call.
+func (result *fakeResult) Error() (error, bool) {
+	return result.err, result.err != nil
+}
+
+// fakeState is the state of the migrations in the fake database.
+type fakeState struct {
+	applied    []string
+	markers    map[string]time.Time
+	lockExists bool
+	owned      bool
+	owner      string
+	expires    time.Time
+}
+
+// copy the state, to apply a transaction to.
+func (state fakeState) copy() fakeState {
+	markers := make(map[string]time.Time, len(state.markers))
+	for name, at := range state.markers {
+		markers[name] = at
+	}
+	state.markers = markers
+	state.applied = append([]string{}, state.applied...)
+	return state
+}
+
+// fakeSource is the source of the fake database.
+type fakeSource struct {
+	*eva.BaseSource
+}
+
+// fakeDb keeps the state of the migrations, and of the lock, that the transactions change. The other operations are
+// kept as they are.
+type fakeDb struct {
+	lock         sync.Mutex
+	state        fakeState
+	transactions []string
+	queries      int
+	conn         eva.ConnectionChannel
+
+	// beforeTransact runs before a transaction is applied.
+	beforeTransact func()
+}
+
+func newFakeDb() *fakeDb {
+	db := &fakeDb{
+		state: fakeState{
+			markers: map[string]time.Time{},
+		},
+	}
+
+	config, err := eva.NewConfiguration(`{"category": "migrations"}`)
+	source := &fakeSource{}
+	if err == nil {
+		source.BaseSource, err = eva.NewBaseSource(config, nil, source, db.connection, db.query)
+	}
+	if err == nil {
+		db.conn, err = source.Connection("label")
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// connection makes the connection to the database.
+func (db *fakeDb) connection(label edn.Serializable, source eva.Source) (eva.ConnectionChannel, error) {
+	return eva.NewBaseConnectionChannel(
+		label,
+		source,
+		db.transact,
+		func(asOf edn.Serializable, options ...eva.SnapshotOption) (eva.SnapshotChannel, error) {
+			return eva.NewBaseSnapshotChannel(label, source, nil, nil, asOf, options...)
+		})
+}
+
+// query answers the queries of the markers and of the lock, the others find nothing.
+func (db *fakeDb) query(_ context.Context, query interface{}, _ ...interface{}) (eva.Result, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.queries++
+
+	str := fmt.Sprint(query)
+	payload := "[]"
+	switch {
+	case strings.Contains(str, AppliedAtAttribute):
+		var rows []string
+		for _, name := range db.state.applied {
+			rows = append(rows, fmt.Sprintf("[%q %s]", name, edn.NewInstantElement(db.state.markers[name]).String()))
+		}
+		payload = "[" + strings.Join(rows, " ") + "]"
+	case strings.Contains(str, LockOwnerAttribute):
+		payload = "nil"
+		if db.state.lockExists {
+			payload = "{}"
+			if db.state.owned {
+				payload = fmt.Sprintf("{%s %q %s %s}", LockOwnerAttribute, db.state.owner, LockExpiresAttribute,
+					edn.NewInstantElement(db.state.expires).String())
+			}
+		}
+	}
+
+	return newFakeResult(payload, nil), nil
+}
+
+// transact applies the transaction to the state, or none of it.
+func (db *fakeDb) transact(_ context.Context, transaction edn.Serializable) (result eva.Result, err error) {
+	if db.beforeTransact != nil {
+		db.beforeTransact()
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	var str string
+	var elem edn.Element
+	if str, err = transaction.Serialize(edn.EvaEdnMimeType); err == nil {
+		elem, err = edn.Parse(str)
+	}
+
+	if err == nil {
+		state := db.state.copy()
+		if failure := state.apply(elem); failure == nil {
+			db.state = state
+			db.transactions = append(db.transactions, str)
+			result = newFakeResult("{:tempids {}}", nil)
+		} else {
+			result = newFakeResult("{}", failure)
+		}
+	}
+
+	return result, err
+}
+
+// apply the operations to the state.
+func (state *fakeState) apply(ops edn.Element) (err error) {
+	return ops.(edn.CollectionElement).IterateChildren(func(_ edn.Element, op edn.Element) (e error) {
+		items := map[string]edn.Element{}
+		var args []edn.Element
+		op.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) error {
+			items[key.String()] = value
+			args = append(args, value)
+			return nil
+		})
+
+		switch {
+		case op.ElementType() == edn.MapType && items[NameAttribute] != nil:
+			name := items[NameAttribute].Value().(string)
+			if _, has := state.markers[name]; has {
+				e = edn.MakeErrorWithFormat(eva.ErrSourceError, "%s is not unique", name)
+			}
+			state.markers[name] = items[AppliedAtAttribute].Value().(time.Time)
+			state.applied = append(state.applied, name)
+		case op.ElementType() == edn.MapType && items[LockNameAttribute] != nil:
+			state.lockExists = true
+		case op.ElementType() == edn.MapType || len(args) < 4:
+		case args[2].String() == FailAttribute:
+			e = edn.MakeError(eva.ErrSourceError, FailAttribute)
+		case args[0].String() == ":db.fn/cas" && args[2].String() == LockOwnerAttribute:
+			old, isOld := args[3].Value().(string)
+			if !state.lockExists || isOld != state.owned || old != state.owner {
+				e = edn.MakeErrorWithFormat(eva.ErrSourceError, "The owner is %s", state.owner)
+			}
+			state.owned, state.owner = true, args[4].Value().(string)
+		case args[0].String() == ":db/add" && args[2].String() == LockExpiresAttribute:
+			state.expires = args[3].Value().(time.Time)
+		}
+		return e
+	})
+}
+
+// take makes the owner hold the lock for the lease.
+func (db *fakeDb) take(owner string, lease time.Duration) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.state.lockExists, db.state.owned, db.state.owner, db.state.expires = true, true, owner, time.Now().Add(lease)
+}
+
+// owner returns the runner holding the lock.
+func (db *fakeDb) owner() string {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return db.state.owner
+}
+
+// applied returns the names of the migrations applied.
+func (db *fakeDb) applied() []string {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	return append([]string{}, db.state.applied...)
+}
@@ -0,0 +1,243 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/eva"
+)
+
+const (
+	// ErrMigrationLocked defines the error for a migration run while another runner holds the lock.
+	ErrMigrationLocked = edn.ErrorMessage("Migrations locked by another runner")
+
+	// NameAttribute is the attribute of the marker entity recording the name of an applied migration.
+	NameAttribute = ":eva.migration/name"
+
+	// AppliedAtAttribute is the attribute of the marker entity recording when the migration was applied.
+	AppliedAtAttribute = ":eva.migration/applied-at"
+
+	// LockNameAttribute identifies the lock entity of the runners.
+	LockNameAttribute = ":eva.migration.lock/name"
+
+	// LockOwnerAttribute is the runner holding the lock, empty when it is free.
+	LockOwnerAttribute = ":eva.migration.lock/owner"
+
+	// LockExpiresAttribute is the time the lock is held until, another runner can take it afterwards.
+	LockExpiresAttribute = ":eva.migration.lock/expires"
+
+	// LockName is the name of the lock entity.
+	LockName = "migrations"
+)
+
+// stateSchema returns the attributes recording the state of the migrations.
+func stateSchema() *eva.Schema {
+	return eva.NewSchema(
+		eva.Attribute(NameAttribute).Type(eva.String).Unique(eva.UniqueValue).Doc("Name of an applied migration"),
+		eva.Attribute(AppliedAtAttribute).Type(eva.Instant).Doc("Time the migration was applied"),
+		eva.Attribute(LockNameAttribute).Type(eva.String).Unique(eva.UniqueIdentity).Doc("Name of the lock of the migration runners"),
+		eva.Attribute(LockOwnerAttribute).Type(eva.String).Doc("Runner holding the lock, empty when it is free"),
+		eva.Attribute(LockExpiresAttribute).Type(eva.Instant).Doc("Time the lock is held until"))
+}
+
+// lockRef is the lookup ref of the lock entity.
+func lockRef() []interface{} {
+	return []interface{}{LockNameAttribute, LockName}
+}
+
+// appliedMigrations reads the names of the migrations applied in the snapshot and when they were. The attributes are
+// matched by their idents so that a database without the state schema has no migration applied.
+func appliedMigrations(ctx context.Context, snap eva.SnapshotChannel) (applied map[string]time.Time, err error) {
+
+	query := eva.Find("?name", "?at").Where(
+		eva.Clause("?n", eva.IdentAttribute, NameAttribute),
+		eva.Clause("?a", eva.IdentAttribute, AppliedAtAttribute),
+		eva.Clause("?m", "?n", "?name"),
+		eva.Clause("?m", "?a", "?at"))
+
+	var result eva.QueryResult
+	var rows [][]edn.Element
+	if result, err = snap.QueryContext(ctx, query); err == nil {
+		rows, err = result.Relation()
+	}
+
+	applied = map[string]time.Time{}
+	for _, row := range rows {
+		name, isName := row[0].Value().(string)
+		at, isTime := row[1].Value().(time.Time)
+		if !isName || !isTime {
+			err = edn.MakeErrorWithFormat(ErrInvalidMigration, "Expected a name and a time, got: %s %s", row[0], row[1])
+			break
+		}
+		applied[name] = at
+	}
+
+	return applied, err
+}
+
+// lockState is the state of the lock entity.
+type lockState struct {
+	exists   bool
+	owned    bool
+	owner    string
+	expires  time.Time
+	readTime time.Time
+}
+
+// heldBy checks if a runner other than the owner holds the lock.
+func (state lockState) heldBy(owner string) bool {
+	return state.owner != "" && state.owner != owner && state.expires.After(state.readTime)
+}
+
+// readLock reads the lock entity from the latest snapshot of the connection.
+func readLock(ctx context.Context, conn eva.ConnectionChannel) (state lockState, err error) {
+
+	query := eva.Find(eva.PullOf("?l", eva.Pattern(LockOwnerAttribute, LockExpiresAttribute))).Scalar().
+		In("$", "?lock").
+		Where(eva.Clause("?l", LockNameAttribute, "?lock"))
+
+	var snap eva.SnapshotChannel
+	var result eva.QueryResult
+	var elem edn.Element
+	if snap, err = conn.LatestSnapshot(); err == nil {
+		if result, err = snap.QueryContext(ctx, query, edn.NewStringElement(LockName)); err == nil {
+			elem, err = result.Scalar()
+		}
+	}
+
+	state.readTime = time.Now()
+	if err == nil && elem != nil && elem.ElementType() == edn.MapType {
+		state.exists = true
+		err = elem.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) error {
+			switch key.String() {
+			case LockOwnerAttribute:
+				state.owned = true
+				state.owner, _ = value.Value().(string)
+			case LockExpiresAttribute:
+				state.expires, _ = value.Value().(time.Time)
+			}
+			return nil
+		})
+	}
+
+	return state, err
+}
+
+// transactState transacts the data, the transactions the service rejects failing with its error.
+func transactState(ctx context.Context, conn eva.ConnectionChannel, data interface{}) (report eva.TransactReport, err error) {
+	if report, err = conn.TransactContext(ctx, data); err == nil {
+		if resultErr, failed := report.Error(); failed {
+			err = resultErr
+		}
+	}
+	return report, err
+}
+
+// acquireLock takes the lock for the owner until the lease expires, unless another runner holds it. Two runners taking
+// it at once are told apart by the compare and swap of the owner.
+func acquireLock(ctx context.Context, conn eva.ConnectionChannel, owner string, lease time.Duration) (err error) {
+
+	var state lockState
+	if state, err = readLock(ctx, conn); err == nil && !state.exists {
+		_, err = transactState(ctx, conn, eva.NewTx().Entity(map[string]interface{}{
+			eva.EntityIdAttribute: eva.TempID{Partition: eva.UserPartition, N: -1},
+			LockNameAttribute:     LockName,
+		}))
+	}
+
+	if err == nil {
+		if state.heldBy(owner) {
+			err = lockedError(state)
+		} else {
+			var old interface{}
+			if state.owned {
+				old = state.owner
+			}
+
+			tx := eva.NewTx().
+				CAS(lockRef(), LockOwnerAttribute, old, owner).
+				Add(lockRef(), LockExpiresAttribute, state.readTime.Add(lease))
+			if _, err = transactState(ctx, conn, tx); err != nil {
+				if current, readErr := readLock(ctx, conn); readErr == nil && current.heldBy(owner) {
+					err = lockedError(current)
+				}
+			}
+		}
+	}
+
+	return err
+}
+
+// releaseLock frees the lock the owner holds.
+func releaseLock(ctx context.Context, conn eva.ConnectionChannel, owner string) (err error) {
+	tx := eva.NewTx().
+		CAS(lockRef(), LockOwnerAttribute, owner, "").
+		Add(lockRef(), LockExpiresAttribute, time.Now())
+	_, err = transactState(ctx, conn, tx)
+	return err
+}
+
+// lockedError reports the runner holding the lock.
+func lockedError(state lockState) error {
+	return edn.MakeErrorWithFormat(ErrMigrationLocked, "Held by %s until %s", state.owner, state.expires.Format(time.RFC3339))
+}
+
+// marked appends to the operations of a migration the marker recording it, along with the renewal of the lock of the
+// owner. The id of the marker, `#db/id [:db.part/user]`, is a temporary id no operation of the migration has, and the
+// owner of the lock is compared and swapped with itself so that the migration fails if another runner took the lock.
+func marked(operations edn.Element, name string, owner string, lease time.Duration) (elem edn.Element, err error) {
+
+	now := time.Now()
+	var partition edn.Element
+	var id edn.CollectionElement
+	if partition, err = eva.Ident(eva.UserPartition).Element(); err == nil {
+		if id, err = edn.NewVector(partition); err == nil {
+			err = id.SetTag(eva.TempIdTag)
+		}
+	}
+
+	var state edn.Element
+	if err == nil {
+		state, err = eva.NewTx().
+			Entity(map[string]interface{}{
+				eva.EntityIdAttribute: id,
+				NameAttribute:         name,
+				AppliedAtAttribute:    now,
+			}).
+			CAS(lockRef(), LockOwnerAttribute, owner, owner).
+			Add(lockRef(), LockExpiresAttribute, now.Add(lease)).
+			Element()
+	}
+
+	var items []edn.Element
+	for _, collection := range []edn.Element{operations, state} {
+		if err != nil {
+			break
+		}
+		err = collection.(edn.CollectionElement).IterateChildren(func(_ edn.Element, item edn.Element) error {
+			items = append(items, item)
+			return nil
+		})
+	}
+
+	if err == nil {
+		elem, err = edn.NewVector(items...)
+	}
+
+	return elem, err
+}
@@ -14,14 +14,20 @@
 
 package eva
 
-import "github.com/Workiva/eva-client-go/edn"
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
 
 type mockResult struct {
+	*BaseResult
 }
 
-// String version of the call.
-func (mock *mockResult) String() (string, bool) {
-	return "test", true
+func newMockResult() *mockResult {
+	return &mockResult{
+		BaseResult: NewBaseResult([]byte("test")),
+	}
 }
 
 // Error from the call.
@@ -29,7 +35,35 @@ func (mock *mockResult) Error() (error, bool) {
 	return nil, false
 }
 
+// mockTransactPayload is what the service returns from a transaction.
+const mockTransactPayload = `{
+	:tempids {-1 8796093023233, -2 8796093023234}
+	:eva.client.service/tempids {#db/id [:db.part/user -1] 8796093023233, #db/id [:db.part/user -2] 8796093023234}
+	:db-before #eva.client.service/snapshot-ref {:label "label" :as-of 1}
+	:db-after #eva.client.service/snapshot-ref {:label "label" :as-of 2}
+	:tx-data (#datom [4398046511106 3 #inst "2019-01-01T00:00:00.000-00:00" 4398046511106 true]
+	          #datom [8796093023233 8 "First Book" 4398046511106 true]
+	          #datom [8796093023234 9 "James Madison" 4398046511106 false])
+}`
+
+type mockPayloadResult struct {
+	*BaseResult
+}
+
+func newMockPayloadResult(payload string) *mockPayloadResult {
+	return &mockPayloadResult{
+		BaseResult: NewBaseResult([]byte(payload)),
+	}
+}
+
+// Error from the call.
+func (mock *mockPayloadResult) Error() (error, bool) {
+	return nil, false
+}
+
 type mockSource struct {
+	query      interface{}
+	parameters []interface{}
 }
 
 // Connections to the eva service.
@@ -39,7 +73,11 @@ func (source *mockSource) Tenant() Tenant {
 
 // Connection returns a specific connection channel based on the category of data.
 func (source *mockSource) Connection(category interface{}) (channel ConnectionChannel, err error) {
-	return nil, nil
+	var label edn.Serializable
+	if label, err = decodeSerializable(category); err == nil {
+		channel, err = makeMockConnChannel(label, source)
+	}
+	return channel, err
 }
 
 // LatestSnapshot returns the latest snapshot channel for the specified category of data.
@@ -48,12 +86,19 @@ func (source *mockSource) LatestSnapshot(category interface{}) (channel Snapshot
 }
 
 // AsOfSnapshot returns the snapshot channel of the specified category as of the rules provided.
-func (source *mockSource) AsOfSnapshot(category interface{}, asOf interface{}) (channel SnapshotChannel, err error) {
+func (source *mockSource) AsOfSnapshot(category interface{}, asOf interface{}, options ...SnapshotOption) (channel SnapshotChannel, err error) {
 	return nil, nil
 }
 
 // Query the source for data.
-func (source *mockSource) Query(query interface{}, parameters ...interface{}) (result Result, err error) {
+func (source *mockSource) Query(query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+	return nil, nil
+}
+
+// QueryContext queries the source for data, the query and its parameters are kept.
+func (source *mockSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+	source.query = query
+	source.parameters = parameters
 	return nil, nil
 }
 
@@ -62,27 +107,28 @@ func (source *mockSource) Serializer() (edn.Serializer, error) {
 	return nil, nil
 }
 
-func mockQuery(_ interface{}, _ ...interface{}) (Result, error) {
-	return &mockResult{}, nil
+func mockQuery(_ context.Context, _ interface{}, _ ...interface{}) (Result, error) {
+	return newMockResult(), nil
 }
 
 func makeMockConnChannel(label edn.Serializable, source Source) (c ConnectionChannel, e error) {
 	return NewBaseConnectionChannel(
 		label,
 		source,
-		func(transaction edn.Serializable) (Result, error) {
-			return &mockResult{}, nil
+		func(ctx context.Context, transaction edn.Serializable) (Result, error) {
+			return newMockPayloadResult(mockTransactPayload), nil
 		},
-		func(asOf edn.Serializable) (SnapshotChannel, error) {
+		func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error) {
 			return NewBaseSnapshotChannel(
 				label,
 				source,
-				func(pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (result Result, err error) {
-					return &mockResult{}, nil
+				func(ctx context.Context, pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (result Result, err error) {
+					return newMockResult(), nil
 				},
-				func(function edn.Serializable, parameters ...interface{}) (result Result, err error) {
-					return &mockResult{}, nil
+				func(ctx context.Context, function edn.Serializable, parameters ...interface{}) (result Result, err error) {
+					return newMockResult(), nil
 				},
-				asOf)
+				asOf,
+				options...)
 		})
 }
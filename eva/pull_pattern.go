@@ -0,0 +1,294 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidPullPattern defines the error for a pull pattern the builder cannot build.
+	ErrInvalidPullPattern = edn.ErrorMessage("Invalid pull pattern")
+
+	// Wildcard is the pull pattern spec of all the attributes of an entity.
+	Wildcard = "*"
+)
+
+// PullPattern is a pull pattern built spec by spec instead of written as a string. The specs are keywords, the
+// Wildcard, the attribute specs made with Attr and edn elements. The pattern is handed to SnapshotChannel.Pull as it
+// is:
+//
+//	pattern := eva.Pattern(":book/title", eva.Attr(":book/author").Nest(":person/name"))
+//	result, err := snap.Pull(pattern, id)
+type PullPattern struct {
+	specs []interface{}
+}
+
+// Pattern makes a pull pattern of the specs.
+func Pattern(specs ...interface{}) *PullPattern {
+	return &PullPattern{
+		specs: specs,
+	}
+}
+
+// Element builds the pattern, `[:book/title {:book/author [:person/name]}]`.
+func (pattern *PullPattern) Element() (elem edn.Element, err error) {
+
+	if len(pattern.specs) > 0 {
+		elems := make([]edn.Element, 0, len(pattern.specs))
+		for _, spec := range pattern.specs {
+			var specElem edn.Element
+			if specElem, err = pullSpecOf(spec); err != nil {
+				break
+			}
+			elems = append(elems, specElem)
+		}
+
+		if err == nil {
+			elem, err = edn.NewVector(elems...)
+		}
+	} else {
+		err = edn.MakeError(ErrInvalidPullPattern, "No specs")
+	}
+
+	return elem, err
+}
+
+// Serialize the pattern.
+func (pattern *PullPattern) Serialize(serializer edn.Serializer) (str string, err error) {
+	var elem edn.Element
+	if elem, err = pattern.Element(); err == nil {
+		str, err = elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the pattern, empty when it cannot be built.
+func (pattern *PullPattern) String() (str string) {
+	if elem, err := pattern.Element(); err == nil {
+		str = elem.String()
+	}
+	return str
+}
+
+// AttrSpec is the spec of an attribute in a pull pattern, with its options and the pattern of the entities it refers
+// to.
+type AttrSpec struct {
+	ident    string
+	options  []interface{}
+	nested   *PullPattern
+	depth    int
+	recurses bool
+}
+
+// Attr makes the spec of the attribute, which can be a reverse reference such as `:book/_author`.
+func Attr(ident string) *AttrSpec {
+	return &AttrSpec{
+		ident: ident,
+	}
+}
+
+// Reverse returns the reverse reference of the attribute, `:book/_author` for `:book/author`.
+func Reverse(ident string) string {
+	if split := strings.LastIndex(ident, "/"); split >= 0 && !strings.HasPrefix(ident[split+1:], "_") {
+		ident = ident[:split+1] + "_" + ident[split+1:]
+	}
+	return ident
+}
+
+// As sets the key the values of the attribute are found under in the result.
+func (spec *AttrSpec) As(alias interface{}) *AttrSpec {
+	spec.options = append(spec.options, ":as", alias)
+	return spec
+}
+
+// Limit sets the maximum number of values of a cardinality many attribute, a negative limit removes the default one.
+func (spec *AttrSpec) Limit(limit int) *AttrSpec {
+	var value interface{}
+	if limit >= 0 {
+		value = limit
+	}
+	spec.options = append(spec.options, ":limit", value)
+	return spec
+}
+
+// Default sets the value found when the entity does not have the attribute.
+func (spec *AttrSpec) Default(value interface{}) *AttrSpec {
+	spec.options = append(spec.options, ":default", value)
+	return spec
+}
+
+// Nest sets the pattern of the entities the attribute refers to, `{:book/author [:person/name]}`.
+func (spec *AttrSpec) Nest(specs ...interface{}) *AttrSpec {
+	spec.nested = Pattern(specs...)
+	spec.recurses = false
+	return spec
+}
+
+// Recurse follows the attribute with the pattern it is in, down to the depth, or without limit for 0,
+// `{:person/friend 2}` or `{:person/friend ...}`.
+func (spec *AttrSpec) Recurse(depth int) *AttrSpec {
+	spec.depth = depth
+	spec.recurses = true
+	spec.nested = nil
+	return spec
+}
+
+// Element builds the spec.
+func (spec *AttrSpec) Element() (elem edn.Element, err error) {
+
+	if elem, err = keywordOf(spec.ident); err != nil {
+		err = edn.MakeErrorWithFormat(ErrInvalidPullPattern, "Expected an attribute, got: %#v", spec.ident)
+	}
+
+	if err == nil && len(spec.options) > 0 {
+		items := []edn.Element{elem}
+		for i := 0; i < len(spec.options) && err == nil; i += 2 {
+			var value edn.Element
+			if value, err = edn.NewPrimitiveElement(spec.options[i+1]); err == nil {
+				items = append(items, keywordOrNil(spec.options[i].(string)), value)
+			} else {
+				err = edn.MakeErrorWithFormat(ErrInvalidPullPattern, "Unsupported %s: %#v", spec.options[i], spec.options[i+1])
+			}
+		}
+
+		if err == nil {
+			elem, err = edn.NewVector(items...)
+		}
+	}
+
+	var value edn.Element
+	switch {
+	case err != nil:
+	case spec.nested != nil:
+		value, err = spec.nested.Element()
+	case spec.recurses && spec.depth > 0:
+		value = edn.NewIntegerElement(int64(spec.depth))
+	case spec.recurses:
+		value = symbolOrNil("...")
+	}
+
+	if err == nil && value != nil {
+		var pair edn.Pair
+		if pair, err = edn.NewPair(elem, value); err == nil {
+			elem, err = edn.NewMap(pair)
+		}
+	}
+
+	return elem, err
+}
+
+// Serialize the spec.
+func (spec *AttrSpec) Serialize(serializer edn.Serializer) (str string, err error) {
+	var elem edn.Element
+	if elem, err = spec.Element(); err == nil {
+		str, err = elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the spec, empty when it cannot be built.
+func (spec *AttrSpec) String() (str string) {
+	if elem, err := spec.Element(); err == nil {
+		str = elem.String()
+	}
+	return str
+}
+
+// pullSpecOf reads a spec of a pull pattern.
+func pullSpecOf(spec interface{}) (elem edn.Element, err error) {
+	switch typed := spec.(type) {
+	case *AttrSpec:
+		elem, err = typed.Element()
+	case *PullPattern:
+		elem, err = typed.Element()
+	case edn.Element:
+		elem = typed
+	case string:
+		if typed == Wildcard {
+			elem = symbolOrNil(Wildcard)
+		} else {
+			elem, err = Attr(typed).Element()
+		}
+	default:
+		err = edn.MakeErrorWithFormat(ErrInvalidPullPattern, "Unsupported spec: %#v", spec)
+	}
+
+	return elem, err
+}
+
+// PatternOf makes the pattern of the struct, or of the structs of the slice, v points to. It holds the fields with an
+// `edn` tag naming an attribute, `edn:"book/title"` or `edn:":book/title"`, the fields of a struct, a pointer to one or
+// a slice of them are nested with the pattern of that struct. The results of the pattern decode into the struct with
+// Result.Decode.
+func PatternOf(v interface{}) (pattern *PullPattern, err error) {
+
+	structType := reflect.TypeOf(v)
+	for structType != nil && (structType.Kind() == reflect.Ptr || structType.Kind() == reflect.Slice || structType.Kind() == reflect.Array) {
+		structType = structType.Elem()
+	}
+
+	if structType != nil && structType.Kind() == reflect.Struct {
+		if pattern = patternOfStruct(structType, map[reflect.Type]bool{}); pattern == nil {
+			err = edn.MakeErrorWithFormat(ErrInvalidPullPattern, "No attribute tagged in: %s", structType)
+		}
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidPullPattern, "Expected a struct, got: %T", v)
+	}
+
+	return pattern, err
+}
+
+// patternOfStruct makes the pattern of the tagged fields of the struct, the structs being built are not nested again,
+// so that the types that refer to themselves only pull the ids of the entities they refer to.
+func patternOfStruct(structType reflect.Type, building map[reflect.Type]bool) (pattern *PullPattern) {
+
+	building[structType] = true
+	defer delete(building, structType)
+
+	var specs []interface{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get(edn.DecodeTag), ",")[0]
+		if field.PkgPath == "" && strings.Contains(tag, "/") {
+			spec := Attr(edn.KeywordPrefix + strings.TrimPrefix(tag, edn.KeywordPrefix))
+
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct && !building[fieldType] && !fieldType.Implements(elementType) {
+				if nested := patternOfStruct(fieldType, building); nested != nil {
+					spec.nested = nested
+				}
+			}
+
+			specs = append(specs, spec)
+		}
+	}
+
+	if len(specs) > 0 {
+		pattern = Pattern(specs...)
+	}
+
+	return pattern
+}
+
+// elementType is the type of the edn elements, which are not nested.
+var elementType = reflect.TypeOf((*edn.Element)(nil)).Elem()
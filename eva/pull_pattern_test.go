@@ -0,0 +1,227 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type pulledAuthor struct {
+	Id   int64  `edn:"db/id"`
+	Name string `edn:"person/name"`
+}
+
+type pulledBook struct {
+	Id      int64          `edn:"db/id"`
+	Title   string         `edn:":book/title"`
+	Year    int            `edn:"book/year_published,omitempty"`
+	Authors []pulledAuthor `edn:"book/author"`
+	Series  *pulledBook    `edn:"book/series"`
+	Note    string
+	Raw     edn.Element `edn:"book/raw"`
+	hidden  string      `edn:"book/hidden"`
+}
+
+var _ = Describe("pull pattern test", func() {
+
+	build := func(pattern *PullPattern) string {
+		elem, err := pattern.Element()
+		Ω(err).Should(BeNil())
+		return elem.String()
+	}
+
+	Context("building the patterns", func() {
+		It("should build the attributes and the wildcard", func() {
+			Ω(build(Pattern(Wildcard))).Should(BeEquivalentTo(`[*]`))
+			Ω(build(Pattern(":book/title", Attr(":book/year")))).Should(BeEquivalentTo(`[:book/title :book/year]`))
+			Ω(build(Pattern(Reverse(":book/author")))).Should(BeEquivalentTo(`[:book/_author]`))
+			Ω(Reverse(":book/_author")).Should(BeEquivalentTo(":book/_author"))
+			Ω(Attr(":book/year").Default(2000).String()).Should(BeEquivalentTo(`[:book/year :default 2000]`))
+		})
+
+		It("should build the options", func() {
+			pattern := Pattern(
+				Attr(":book/tags").Limit(5),
+				Attr(":book/reviews").Limit(-1),
+				Attr(":book/year").Default(2000).As("year"))
+			Ω(build(pattern)).Should(BeEquivalentTo(`[[:book/tags :limit 5] [:book/reviews :limit nil] [:book/year :default 2000 :as "year"]]`))
+		})
+
+		It("should nest the patterns", func() {
+			pattern := Pattern(":book/title",
+				Attr(":book/author").Nest(":person/name", Attr(Reverse(":book/author")).Limit(2).Nest(":book/title")))
+			Ω(build(pattern)).Should(BeEquivalentTo(`[:book/title {:book/author [:person/name {[:book/_author :limit 2] [:book/title]}]}]`))
+		})
+
+		It("should recurse", func() {
+			Ω(build(Pattern(":person/name", Attr(":person/friend").Recurse(0)))).Should(BeEquivalentTo(`[:person/name {:person/friend ...}]`))
+			Ω(build(Pattern(":person/name", Attr(":person/friend").Recurse(3)))).Should(BeEquivalentTo(`[:person/name {:person/friend 3}]`))
+		})
+
+		It("should reject the specs it cannot build", func() {
+			for _, pattern := range []*PullPattern{
+				Pattern(),
+				Pattern("book/title"),
+				Pattern(":book/title :book/year"),
+				Pattern(42),
+				Pattern(Attr(":book/year").Default(struct{}{})),
+				Pattern(Attr(":book/author").Nest()),
+			} {
+				_, err := pattern.Element()
+				Ω(err).Should(test.HaveMessage(ErrInvalidPullPattern))
+				Ω(pattern.String()).Should(BeEmpty())
+			}
+		})
+	})
+
+	Context("building the pattern of a struct", func() {
+		It("should follow the tagged fields", func() {
+			pattern, err := PatternOf(&pulledBook{})
+			Ω(err).Should(BeNil())
+			Ω(pattern.String()).Should(BeEquivalentTo(`[:db/id :book/title :book/year_published {:book/author [:db/id :person/name]} :book/series :book/raw]`))
+
+			many, err := PatternOf(&[]pulledBook{})
+			Ω(err).Should(BeNil())
+			Ω(many.String()).Should(BeEquivalentTo(pattern.String()))
+		})
+
+		It("should reject what is not a tagged struct", func() {
+			_, err := PatternOf(42)
+			Ω(err).Should(test.HaveMessage(ErrInvalidPullPattern))
+
+			_, err = PatternOf(nil)
+			Ω(err).Should(test.HaveMessage(ErrInvalidPullPattern))
+
+			_, err = PatternOf(&struct{ Title string }{})
+			Ω(err).Should(test.HaveMessage(ErrInvalidPullPattern))
+		})
+	})
+
+	Context("pulling", func() {
+
+		var snap SnapshotChannel
+		var patterns, ids, payloads []string
+
+		BeforeEach(func() {
+			patterns, ids, payloads = nil, nil, nil
+
+			var err error
+			snap, err = NewBaseSnapshotChannel(
+				edn.NewStringElement("label"),
+				&mockSource{},
+				func(ctx context.Context, pattern edn.Serializable, id edn.Serializable, params ...interface{}) (Result, error) {
+					p, e := pattern.Serialize(edn.EvaEdnMimeType)
+					Ω(e).Should(BeNil())
+					i, e := id.Serialize(edn.EvaEdnMimeType)
+					Ω(e).Should(BeNil())
+
+					patterns = append(patterns, p)
+					ids = append(ids, i)
+					return newMockPayloadResult(payloads[len(ids)-1]), nil
+				},
+				nil, nil)
+			Ω(err).Should(BeNil())
+		})
+
+		It("should pull with a built pattern", func() {
+			payloads = []string{`{:book/title "First Book"}`}
+			_, err := snap.Pull(Pattern(":book/title"), 1)
+			Ω(err).Should(BeNil())
+			Ω(patterns).Should(Equal([]string{`[:book/title]`}))
+
+			q := Find(PullOf("?b", Pattern(":book/title"))).Where(Clause("?b", ":book/title"))
+			Ω(q.String()).Should(BeEquivalentTo(`[:find (pull ?b [:book/title]) :where [?b :book/title]]`))
+		})
+
+		It("should pull many", func() {
+			payloads = []string{`{:book/title "First Book"}`, `{:book/title "Second Book"}`, `{:book/title "Third Book"}`}
+			result, err := snap.PullMany("[:book/title]", []interface{}{1, []interface{}{":book/isbn", "123"}, ":book/third"})
+			Ω(err).Should(BeNil())
+			Ω(ids).Should(Equal([]string{`1`, `[:book/isbn "123"]`, `:book/third`}))
+
+			var titles []map[string]string
+			Ω(result.Decode(&titles)).Should(BeNil())
+			Ω(titles).Should(HaveLen(3))
+			Ω(titles[1][":book/title"]).Should(BeEquivalentTo("Second Book"))
+
+			_, err = snap.PullMany("[:book/title]", []interface{}{struct{}{}})
+			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+		})
+
+		It("should not read a lookup ref as the ids to pull", func() {
+			payloads = []string{`{:book/title "First Book"}`, `{:book/title "Second Book"}`}
+			result, err := snap.PullMany("[:book/title]", []interface{}{":book/isbn", "123"})
+			Ω(err).Should(BeNil())
+			Ω(ids).Should(Equal([]string{`:book/isbn`, `"123"`}))
+
+			ids = nil
+			result, err = snap.PullMany("[:book/title]", []interface{}{[]interface{}{":book/isbn", "123"}})
+			Ω(err).Should(BeNil())
+			Ω(ids).Should(Equal([]string{`[:book/isbn "123"]`}))
+
+			var titles []map[string]string
+			Ω(result.Decode(&titles)).Should(BeNil())
+			Ω(titles).Should(HaveLen(1))
+		})
+
+		It("should pull into a struct", func() {
+			payloads = []string{`{:db/id 1 :book/title "First Book" :book/year_published 2017
+			            :book/author [{:db/id 2 :person/name "James Madison"} {:db/id 3 :person/name "Alexander Hamilton"}]
+			            :book/series {:db/id 4}}`}
+
+			var book pulledBook
+			Ω(snap.PullInto(1, &book)).Should(BeNil())
+			Ω(patterns[0]).Should(BeEquivalentTo(`[:db/id :book/title :book/year_published {:book/author [:db/id :person/name]} :book/series :book/raw]`))
+			Ω(ids[0]).Should(BeEquivalentTo(`1`))
+
+			Ω(book.Title).Should(BeEquivalentTo("First Book"))
+			Ω(book.Year).Should(BeEquivalentTo(2017))
+			Ω(book.Authors).Should(Equal([]pulledAuthor{{2, "James Madison"}, {3, "Alexander Hamilton"}}))
+			Ω(book.Series.Id).Should(BeEquivalentTo(4))
+		})
+
+		It("should pull many into a slice", func() {
+			payloads = []string{`{:db/id 1 :book/title "First Book"}`, `{:db/id 2 :book/title "Second Book"}`}
+
+			var books []pulledBook
+			Ω(snap.PullInto([]interface{}{1, 2}, &books)).Should(BeNil())
+			Ω(ids).Should(Equal([]string{`1`, `2`}))
+			Ω(books).Should(HaveLen(2))
+			Ω(books[1].Title).Should(BeEquivalentTo("Second Book"))
+		})
+
+		It("should report the failures", func() {
+			payloads = []string{`{:db/id 1}`}
+			var book pulledBook
+			Ω(snap.PullInto(1, book)).Should(test.HaveMessage(edn.ErrInvalidTarget))
+			Ω(snap.PullInto(1, &struct{}{})).Should(test.HaveMessage(ErrInvalidPullPattern))
+
+			failing, err := NewBaseSnapshotChannel(edn.NewStringElement("label"), &mockSource{},
+				func(ctx context.Context, pattern edn.Serializable, id edn.Serializable, params ...interface{}) (Result, error) {
+					return newMockFailedResult(), nil
+				}, nil, nil)
+			Ω(err).Should(BeNil())
+			Ω(failing.PullInto(1, &book)).Should(test.HaveMessage(ErrSourceError))
+
+			var books []pulledBook
+			Ω(failing.PullInto([]interface{}{1, 2}, &books)).Should(test.HaveMessage(ErrSourceError))
+		})
+	})
+})
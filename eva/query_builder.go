@@ -0,0 +1,407 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidQuery defines the error for a query the builder cannot build.
+	ErrInvalidQuery = edn.ErrorMessage("Invalid query")
+)
+
+// QueryPart is a part of a query made by the builder, such as a clause, a binding or an aggregate. Its error, if any,
+// is reported when the query is built. Parts are serializable, so that rules can be handed to a query as a parameter.
+type QueryPart interface {
+	edn.Serializable
+
+	// Element returns the part as an element, or the error that prevents building it.
+	Element() (edn.Element, error)
+}
+
+// queryPartImpl holds the element of a part, or the error building it.
+type queryPartImpl struct {
+	elem edn.Element
+	err  error
+}
+
+// Element returns the part as an element.
+func (part *queryPartImpl) Element() (edn.Element, error) {
+	return part.elem, part.err
+}
+
+// Serialize the part.
+func (part *queryPartImpl) Serialize(serializer edn.Serializer) (str string, err error) {
+	if err = part.err; err == nil {
+		str, err = part.elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the part, empty when it cannot be built.
+func (part *queryPartImpl) String() (str string) {
+	if part.err == nil {
+		str = part.elem.String()
+	}
+	return str
+}
+
+// Query is a datalog query built term by term instead of formatted into a string. The terms are:
+//
+//   - strings starting with `?`, `$`, `%` or `_`, which are symbols: variables, data sources, rules and blanks,
+//   - strings starting with `:`, which are keywords,
+//   - the other strings, which are string values, never read as edn,
+//   - numbers, booleans, times, uuids, edn elements and the parts made by the builder.
+//
+// Values from outside the program are best bound as inputs rather than put in the query. The query plugs into
+// Source.Query and SnapshotChannel.Query as it is:
+//
+//	q := eva.Find("?title").In("$", "?year").Where(
+//	    eva.Clause("?b", ":book/year_published", "?year"),
+//	    eva.Clause("?b", ":book/title", "?title"))
+//	result, err := snap.Query(q, edn.NewIntegerElement(2017))
+type Query struct {
+	shape FindShape
+	find  []interface{}
+	with  []interface{}
+	in    []interface{}
+	where []interface{}
+}
+
+// Find starts a query finding the elements, which can be variables, aggregates or pull expressions. The result is a
+// relation unless Scalar, Collection or Tuple is used.
+func Find(elements ...interface{}) *Query {
+	return &Query{
+		shape: RelationShape,
+		find:  elements,
+	}
+}
+
+// Scalar makes the query find a single value, `:find ?a .`.
+func (q *Query) Scalar() *Query {
+	q.shape = ScalarShape
+	return q
+}
+
+// Collection makes the query find a collection of values, `:find [?a ...]`.
+func (q *Query) Collection() *Query {
+	q.shape = CollectionShape
+	return q
+}
+
+// Tuple makes the query find a single tuple, `:find [?a ?b]`.
+func (q *Query) Tuple() *Query {
+	q.shape = TupleShape
+	return q
+}
+
+// With adds the variables to the `:with` of the query, to keep the duplicate values of an aggregate.
+func (q *Query) With(variables ...interface{}) *Query {
+	q.with = append(q.with, variables...)
+	return q
+}
+
+// In adds the inputs of the query: data sources, variables, bindings or `%` for the rules.
+func (q *Query) In(inputs ...interface{}) *Query {
+	q.in = append(q.in, inputs...)
+	return q
+}
+
+// Where adds the clauses of the query.
+func (q *Query) Where(clauses ...interface{}) *Query {
+	q.where = append(q.where, clauses...)
+	return q
+}
+
+// FindSpec returns the find spec the query is built with.
+func (q *Query) FindSpec() (spec FindSpec, err error) {
+	var elem edn.Element
+	if elem, err = q.Element(); err == nil {
+		spec, err = ParseFindSpec(elem)
+	}
+	return spec, err
+}
+
+// Element builds the query in the list form, `[:find ... :with ... :in ... :where ...]`.
+func (q *Query) Element() (elem edn.Element, err error) {
+
+	var find []edn.Element
+	if find, err = termsOf(q.find); err == nil {
+		switch {
+		case len(find) == 0:
+			err = edn.MakeError(ErrInvalidQuery, "Nothing to find")
+		case q.shape == ScalarShape && len(find) == 1:
+			find = append(find, symbolOrNil("."))
+		case q.shape == CollectionShape && len(find) == 1:
+			find, err = vectorOf(find[0], symbolOrNil("..."))
+		case q.shape == TupleShape:
+			find, err = vectorOf(find...)
+		case q.shape != RelationShape:
+			err = edn.MakeErrorWithFormat(ErrInvalidQuery, "A %s finds a single element, got: %d", q.shape, len(find))
+		}
+	}
+
+	items := find
+	for _, clause := range []struct {
+		keyword string
+		terms   []interface{}
+	}{{":with", q.with}, {":in", q.in}, {":where", q.where}} {
+		if err == nil && len(clause.terms) > 0 {
+			var terms []edn.Element
+			if terms, err = termsOf(clause.terms); err == nil {
+				items = append(append(items, keywordOrNil(clause.keyword)), terms...)
+			}
+		}
+	}
+
+	if err == nil {
+		elem, err = edn.NewVector(append([]edn.Element{keywordOrNil(":find")}, items...)...)
+	}
+
+	return elem, err
+}
+
+// Serialize the query.
+func (q *Query) Serialize(serializer edn.Serializer) (str string, err error) {
+	var elem edn.Element
+	if elem, err = q.Element(); err == nil {
+		str, err = elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the query, empty when it cannot be built.
+func (q *Query) String() (str string) {
+	if elem, err := q.Element(); err == nil {
+		str = elem.String()
+	}
+	return str
+}
+
+// Clause makes a data pattern, `[?e :attr ?v]`, the first term can be a data source, `[$a ?e :attr ?v]`.
+func Clause(terms ...interface{}) QueryPart {
+	return vectorPart(terms...)
+}
+
+// Pred makes a predicate clause, `[(> ?year 2000)]`.
+func Pred(fn string, args ...interface{}) QueryPart {
+	return vectorPart(call(fn, args))
+}
+
+// Fn makes a function expression, `[(str ?first " " ?last) ?name]`, the binding is a variable or a binding made with
+// BindTuple, BindCollection or BindRelation.
+func Fn(fn string, args []interface{}, binding interface{}) QueryPart {
+	return vectorPart(call(fn, args), binding)
+}
+
+// RuleCall makes the invocation of a rule, `(ancestor ?a ?b)`.
+func RuleCall(name string, args ...interface{}) QueryPart {
+	return call(name, args)
+}
+
+// OrClause makes an `(or ...)` clause, the clauses to join with AndClause.
+func OrClause(clauses ...interface{}) QueryPart {
+	return listPart(append([]interface{}{symbolOrNil("or")}, clauses...)...)
+}
+
+// OrJoin makes an `(or-join [?a] ...)` clause.
+func OrJoin(variables []interface{}, clauses ...interface{}) QueryPart {
+	return listPart(append([]interface{}{symbolOrNil("or-join"), vectorPart(variables...)}, clauses...)...)
+}
+
+// AndClause groups the clauses of a branch of an `or`, `(and ...)`.
+func AndClause(clauses ...interface{}) QueryPart {
+	return listPart(append([]interface{}{symbolOrNil("and")}, clauses...)...)
+}
+
+// NotClause makes a `(not ...)` clause.
+func NotClause(clauses ...interface{}) QueryPart {
+	return listPart(append([]interface{}{symbolOrNil("not")}, clauses...)...)
+}
+
+// NotJoin makes a `(not-join [?a] ...)` clause.
+func NotJoin(variables []interface{}, clauses ...interface{}) QueryPart {
+	return listPart(append([]interface{}{symbolOrNil("not-join"), vectorPart(variables...)}, clauses...)...)
+}
+
+// Aggregate makes an aggregate to find, `(count ?b)` or `(max 3 ?year)`.
+func Aggregate(fn string, args ...interface{}) QueryPart {
+	return call(fn, args)
+}
+
+// PullOf makes a pull expression to find, `(pull ?b [:book/title])`, the pattern is a string or an element.
+func PullOf(variable string, pattern interface{}) QueryPart {
+	part := &queryPartImpl{}
+	if str, is := pattern.(string); is {
+		if pattern, part.err = edn.Parse(str); part.err != nil {
+			part.err = edn.MakeErrorWithFormat(ErrInvalidQuery, "Pull pattern %#v: %s", str, part.err.Error())
+		}
+	}
+
+	if part.err == nil {
+		part = listPart(symbolOrNil("pull"), variable, pattern)
+	}
+
+	return part
+}
+
+// BindTuple makes a tuple binding, `[?a ?b]`.
+func BindTuple(variables ...interface{}) QueryPart {
+	return vectorPart(variables...)
+}
+
+// BindCollection makes a collection binding, `[?a ...]`.
+func BindCollection(variable interface{}) QueryPart {
+	return vectorPart(variable, symbolOrNil("..."))
+}
+
+// BindRelation makes a relation binding, `[[?a ?b]]`.
+func BindRelation(variables ...interface{}) QueryPart {
+	return vectorPart(vectorPart(variables...))
+}
+
+// Rule makes the definition of a rule, `[(ancestor ?a ?b) [?a :person/parent ?b]]`.
+func Rule(name string, variables []interface{}, clauses ...interface{}) QueryPart {
+	return vectorPart(append([]interface{}{call(name, variables)}, clauses...)...)
+}
+
+// Rules makes the set of rules handed to a query for its `%` input, the rules can have several definitions of the same
+// name to express alternatives.
+func Rules(rules ...QueryPart) QueryPart {
+	terms := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		terms = append(terms, rule)
+	}
+	return vectorPart(terms...)
+}
+
+// call makes the list of a function, predicate or rule applied to the arguments.
+func call(fn string, args []interface{}) *queryPartImpl {
+	part := &queryPartImpl{}
+
+	var sym edn.Element
+	if sym, part.err = symbolOf(fn); part.err == nil {
+		part = listPart(append([]interface{}{sym}, args...)...)
+	}
+
+	return part
+}
+
+// vectorPart makes a vector of the terms.
+func vectorPart(terms ...interface{}) *queryPartImpl {
+	part := &queryPartImpl{}
+
+	var elems []edn.Element
+	if elems, part.err = termsOf(terms); part.err == nil {
+		part.elem, part.err = edn.NewVector(elems...)
+	}
+
+	return part
+}
+
+// listPart makes a list of the terms.
+func listPart(terms ...interface{}) *queryPartImpl {
+	part := &queryPartImpl{}
+
+	var elems []edn.Element
+	if elems, part.err = termsOf(terms); part.err == nil {
+		part.elem, part.err = edn.NewList(elems...)
+	}
+
+	return part
+}
+
+// vectorOf wraps the elements in a vector, as the only element of the result.
+func vectorOf(elems ...edn.Element) (wrapped []edn.Element, err error) {
+	var vector edn.Element
+	if vector, err = edn.NewVector(elems...); err == nil {
+		wrapped = []edn.Element{vector}
+	}
+	return wrapped, err
+}
+
+// termsOf reads the terms.
+func termsOf(terms []interface{}) (elems []edn.Element, err error) {
+	elems = make([]edn.Element, 0, len(terms))
+	for _, term := range terms {
+		var elem edn.Element
+		if elem, err = termOf(term); err != nil {
+			break
+		}
+		elems = append(elems, elem)
+	}
+
+	return elems, err
+}
+
+// termOf reads a term of the query, see Query.
+func termOf(term interface{}) (elem edn.Element, err error) {
+	switch typed := term.(type) {
+	case nil:
+		elem = edn.NewNilElement()
+	case edn.Element:
+		elem = typed
+	case QueryPart:
+		elem, err = typed.Element()
+	case string:
+		switch {
+		case strings.HasPrefix(typed, edn.KeywordPrefix):
+			elem, err = keywordOf(typed)
+		case len(typed) > 0 && strings.ContainsRune("?$%_", rune(typed[0])):
+			elem, err = symbolOf(typed)
+		default:
+			elem = edn.NewStringElement(typed)
+		}
+	default:
+		if elem, err = edn.NewPrimitiveElement(term); err != nil {
+			err = edn.MakeErrorWithFormat(ErrInvalidQuery, "Unsupported term: %#v", term)
+		}
+	}
+
+	return elem, err
+}
+
+// symbolOf reads the symbol, which must be the whole of the string.
+func symbolOf(name string) (elem edn.Element, err error) {
+	if elem, err = edn.Parse(name); err != nil || elem.ElementType() != edn.SymbolType || elem.String() != name {
+		elem = nil
+		err = edn.MakeErrorWithFormat(ErrInvalidQuery, "Expected a symbol, got: %#v", name)
+	}
+	return elem, err
+}
+
+// keywordOf reads the keyword, which must be the whole of the string.
+func keywordOf(name string) (elem edn.Element, err error) {
+	if elem, err = edn.Parse(name); err != nil || elem.ElementType() != edn.KeywordType || elem.String() != name {
+		elem = nil
+		err = edn.MakeErrorWithFormat(ErrInvalidQuery, "Expected a keyword, got: %#v", name)
+	}
+	return elem, err
+}
+
+// symbolOrNil returns the symbol for the names the builder knows to be valid.
+func symbolOrNil(name string) edn.Element {
+	elem, _ := symbolOf(name)
+	return elem
+}
+
+// keywordOrNil returns the keyword for the names the builder knows to be valid.
+func keywordOrNil(name string) edn.Element {
+	elem, _ := keywordOf(name)
+	return elem
+}
@@ -0,0 +1,160 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("query builder test", func() {
+
+	build := func(q *Query) string {
+		elem, err := q.Element()
+		Ω(err).Should(BeNil())
+		return elem.String()
+	}
+
+	Context("building the find specs", func() {
+		It("should find a relation", func() {
+			q := Find("?title", "?year").Where(Clause("?b", ":book/title", "?title"), Clause("?b", ":book/year_published", "?year"))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?title ?year :where [?b :book/title ?title] [?b :book/year_published ?year]]`))
+
+			spec, err := q.FindSpec()
+			Ω(err).Should(BeNil())
+			Ω(spec.Shape).Should(BeEquivalentTo(RelationShape))
+		})
+
+		It("should find the other shapes", func() {
+			where := Clause("?b", ":book/title", "?title")
+
+			Ω(build(Find("?title").Scalar().Where(where))).Should(BeEquivalentTo(`[:find ?title . :where [?b :book/title ?title]]`))
+			Ω(build(Find("?title").Collection().Where(where))).Should(BeEquivalentTo(`[:find [?title ...] :where [?b :book/title ?title]]`))
+			Ω(build(Find("?b", "?title").Tuple().Where(where))).Should(BeEquivalentTo(`[:find [?b ?title] :where [?b :book/title ?title]]`))
+
+			for shape, q := range map[FindShape]*Query{
+				ScalarShape:     Find("?title").Scalar().Where(where),
+				CollectionShape: Find("?title").Collection().Where(where),
+				TupleShape:      Find("?b", "?title").Tuple().Where(where),
+			} {
+				spec, err := q.FindSpec()
+				Ω(err).Should(BeNil())
+				Ω(spec.Shape).Should(BeEquivalentTo(shape))
+			}
+		})
+
+		It("should find aggregates and pull expressions", func() {
+			q := Find(Aggregate("count", "?b"), Aggregate("max", 3, "?year")).With("?title").Where(Clause("?b", ":book/year_published", "?year"))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find (count ?b) (max 3 ?year) :with ?title :where [?b :book/year_published ?year]]`))
+
+			q = Find(PullOf("?b", "[:book/title {:book/author [:person/name]}]")).Where(Clause("?b", ":book/title"))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find (pull ?b [:book/title {:book/author [:person/name]}]) :where [?b :book/title]]`))
+		})
+
+		It("should reject the find specs it cannot build", func() {
+			_, err := Find().Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+
+			_, err = Find("?a", "?b").Scalar().Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+
+			_, err = Find("?a", "?b").Collection().Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+
+			_, err = Find(PullOf("?b", "[:book/title")).Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+		})
+	})
+
+	Context("building the clauses", func() {
+		It("should build the inputs and bindings", func() {
+			q := Find("?b").In("$", "?year", BindCollection("?title"), BindTuple("?a", "?z"), BindRelation("?x", "?y"), "%")
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?b :in $ ?year [?title ...] [?a ?z] [[?x ?y]] %]`))
+		})
+
+		It("should build the predicates and function expressions", func() {
+			q := Find("?name").Where(
+				Clause("?p", ":person/first", "?first"),
+				Clause("?p", ":person/year", "?year"),
+				Pred(">", "?year", 1700),
+				Fn("str", []interface{}{"?first", " ", "?last"}, "?name"),
+				Fn("ground", []interface{}{edn.NewIntegerElement(1)}, BindCollection("?one")))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?name :where [?p :person/first ?first] [?p :person/year ?year] [(> ?year 1700)] [(str ?first " " ?last) ?name] [(ground 1) [?one ...]]]`))
+		})
+
+		It("should build or, and, not and the joins", func() {
+			q := Find("?b").Where(
+				OrClause(Clause("?b", ":book/title", "First Book"), AndClause(Clause("?b", ":book/year_published", 2017), NotClause(Clause("?b", ":book/hidden", true)))),
+				OrJoin([]interface{}{"?b"}, Clause("?b", ":book/author", "_")),
+				NotJoin([]interface{}{"?b"}, Clause("$a", "?b", ":book/removed", "_")))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?b :where (or [?b :book/title "First Book"] (and [?b :book/year_published 2017] (not [?b :book/hidden true]))) (or-join [?b] [?b :book/author _]) (not-join [?b] [$a ?b :book/removed _])]`))
+		})
+
+		It("should build the rules", func() {
+			rules := Rules(
+				Rule("ancestor", []interface{}{"?a", "?b"}, Clause("?a", ":person/parent", "?b")),
+				Rule("ancestor", []interface{}{"?a", "?b"}, Clause("?a", ":person/parent", "?x"), RuleCall("ancestor", "?x", "?b")))
+
+			elem, err := rules.Element()
+			Ω(err).Should(BeNil())
+			Ω(elem.String()).Should(BeEquivalentTo(`[[(ancestor ?a ?b) [?a :person/parent ?b]] [(ancestor ?a ?b) [?a :person/parent ?x] (ancestor ?x ?b)]]`))
+
+			str, err := rules.Serialize(edn.EvaEdnMimeType)
+			Ω(err).Should(BeNil())
+			Ω(str).Should(BeEquivalentTo(elem.String()))
+
+			q := Find("?b").In("$", "%", "?a").Where(RuleCall("ancestor", "?a", "?b"))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?b :in $ % ?a :where (ancestor ?a ?b)]`))
+		})
+
+		It("should keep the strings as values", func() {
+			q := Find("?b").Where(Clause("?b", ":book/title", `"] [?b :book/secret ?s`))
+			Ω(build(q)).Should(BeEquivalentTo(`[:find ?b :where [?b :book/title "\"] [?b :book/secret ?s"]]`))
+		})
+
+		It("should reject the terms it cannot build", func() {
+			for _, q := range []*Query{
+				Find("?b").Where(Clause("?b", ":book/title ?t")),
+				Find("?b").Where(Clause("?b] [?c")),
+				Find("?b").Where(Pred("> ?year", "?year")),
+				Find("?b").Where(Clause("?b", struct{}{})),
+			} {
+				_, err := q.Element()
+				Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+				Ω(q.String()).Should(BeEmpty())
+
+				_, err = q.Serialize(edn.EvaEdnMimeType)
+				Ω(err).Should(test.HaveMessage(ErrInvalidQuery))
+			}
+		})
+	})
+
+	Context("querying", func() {
+		It("should bind the inputs of a built query", func() {
+			source := &mockSource{}
+			snap, err := NewBaseSnapshotChannel(edn.NewStringElement("label"), source, nil, nil, edn.NewIntegerElement(7))
+			Ω(err).Should(BeNil())
+
+			q := Find("?title").In("$", "?year").Where(Clause("?b", ":book/year_published", "?year"), Clause("?b", ":book/title", "?title"))
+			_, err = snap.Query(q, edn.NewIntegerElement(2017))
+			Ω(err).Should(BeNil())
+			Ω(source.query).Should(Equal(q))
+			Ω(source.parameters).Should(HaveLen(2))
+			Ω(source.parameters[0]).Should(Equal(snap.Reference()))
+		})
+	})
+})
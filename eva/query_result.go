@@ -0,0 +1,265 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrFindSpecMismatch defines the error for asking a query result for a shape its find spec does not give.
+	ErrFindSpecMismatch = edn.ErrorMessage("Find spec mismatch")
+
+	// ErrInvalidQueryResult defines the error for a query result that does not have the shape of its find spec.
+	ErrInvalidQueryResult = edn.ErrorMessage("Invalid query result")
+
+	// ErrNoRow defines the error for scanning without a current row.
+	ErrNoRow = edn.ErrorMessage("No current row")
+)
+
+// QueryResult is the result of a query, read according to the find spec of the query.
+type QueryResult interface {
+	Result
+
+	// FindSpec returns the find spec of the query, its shape is unknown when the query could not be read.
+	FindSpec() FindSpec
+
+	// Scalar returns the value of a `:find ?a .` query.
+	Scalar() (edn.Element, error)
+
+	// Collection returns the values of a `:find [?a ...]` query.
+	Collection() ([]edn.Element, error)
+
+	// Tuple returns the tuple of a `:find [?a ?b]` query.
+	Tuple() ([]edn.Element, error)
+
+	// Relation returns the tuples of a `:find ?a ?b` query.
+	Relation() ([][]edn.Element, error)
+
+	// Rows returns the result as rows, whatever its shape.
+	Rows() (Rows, error)
+}
+
+// Rows iterates over the rows of a query result.
+type Rows interface {
+
+	// Next moves to the next row, it returns false once the rows are exhausted.
+	Next() bool
+
+	// Scan decodes the values of the current row into the destinations, see edn.Decode.
+	Scan(dest ...interface{}) error
+
+	// Row returns the values of the current row.
+	Row() []edn.Element
+
+	// Len returns the number of rows.
+	Len() int
+}
+
+// queryResultImpl reads a result according to the find spec.
+type queryResultImpl struct {
+	Result
+	spec FindSpec
+}
+
+// newQueryResult wraps the result of the query, a query whose find spec can not be read leaves the shape unknown and
+// the result is then only checked against the shape asked for.
+func newQueryResult(query interface{}, result Result) (queryResult QueryResult) {
+	if result != nil {
+		spec, _ := ParseFindSpec(query)
+		queryResult = &queryResultImpl{
+			Result: result,
+			spec:   spec,
+		}
+	}
+
+	return queryResult
+}
+
+// FindSpec returns the find spec of the query.
+func (result *queryResultImpl) FindSpec() FindSpec {
+	return result.spec
+}
+
+// element returns the payload, once the result is known to be for the shape.
+func (result *queryResultImpl) element(shape FindShape) (elem edn.Element, err error) {
+	var failed bool
+	if err, failed = result.Error(); !failed {
+		if result.spec.Shape == UnknownShape || result.spec.Shape == shape {
+			elem, err = result.Element()
+		} else {
+			err = edn.MakeErrorWithFormat(ErrFindSpecMismatch, "The query finds a %s, not a %s", result.spec.Shape, shape)
+		}
+	}
+
+	return elem, err
+}
+
+// Scalar returns the value of a `:find ?a .` query.
+func (result *queryResultImpl) Scalar() (edn.Element, error) {
+	return result.element(ScalarShape)
+}
+
+// Collection returns the values of a `:find [?a ...]` query.
+func (result *queryResultImpl) Collection() (values []edn.Element, err error) {
+	var elem edn.Element
+	if elem, err = result.element(CollectionShape); err == nil {
+		var is bool
+		if values, is = sequenceOf(elem); !is {
+			err = edn.MakeErrorWithFormat(ErrInvalidQueryResult, "Expected a collection, got: %s", elem.String())
+		}
+	}
+
+	return values, err
+}
+
+// Tuple returns the tuple of a `:find [?a ?b]` query.
+func (result *queryResultImpl) Tuple() (tuple []edn.Element, err error) {
+	var elem edn.Element
+	if elem, err = result.element(TupleShape); err == nil {
+		tuple, err = result.tuple(elem)
+	}
+
+	return tuple, err
+}
+
+// Relation returns the tuples of a `:find ?a ?b` query.
+func (result *queryResultImpl) Relation() (relation [][]edn.Element, err error) {
+	var elem edn.Element
+	if elem, err = result.element(RelationShape); err == nil {
+		var rows []edn.Element
+		var is bool
+		if rows, is = sequenceOf(elem); is {
+			relation = make([][]edn.Element, 0, len(rows))
+			for _, row := range rows {
+				var tuple []edn.Element
+				if tuple, err = result.tuple(row); err != nil {
+					break
+				}
+				relation = append(relation, tuple)
+			}
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidQueryResult, "Expected a collection of tuples, got: %s", elem.String())
+		}
+	}
+
+	if err != nil {
+		relation = nil
+	}
+
+	return relation, err
+}
+
+// tuple reads a tuple with as many values as the find spec.
+func (result *queryResultImpl) tuple(elem edn.Element) (tuple []edn.Element, err error) {
+	var is bool
+	if tuple, is = sequenceOf(elem); is && elem.ElementType() != edn.SetType {
+		if columns := result.spec.Columns(); columns > 0 && len(tuple) != columns {
+			err = edn.MakeErrorWithFormat(ErrInvalidQueryResult, "Expected %d values in the tuple, got: %s", columns, elem.String())
+		}
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidQueryResult, "Expected a tuple, got: %v", elem)
+	}
+
+	if err != nil {
+		tuple = nil
+	}
+
+	return tuple, err
+}
+
+// Rows returns the result as rows, a scalar is a single row of one value, a collection is rows of one value, a tuple is
+// a single row and a relation is a row per tuple.
+func (result *queryResultImpl) Rows() (rows Rows, err error) {
+
+	var table [][]edn.Element
+	switch result.spec.Shape {
+	case ScalarShape:
+		var value edn.Element
+		if value, err = result.Scalar(); err == nil {
+			table = [][]edn.Element{{value}}
+		}
+	case CollectionShape:
+		var values []edn.Element
+		if values, err = result.Collection(); err == nil {
+			for _, value := range values {
+				table = append(table, []edn.Element{value})
+			}
+		}
+	case TupleShape:
+		var tuple []edn.Element
+		if tuple, err = result.Tuple(); err == nil {
+			table = [][]edn.Element{tuple}
+		}
+	default:
+		table, err = result.Relation()
+	}
+
+	if err == nil {
+		rows = &rowsImpl{
+			table:   table,
+			current: -1,
+		}
+	}
+
+	return rows, err
+}
+
+// rowsImpl iterates over the rows.
+type rowsImpl struct {
+	table   [][]edn.Element
+	current int
+}
+
+// Next moves to the next row, it returns false once the rows are exhausted.
+func (rows *rowsImpl) Next() bool {
+	if rows.current < len(rows.table) {
+		rows.current++
+	}
+	return rows.current < len(rows.table)
+}
+
+// Row returns the values of the current row.
+func (rows *rowsImpl) Row() (row []edn.Element) {
+	if rows.current >= 0 && rows.current < len(rows.table) {
+		row = rows.table[rows.current]
+	}
+	return row
+}
+
+// Len returns the number of rows.
+func (rows *rowsImpl) Len() int {
+	return len(rows.table)
+}
+
+// Scan decodes the values of the current row into the destinations, see edn.Decode.
+func (rows *rowsImpl) Scan(dest ...interface{}) (err error) {
+	if row := rows.Row(); row != nil {
+		if len(dest) == len(row) {
+			for i, value := range row {
+				if err = edn.Decode(value, dest[i]); err != nil {
+					break
+				}
+			}
+		} else {
+			err = edn.MakeErrorWithFormat(ErrFindSpecMismatch, "The row has %d values, got %d destinations", len(row), len(dest))
+		}
+	} else {
+		err = edn.MakeError(ErrNoRow, "Next has to be called, and return true, before Scan")
+	}
+
+	return err
+}
@@ -0,0 +1,233 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("query result test", func() {
+
+	const (
+		relationQuery   = "[:find ?title ?year :where [?b :book/title ?title] [?b :book/year ?year]]"
+		collectionQuery = "[:find [?title ...] :where [?b :book/title ?title]]"
+		tupleQuery      = "[:find [?title ?year] :where [?b :book/title ?title] [?b :book/year ?year]]"
+		scalarQuery     = "[:find ?title . :where [?b :book/title ?title]]"
+	)
+
+	query := func(query interface{}, payload string) QueryResult {
+		result := newQueryResult(query, newMockPayloadResult(payload))
+		Ω(result).ShouldNot(BeNil())
+		return result
+	}
+
+	Context("with the matching find spec", func() {
+		It("should read a relation", func() {
+			result := query(relationQuery, `[["First Book" 1999] ["Second Book" 2001]]`)
+			Ω(result.FindSpec().Shape).Should(BeEquivalentTo(RelationShape))
+
+			relation, err := result.Relation()
+			Ω(err).Should(BeNil())
+			Ω(relation).Should(HaveLen(2))
+			Ω(relation[1]).Should(HaveLen(2))
+			Ω(relation[1][0].Equals(edn.NewStringElement("Second Book"))).Should(BeTrue())
+		})
+
+		It("should read a collection", func() {
+			result := query(collectionQuery, `["First Book" "Second Book"]`)
+
+			values, err := result.Collection()
+			Ω(err).Should(BeNil())
+			Ω(values).Should(HaveLen(2))
+			Ω(values[0].Equals(edn.NewStringElement("First Book"))).Should(BeTrue())
+		})
+
+		It("should read a tuple", func() {
+			result := query(tupleQuery, `["First Book" 1999]`)
+
+			tuple, err := result.Tuple()
+			Ω(err).Should(BeNil())
+			Ω(tuple).Should(HaveLen(2))
+			Ω(tuple[1].Equals(edn.NewIntegerElement(1999))).Should(BeTrue())
+		})
+
+		It("should read a scalar", func() {
+			result := query(scalarQuery, `"First Book"`)
+
+			value, err := result.Scalar()
+			Ω(err).Should(BeNil())
+			Ω(value.Equals(edn.NewStringElement("First Book"))).Should(BeTrue())
+
+			value, err = query(scalarQuery, "nil").Scalar()
+			Ω(err).Should(BeNil())
+			Ω(value.ElementType()).Should(BeEquivalentTo(edn.NilType))
+		})
+	})
+
+	Context("with the wrong find spec", func() {
+		It("should name the shapes", func() {
+			_, err := query(scalarQuery, `"First Book"`).Collection()
+			Ω(err).Should(test.HaveMessage(ErrFindSpecMismatch))
+			Ω(err.Error()).Should(ContainSubstring("The query finds a scalar, not a collection"))
+
+			_, err = query(relationQuery, `[]`).Tuple()
+			Ω(err).Should(test.HaveMessage(ErrFindSpecMismatch))
+
+			_, err = query(tupleQuery, `[]`).Relation()
+			Ω(err).Should(test.HaveMessage(ErrFindSpecMismatch))
+
+			_, err = query(collectionQuery, `[]`).Scalar()
+			Ω(err).Should(test.HaveMessage(ErrFindSpecMismatch))
+		})
+
+		It("should check the result against the find spec", func() {
+			_, err := query(relationQuery, `[["First Book"]]`).Relation()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+
+			_, err = query(relationQuery, `"First Book"`).Relation()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+
+			_, err = query(relationQuery, `[#{"First Book" 1999}]`).Relation()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+
+			_, err = query(tupleQuery, `["First Book" 1999 3]`).Tuple()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+
+			_, err = query(collectionQuery, `"First Book"`).Collection()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+		})
+	})
+
+	Context("with an unknown find spec", func() {
+		It("should only check the result", func() {
+			result := query(RawString("not a query"), `[["First Book" 1999]]`)
+			Ω(result.FindSpec().Shape).Should(BeEquivalentTo(UnknownShape))
+
+			relation, err := result.Relation()
+			Ω(err).Should(BeNil())
+			Ω(relation).Should(HaveLen(1))
+
+			values, err := result.Collection()
+			Ω(err).Should(BeNil())
+			Ω(values).Should(HaveLen(1))
+
+			_, err = query(nil, `"First Book"`).Tuple()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+		})
+	})
+
+	It("should return the error of the result", func() {
+		result := newQueryResult(scalarQuery, newMockFailedResult())
+		_, err := result.Scalar()
+		Ω(err).Should(test.HaveMessage(ErrSourceError))
+
+		_, err = result.Rows()
+		Ω(err).Should(test.HaveMessage(ErrSourceError))
+	})
+
+	It("should not wrap a missing result", func() {
+		Ω(newQueryResult(scalarQuery, nil)).Should(BeNil())
+	})
+
+	Context("with rows", func() {
+		It("should scan a relation", func() {
+			rows, err := query(relationQuery, `[["First Book" 1999] ["Second Book" 2001]]`).Rows()
+			Ω(err).Should(BeNil())
+			Ω(rows.Len()).Should(BeEquivalentTo(2))
+
+			var titles []string
+			var years []int
+			for rows.Next() {
+				var title string
+				var year int
+				Ω(rows.Scan(&title, &year)).Should(BeNil())
+				titles = append(titles, title)
+				years = append(years, year)
+			}
+
+			Ω(titles).Should(BeEquivalentTo([]string{"First Book", "Second Book"}))
+			Ω(years).Should(BeEquivalentTo([]int{1999, 2001}))
+			Ω(rows.Next()).Should(BeFalse())
+			Ω(rows.Row()).Should(BeNil())
+		})
+
+		It("should give a row per shape", func() {
+			var title string
+			var year int
+
+			rows, err := query(collectionQuery, `["First Book" "Second Book"]`).Rows()
+			Ω(err).Should(BeNil())
+			Ω(rows.Len()).Should(BeEquivalentTo(2))
+			Ω(rows.Next()).Should(BeTrue())
+			Ω(rows.Scan(&title)).Should(BeNil())
+			Ω(title).Should(BeEquivalentTo("First Book"))
+
+			rows, err = query(tupleQuery, `["Third Book" 2003]`).Rows()
+			Ω(err).Should(BeNil())
+			Ω(rows.Len()).Should(BeEquivalentTo(1))
+			Ω(rows.Next()).Should(BeTrue())
+			Ω(rows.Scan(&title, &year)).Should(BeNil())
+			Ω(title).Should(BeEquivalentTo("Third Book"))
+			Ω(year).Should(BeEquivalentTo(2003))
+
+			rows, err = query(scalarQuery, `"Fourth Book"`).Rows()
+			Ω(err).Should(BeNil())
+			Ω(rows.Len()).Should(BeEquivalentTo(1))
+			Ω(rows.Next()).Should(BeTrue())
+			Ω(rows.Row()).Should(HaveLen(1))
+			Ω(rows.Scan(&title)).Should(BeNil())
+			Ω(title).Should(BeEquivalentTo("Fourth Book"))
+			Ω(rows.Next()).Should(BeFalse())
+		})
+
+		It("should fail to scan", func() {
+			rows, err := query(relationQuery, `[["First Book" 1999]]`).Rows()
+			Ω(err).Should(BeNil())
+
+			var title string
+			var year int
+			Ω(rows.Scan(&title, &year)).Should(test.HaveMessage(ErrNoRow))
+
+			Ω(rows.Next()).Should(BeTrue())
+			Ω(rows.Scan(&title)).Should(test.HaveMessage(ErrFindSpecMismatch))
+			Ω(rows.Scan(&year, &title)).Should(test.HaveMessage(edn.ErrTypeMismatch))
+
+			_, err = query(relationQuery, `[["First Book"]]`).Rows()
+			Ω(err).Should(test.HaveMessage(ErrInvalidQueryResult))
+		})
+	})
+
+	It("should be returned by the source", func() {
+		config, err := NewConfiguration("{\"category\": \"foo\"}")
+		Ω(err).Should(BeNil())
+
+		tenant, err := NewTenant("foo")
+		Ω(err).Should(BeNil())
+
+		source, err := NewBaseSource(config, tenant, &mockSource{}, makeMockConnChannel, mockQuery)
+		Ω(err).Should(BeNil())
+
+		result, err := source.Query(scalarQuery)
+		Ω(err).Should(BeNil())
+		Ω(result.FindSpec().Shape).Should(BeEquivalentTo(ScalarShape))
+
+		value, err := result.Scalar()
+		Ω(err).Should(BeNil())
+		Ω(value.String()).Should(BeEquivalentTo("test"))
+	})
+})
@@ -34,6 +34,12 @@ const (
 	ErrInvalidSerializer   = edn.ErrorMessage("Invalid serializer")
 	LabelReferenceProperty = "label"
 	AsOfReferenceProperty  = "as-of"
+
+	// SinceReferenceProperty holds the basis of a since snapshot.
+	SinceReferenceProperty = "since"
+
+	// HistoryReferenceProperty marks a history snapshot.
+	HistoryReferenceProperty = "history"
 )
 
 type refImpl struct {
@@ -133,14 +139,22 @@ func NewConnectionReference(label string) (ref Reference, err error) {
 	})
 }
 
-func NewSnapshotAsOfReference(label string, asOf interface{}) (ref Reference, err error) {
+// NewSnapshotAsOfReference creates the reference of the snapshot as of a t, the entity id of a transaction or a
+// time.Time, the options set the view of the snapshot.
+func NewSnapshotAsOfReference(label string, asOf interface{}, options ...SnapshotOption) (ref Reference, err error) {
 
 	var asOfElem edn.Serializable
 	if asOfElem, err = decodeSerializable(asOf); err == nil {
-		ref, err = newReference(SnapshotReferenceType, map[string]edn.Serializable{
+		if ref, err = newReference(SnapshotReferenceType, map[string]edn.Serializable{
 			LabelReferenceProperty: RawString(label),
 			AsOfReferenceProperty:  asOfElem,
-		})
+		}); err == nil {
+			err = applySnapshotOptions(ref, options...)
+		}
+	}
+
+	if err != nil {
+		ref = nil
 	}
 
 	return ref, err
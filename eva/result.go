@@ -14,6 +14,8 @@
 
 package eva
 
+import "github.com/Workiva/eva-client-go/edn"
+
 // Result of the call.
 type Result interface {
 
@@ -22,4 +24,13 @@ type Result interface {
 
 	// Error from the call.
 	Error() (error, bool)
+
+	// Raw returns the payload as it was received.
+	Raw() []byte
+
+	// Element returns the parsed payload, it is parsed once and then cached.
+	Element() (edn.Element, error)
+
+	// Decode the payload into the value pointed to by v, see edn.Decode.
+	Decode(v interface{}) error
 }
@@ -0,0 +1,605 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidSchema defines the error for an attribute definition that cannot be installed.
+	ErrInvalidSchema = edn.ErrorMessage("Invalid schema")
+
+	// ErrIncompatibleSchema defines the error for an attribute installed with a definition the schema cannot change.
+	ErrIncompatibleSchema = edn.ErrorMessage("Incompatible schema")
+
+	// DbPartition defines the partition of the attributes.
+	DbPartition = ":db.part/db"
+
+	// IdentAttribute is the attribute holding the ident of an entity.
+	IdentAttribute = ":db/ident"
+)
+
+// ValueType is the `:db.type/*` type of the values of an attribute.
+type ValueType string
+
+const (
+	String  = ValueType(edn.StringType)
+	Keyword = ValueType(edn.KeywordType)
+	Boolean = ValueType(edn.BooleanType)
+	Long    = ValueType(edn.IntegerType)
+	BigInt  = ValueType(edn.BigIntType)
+	Float   = ValueType(edn.FloatType)
+	Double  = ValueType(edn.DoubleType)
+	BigDec  = ValueType(edn.BigDecType)
+	Ref     = ValueType(edn.RefType)
+	Instant = ValueType(edn.InstantType)
+	UUID    = ValueType(edn.UUIDType)
+	URI     = ValueType(edn.URIType)
+	Bytes   = ValueType(edn.BytesType)
+)
+
+// valueTypes are the types an attribute can have.
+var valueTypes = map[ValueType]bool{
+	String: true, Keyword: true, Boolean: true, Long: true, BigInt: true, Float: true, Double: true, BigDec: true,
+	Ref: true, Instant: true, UUID: true, URI: true, Bytes: true,
+}
+
+// Cardinality tells if an attribute has one value or many.
+type Cardinality string
+
+const (
+	One  = Cardinality(":db.cardinality/one")
+	Many = Cardinality(":db.cardinality/many")
+)
+
+// Uniqueness tells if the values of an attribute are unique among the entities.
+type Uniqueness string
+
+const (
+	// UniqueValue rejects a value another entity already has.
+	UniqueValue = Uniqueness(":db.unique/value")
+
+	// UniqueIdentity resolves a temporary id to the entity that already has the value.
+	UniqueIdentity = Uniqueness(":db.unique/identity")
+)
+
+// AttributeDef is the definition of an attribute, as declared by an AttributeSchema or as installed in a database.
+type AttributeDef struct {
+
+	// Id is the entity id of an installed attribute, 0 for a declared one.
+	Id int64
+
+	Ident       string
+	ValueType   ValueType
+	Cardinality Cardinality
+	Doc         string
+	Unique      Uniqueness
+	Index       bool
+	Fulltext    bool
+	IsComponent bool
+	NoHistory   bool
+
+	// Enums are the idents of the values of a reference attribute used as an enum, i.e. `:book.genre/fiction`.
+	Enums []string
+}
+
+// String of the definition, the entity map that installs it without its id.
+func (def AttributeDef) String() string {
+	parts := []string{
+		IdentAttribute + " " + def.Ident,
+		":db/valueType " + string(def.ValueType),
+		":db/cardinality " + string(def.Cardinality),
+	}
+	if def.Doc != "" {
+		parts = append(parts, fmt.Sprintf(":db/doc %q", def.Doc))
+	}
+	if def.Unique != "" {
+		parts = append(parts, ":db/unique "+string(def.Unique))
+	}
+	for flag, set := range map[string]bool{
+		":db/index":       def.Index,
+		":db/fulltext":    def.Fulltext,
+		":db/isComponent": def.IsComponent,
+		":db/noHistory":   def.NoHistory,
+	} {
+		if set {
+			parts = append(parts, flag+" true")
+		}
+	}
+	sort.Strings(parts[3:])
+	if len(def.Enums) > 0 {
+		parts = append(parts, ":enums ["+strings.Join(def.Enums, " ")+"]")
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// AttributeSchema declares an attribute, instead of writing its `:db.install/_attribute` entity map by hand:
+//
+//	title := eva.Attribute(":book/title").Type(eva.String).Cardinality(eva.One).Doc("Title of a book")
+//	genre := eva.Attribute(":book/genre").Type(eva.Ref).Enum(":book.genre/fiction", ":book.genre/history")
+type AttributeSchema struct {
+	def AttributeDef
+}
+
+// Attribute starts the declaration of the attribute, of cardinality one unless told otherwise.
+func Attribute(ident string) *AttributeSchema {
+	return &AttributeSchema{
+		def: AttributeDef{
+			Ident:       ident,
+			Cardinality: One,
+		},
+	}
+}
+
+// Type sets the type of the values.
+func (attr *AttributeSchema) Type(valueType ValueType) *AttributeSchema {
+	attr.def.ValueType = valueType
+	return attr
+}
+
+// Cardinality sets if the attribute has one value or many.
+func (attr *AttributeSchema) Cardinality(cardinality Cardinality) *AttributeSchema {
+	attr.def.Cardinality = cardinality
+	return attr
+}
+
+// Doc sets the documentation of the attribute.
+func (attr *AttributeSchema) Doc(doc string) *AttributeSchema {
+	attr.def.Doc = doc
+	return attr
+}
+
+// Unique makes the values unique among the entities.
+func (attr *AttributeSchema) Unique(unique Uniqueness) *AttributeSchema {
+	attr.def.Unique = unique
+	return attr
+}
+
+// Index indexes the values in AVET.
+func (attr *AttributeSchema) Index() *AttributeSchema {
+	attr.def.Index = true
+	return attr
+}
+
+// Fulltext indexes the values of a string attribute for fulltext search.
+func (attr *AttributeSchema) Fulltext() *AttributeSchema {
+	attr.def.Fulltext = true
+	return attr
+}
+
+// IsComponent makes the entities a reference attribute refers to components of the entity, retracted along with it.
+func (attr *AttributeSchema) IsComponent() *AttributeSchema {
+	attr.def.IsComponent = true
+	return attr
+}
+
+// NoHistory keeps only the current values of the attribute.
+func (attr *AttributeSchema) NoHistory() *AttributeSchema {
+	attr.def.NoHistory = true
+	return attr
+}
+
+// Enum declares the idents of the values of a reference attribute, which are installed along with the attribute.
+func (attr *AttributeSchema) Enum(idents ...string) *AttributeSchema {
+	attr.def.Enums = append(attr.def.Enums, idents...)
+	return attr
+}
+
+// Def returns the definition of the attribute, once checked.
+func (attr *AttributeSchema) Def() (def AttributeDef, err error) {
+
+	def = attr.def
+	if _, err = keywordOf(def.Ident); err != nil {
+		err = edn.MakeErrorWithFormat(ErrInvalidSchema, "Expected an attribute ident, got: %#v", def.Ident)
+	}
+
+	if err == nil {
+		switch {
+		case !valueTypes[def.ValueType]:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s has no valid type: %#v", def.Ident, string(def.ValueType))
+		case def.Cardinality != One && def.Cardinality != Many:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s has no valid cardinality: %#v", def.Ident, string(def.Cardinality))
+		case def.Unique != "" && def.Unique != UniqueValue && def.Unique != UniqueIdentity:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s has no valid uniqueness: %#v", def.Ident, string(def.Unique))
+		case def.Fulltext && def.ValueType != String:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s is fulltext but not a string", def.Ident)
+		case def.IsComponent && def.ValueType != Ref:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s is a component but not a reference", def.Ident)
+		case len(def.Enums) > 0 && def.ValueType != Ref:
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s has enums but is not a reference", def.Ident)
+		}
+	}
+
+	for _, enum := range def.Enums {
+		if err != nil {
+			break
+		}
+		if _, err = keywordOf(enum); err != nil {
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s has an invalid enum: %#v", def.Ident, enum)
+		}
+	}
+
+	return def, err
+}
+
+// Schema is a set of attributes. It is handed to Transact as it is, or to EnsureSchema to install only what is missing.
+type Schema struct {
+	attributes []*AttributeSchema
+}
+
+// NewSchema makes a schema of the attributes.
+func NewSchema(attributes ...*AttributeSchema) *Schema {
+	return &Schema{
+		attributes: attributes,
+	}
+}
+
+// Add adds the attributes to the schema.
+func (schema *Schema) Add(attributes ...*AttributeSchema) *Schema {
+	schema.attributes = append(schema.attributes, attributes...)
+	return schema
+}
+
+// Attributes returns the definitions of the attributes of the schema.
+func (schema *Schema) Attributes() (defs []AttributeDef, err error) {
+
+	defs = make([]AttributeDef, 0, len(schema.attributes))
+	seen := map[string]bool{}
+	for _, attr := range schema.attributes {
+		var def AttributeDef
+		if def, err = attr.Def(); err == nil && seen[def.Ident] {
+			err = edn.MakeErrorWithFormat(ErrInvalidSchema, "%s is declared twice", def.Ident)
+		}
+
+		if err != nil {
+			break
+		}
+		seen[def.Ident] = true
+		defs = append(defs, def)
+	}
+
+	return defs, err
+}
+
+// Tx returns the transaction installing the schema.
+func (schema *Schema) Tx() (tx *Tx, err error) {
+	var defs []AttributeDef
+	if defs, err = schema.Attributes(); err == nil {
+		tx = NewTx()
+		for _, def := range defs {
+			installAttribute(tx, def)
+			installEnums(tx, def.Enums)
+		}
+	}
+	return tx, err
+}
+
+// Element builds the transaction installing the schema.
+func (schema *Schema) Element() (elem edn.Element, err error) {
+	var tx *Tx
+	if tx, err = schema.Tx(); err == nil {
+		elem, err = tx.Element()
+	}
+	return elem, err
+}
+
+// Serialize the transaction installing the schema.
+func (schema *Schema) Serialize(serializer edn.Serializer) (str string, err error) {
+	var tx *Tx
+	if tx, err = schema.Tx(); err == nil {
+		str, err = tx.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the transaction installing the schema, empty when it cannot be built.
+func (schema *Schema) String() (str string) {
+	if tx, err := schema.Tx(); err == nil {
+		str = tx.String()
+	}
+	return str
+}
+
+// SchemaReport tells what EnsureSchema did.
+type SchemaReport struct {
+
+	// Installed are the attributes and enums that were missing.
+	Installed []string
+
+	// Updated are the installed attributes whose documentation changed.
+	Updated []string
+
+	// Unchanged are the attributes already installed as declared.
+	Unchanged []string
+
+	// Report is the report of the transaction, nil when there was nothing to transact.
+	Report TransactReport
+}
+
+// EnsureSchema installs the attributes of the schema which are missing from the latest snapshot of the connection,
+// see EnsureSchemaContext.
+func EnsureSchema(conn ConnectionChannel, schema *Schema) (SchemaReport, error) {
+	return EnsureSchemaContext(context.Background(), conn, schema)
+}
+
+// EnsureSchemaContext reads the attributes of the schema installed in the latest snapshot of the connection, and
+// transacts those which are missing along with the documentation which changed, so that it can run every time the
+// program starts. An attribute installed with another type, cardinality, uniqueness or index is not changed and fails
+// with ErrIncompatibleSchema, nothing being transacted. The connection records the attributes ensured for its tenant,
+// which are not read again.
+func EnsureSchemaContext(ctx context.Context, conn ConnectionChannel, schema *Schema) (report SchemaReport, err error) {
+
+	var defs []AttributeDef
+	if conn == nil || schema == nil {
+		err = edn.MakeError(edn.ErrInvalidInput, "connection or schema are not valid")
+	} else {
+		defs, err = schema.Attributes()
+	}
+
+	recorder, records := conn.(schemaRecorder)
+	tenant := tenantNameOf(conn)
+
+	var pending []AttributeDef
+	for _, def := range defs {
+		if records && recorder.schemaEnsured(tenant, def.String()) {
+			report.Unchanged = append(report.Unchanged, def.Ident)
+		} else {
+			pending = append(pending, def)
+		}
+	}
+
+	if err == nil && len(pending) > 0 {
+		var installed map[string]AttributeDef
+		if installed, err = installedAttributes(ctx, conn, pending); err == nil {
+			tx := NewTx()
+			var incompatible []string
+			for _, def := range pending {
+				current, has := installed[def.Ident]
+				switch {
+				case !has:
+					installAttribute(tx, def)
+					report.Installed = append(report.Installed, def.Ident)
+				case !compatibleAttributes(def, current):
+					incompatible = append(incompatible, def.Ident)
+				case def.Doc != current.Doc && def.Doc != "":
+					tx.Add(def.Ident, ":db/doc", def.Doc)
+					report.Updated = append(report.Updated, def.Ident)
+				default:
+					report.Unchanged = append(report.Unchanged, def.Ident)
+				}
+
+				var missing []string
+				for _, enum := range def.Enums {
+					if _, has := installed[enum]; !has {
+						missing = append(missing, enum)
+					}
+				}
+				installEnums(tx, missing)
+				report.Installed = append(report.Installed, missing...)
+			}
+
+			if len(incompatible) > 0 {
+				err = edn.MakeErrorWithFormat(ErrIncompatibleSchema, "Installed differently: %s", strings.Join(incompatible, ", "))
+			} else if tx.Len() > 0 {
+				if report.Report, err = conn.TransactContext(ctx, tx); err == nil {
+					if resultErr, failed := report.Report.Error(); failed {
+						err = resultErr
+					}
+				}
+			}
+		}
+
+		if err == nil && records {
+			for _, def := range pending {
+				recorder.recordSchema(tenant, def.String())
+			}
+		}
+	}
+
+	if err != nil {
+		report = SchemaReport{}
+	}
+
+	return report, err
+}
+
+// schemaRecorder records the attributes ensured for the tenants, see BaseConnectionChannel.
+type schemaRecorder interface {
+	schemaEnsured(tenant string, def string) bool
+	recordSchema(tenant string, def string)
+}
+
+// tenantNameOf returns the name of the tenant of the channel, empty when there is none.
+func tenantNameOf(channel Channel) (name string) {
+	if channel != nil && channel.Source() != nil {
+		if tenant := channel.Source().Tenant(); tenant != nil {
+			name = tenant.Name()
+		}
+	}
+	return name
+}
+
+// installAttribute adds the entity map installing the attribute to the transaction.
+func installAttribute(tx *Tx, def AttributeDef) {
+	entity := map[string]interface{}{
+		EntityIdAttribute:        tx.TempID(DbPartition),
+		IdentAttribute:           Ident(def.Ident),
+		":db/valueType":          Ident(string(def.ValueType)),
+		":db/cardinality":        Ident(string(def.Cardinality)),
+		":db.install/_attribute": Ident(DbPartition),
+	}
+	if def.Doc != "" {
+		entity[":db/doc"] = def.Doc
+	}
+	if def.Unique != "" {
+		entity[":db/unique"] = Ident(string(def.Unique))
+	}
+	if def.Index {
+		entity[":db/index"] = true
+	}
+	if def.Fulltext {
+		entity[":db/fulltext"] = true
+	}
+	if def.IsComponent {
+		entity[":db/isComponent"] = true
+	}
+	if def.NoHistory {
+		entity[":db/noHistory"] = true
+	}
+	tx.Entity(entity)
+}
+
+// installEnums adds the entities of the enum idents to the transaction.
+func installEnums(tx *Tx, enums []string) {
+	for _, enum := range enums {
+		tx.Entity(map[string]interface{}{
+			EntityIdAttribute: tx.TempID(),
+			IdentAttribute:    Ident(enum),
+		})
+	}
+}
+
+// compatibleAttributes checks if the installed attribute differs from its declaration by its documentation at most.
+func compatibleAttributes(declared AttributeDef, installed AttributeDef) bool {
+	return declared.ValueType == installed.ValueType &&
+		declared.Cardinality == installed.Cardinality &&
+		declared.Unique == installed.Unique &&
+		declared.Index == installed.Index &&
+		declared.Fulltext == installed.Fulltext &&
+		declared.IsComponent == installed.IsComponent &&
+		declared.NoHistory == installed.NoHistory
+}
+
+// installedPattern pulls the definition of an attribute.
+var installedPattern = Pattern(
+	EntityIdAttribute,
+	IdentAttribute,
+	":db/doc",
+	":db/index",
+	":db/fulltext",
+	":db/isComponent",
+	":db/noHistory",
+	Attr(":db/valueType").Nest(IdentAttribute),
+	Attr(":db/cardinality").Nest(IdentAttribute),
+	Attr(":db/unique").Nest(IdentAttribute))
+
+// installedAttributes reads the definitions of the attributes, and of their enums, installed in the latest snapshot of
+// the connection. The enums are there without a type.
+func installedAttributes(ctx context.Context, conn ConnectionChannel, defs []AttributeDef) (installed map[string]AttributeDef, err error) {
+
+	var idents []edn.Element
+	for _, def := range defs {
+		idents = append(idents, keywordOrNil(def.Ident))
+		for _, enum := range def.Enums {
+			idents = append(idents, keywordOrNil(enum))
+		}
+	}
+
+	query := Find(PullOf("?a", installedPattern)).Collection().
+		In("$", BindCollection("?ident")).
+		Where(Clause("?a", IdentAttribute, "?ident"))
+
+	var snap SnapshotChannel
+	var identsElem edn.Element
+	var result QueryResult
+	var values []edn.Element
+	if snap, err = conn.LatestSnapshot(); err == nil {
+		if identsElem, err = edn.NewVector(idents...); err == nil {
+			if result, err = snap.QueryContext(ctx, query, identsElem); err == nil {
+				values, err = result.Collection()
+			}
+		}
+	}
+
+	installed = map[string]AttributeDef{}
+	for _, value := range values {
+		var def AttributeDef
+		if def, err = installedAttributeOf(value); err != nil {
+			break
+		}
+		installed[def.Ident] = def
+	}
+
+	return installed, err
+}
+
+// installedAttributeOf reads the pulled definition of an attribute.
+func installedAttributeOf(elem edn.Element) (def AttributeDef, err error) {
+
+	identOf := func(attr string) (ident string, err error) {
+		var value edn.Element
+		if value, err = attributeOf(elem, attr); err == nil && value != nil {
+			if value.ElementType() == edn.MapType {
+				value, err = attributeOf(value, IdentAttribute)
+			}
+			if err == nil && value != nil {
+				ident = value.String()
+			}
+		}
+		return ident, err
+	}
+
+	flagOf := func(attr string) (set bool, err error) {
+		var value edn.Element
+		if value, err = attributeOf(elem, attr); err == nil && value != nil {
+			set, _ = value.Value().(bool)
+		}
+		return set, err
+	}
+
+	var valueType, cardinality, unique string
+	if def.Ident, err = identOf(IdentAttribute); err == nil {
+		if valueType, err = identOf(":db/valueType"); err == nil {
+			if cardinality, err = identOf(":db/cardinality"); err == nil {
+				unique, err = identOf(":db/unique")
+			}
+		}
+	}
+
+	var doc edn.Element
+	if err == nil {
+		def.ValueType, def.Cardinality, def.Unique = ValueType(valueType), Cardinality(cardinality), Uniqueness(unique)
+		if doc, err = attributeOf(elem, ":db/doc"); err == nil && doc != nil {
+			def.Doc, _ = doc.Value().(string)
+		}
+	}
+
+	if err == nil {
+		def.Id, _ = entityIdOf(elem)
+	}
+
+	for attr, flag := range map[string]*bool{
+		":db/index":       &def.Index,
+		":db/fulltext":    &def.Fulltext,
+		":db/isComponent": &def.IsComponent,
+		":db/noHistory":   &def.NoHistory,
+	} {
+		if err == nil {
+			*flag, err = flagOf(attr)
+		}
+	}
+
+	if err == nil && def.Ident == "" {
+		err = edn.MakeErrorWithFormat(ErrInvalidSchema, "Expected an attribute, got: %s", elem.String())
+	}
+
+	return def, err
+}
@@ -0,0 +1,259 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// schemaSource answers the queries with the installed attributes and keeps the idents asked for.
+type schemaSource struct {
+	*mockSource
+	tenant    Tenant
+	installed string
+	asked     []string
+}
+
+// Tenant of the source.
+func (source *schemaSource) Tenant() Tenant {
+	return source.tenant
+}
+
+// QueryContext answers the query with the installed attributes.
+func (source *schemaSource) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+	source.asked = append(source.asked, parameters[1].(edn.Element).String())
+	return newQueryResult(query, newMockPayloadResult(source.installed)), nil
+}
+
+var _ = Describe("schema test", func() {
+
+	var source *schemaSource
+	var conn ConnectionChannel
+	var transacted []string
+	var rejected bool
+
+	BeforeEach(func() {
+		tenant, err := NewTenant("tenant")
+		Ω(err).Should(BeNil())
+
+		source = &schemaSource{
+			mockSource: &mockSource{},
+			tenant:     tenant,
+			installed: `[{:db/ident :book/title :db/doc "Title of a book"
+			              :db/valueType {:db/ident :db.type/string} :db/cardinality {:db/ident :db.cardinality/one}}
+			             {:db/ident :book.genre/history}]`,
+		}
+		transacted = nil
+		rejected = false
+
+		conn, err = NewBaseConnectionChannel(
+			edn.NewStringElement("label"),
+			source,
+			func(ctx context.Context, transaction edn.Serializable) (Result, error) {
+				str, err := transaction.Serialize(edn.EvaEdnMimeType)
+				transacted = append(transacted, str)
+				if rejected {
+					return newMockFailedResult(), err
+				}
+				return newMockPayloadResult(mockTransactPayload), err
+			},
+			func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error) {
+				return NewBaseSnapshotChannel(edn.NewStringElement("label"), source, nil, nil, asOf, options...)
+			})
+		Ω(err).Should(BeNil())
+	})
+
+	equivalent := func(serializable edn.Serializable, expected string) {
+		str, err := serializable.Serialize(edn.EvaEdnMimeType)
+		Ω(err).Should(BeNil())
+
+		actual, err := edn.Parse(str)
+		Ω(err).Should(BeNil())
+		expectedElem, err := edn.Parse(expected)
+		Ω(err).Should(BeNil())
+		Ω(actual.Equals(expectedElem)).Should(BeTrue(), str)
+	}
+
+	Context("declaring the attributes", func() {
+		It("should install the attributes", func() {
+			schema := NewSchema(
+				Attribute(":book/title").Type(String).Cardinality(One).Doc("Title of a book").Fulltext(),
+				Attribute(":book/isbn").Type(String).Unique(UniqueIdentity).Index())
+			schema.Add(Attribute(":book/tags").Type(Keyword).Cardinality(Many).NoHistory())
+
+			equivalent(schema, `[
+				{:db/id #db/id [:db.part/db -1] :db/ident :book/title :db/valueType :db.type/string
+				 :db/cardinality :db.cardinality/one :db/doc "Title of a book" :db/fulltext true
+				 :db.install/_attribute :db.part/db}
+				{:db/id #db/id [:db.part/db -2] :db/ident :book/isbn :db/valueType :db.type/string
+				 :db/cardinality :db.cardinality/one :db/unique :db.unique/identity :db/index true
+				 :db.install/_attribute :db.part/db}
+				{:db/id #db/id [:db.part/db -3] :db/ident :book/tags :db/valueType :db.type/keyword
+				 :db/cardinality :db.cardinality/many :db/noHistory true :db.install/_attribute :db.part/db}]`)
+		})
+
+		It("should install the enums", func() {
+			schema := NewSchema(Attribute(":book/genre").Type(Ref).IsComponent().Enum(":book.genre/fiction", ":book.genre/history"))
+
+			equivalent(schema, `[
+				{:db/id #db/id [:db.part/db -1] :db/ident :book/genre :db/valueType :db.type/ref
+				 :db/cardinality :db.cardinality/one :db/isComponent true :db.install/_attribute :db.part/db}
+				{:db/id #db/id [:db.part/user -1] :db/ident :book.genre/fiction}
+				{:db/id #db/id [:db.part/user -2] :db/ident :book.genre/history}]`)
+		})
+
+		It("should return the definitions", func() {
+			defs, err := NewSchema(Attribute(":book/title").Type(String).Doc("Title of a book")).Attributes()
+			Ω(err).Should(BeNil())
+			Ω(defs).Should(Equal([]AttributeDef{{
+				Ident:       ":book/title",
+				ValueType:   String,
+				Cardinality: One,
+				Doc:         "Title of a book",
+			}}))
+			Ω(defs[0].String()).Should(BeEquivalentTo(`{:db/ident :book/title :db/valueType :db.type/string :db/cardinality :db.cardinality/one :db/doc "Title of a book"}`))
+		})
+
+		It("should reject the invalid attributes", func() {
+			for _, attr := range []*AttributeSchema{
+				Attribute("book/title").Type(String),
+				Attribute(":book/title"),
+				Attribute(":book/title").Type(ValueType(":db.type/text")),
+				Attribute(":book/title").Type(String).Cardinality(Cardinality(":db.cardinality/few")),
+				Attribute(":book/title").Type(String).Unique(Uniqueness(":db.unique/maybe")),
+				Attribute(":book/year").Type(Long).Fulltext(),
+				Attribute(":book/title").Type(String).IsComponent(),
+				Attribute(":book/title").Type(String).Enum(":book.title/first"),
+				Attribute(":book/genre").Type(Ref).Enum("fiction"),
+			} {
+				_, err := attr.Def()
+				Ω(err).Should(test.HaveMessage(ErrInvalidSchema), attr.def.String())
+				Ω(NewSchema(attr).String()).Should(BeEmpty())
+			}
+
+			_, err := NewSchema(Attribute(":book/title").Type(String), Attribute(":book/title").Type(String)).Tx()
+			Ω(err).Should(test.HaveMessage(ErrInvalidSchema))
+		})
+	})
+
+	Context("ensuring the schema", func() {
+		It("should install only what is missing", func() {
+			report, err := EnsureSchema(conn, NewSchema(
+				Attribute(":book/title").Type(String).Doc("Title of a book"),
+				Attribute(":book/genre").Type(Ref).Enum(":book.genre/fiction", ":book.genre/history")))
+			Ω(err).Should(BeNil())
+			Ω(report.Installed).Should(Equal([]string{":book/genre", ":book.genre/fiction"}))
+			Ω(report.Updated).Should(BeEmpty())
+			Ω(report.Unchanged).Should(Equal([]string{":book/title"}))
+			Ω(report.Report).ShouldNot(BeNil())
+
+			Ω(source.asked).Should(Equal([]string{"[:book/title :book/genre :book.genre/fiction :book.genre/history]"}))
+			Ω(transacted).Should(HaveLen(1))
+			equivalent(RawString(transacted[0]), `[
+				{:db/id #db/id [:db.part/db -1] :db/ident :book/genre :db/valueType :db.type/ref
+				 :db/cardinality :db.cardinality/one :db.install/_attribute :db.part/db}
+				{:db/id #db/id [:db.part/user -1] :db/ident :book.genre/fiction}]`)
+		})
+
+		It("should update the documentation", func() {
+			report, err := EnsureSchema(conn, NewSchema(Attribute(":book/title").Type(String).Doc("The title")))
+			Ω(err).Should(BeNil())
+			Ω(report.Installed).Should(BeEmpty())
+			Ω(report.Updated).Should(Equal([]string{":book/title"}))
+			Ω(transacted).Should(Equal([]string{`[[:db/add :book/title :db/doc "The title"]]`}))
+		})
+
+		It("should not transact when the schema is installed", func() {
+			report, err := EnsureSchema(conn, NewSchema(Attribute(":book/title").Type(String)))
+			Ω(err).Should(BeNil())
+			Ω(report.Unchanged).Should(Equal([]string{":book/title"}))
+			Ω(report.Report).Should(BeNil())
+			Ω(transacted).Should(BeEmpty())
+		})
+
+		It("should reject the incompatible attributes", func() {
+			report, err := EnsureSchema(conn, NewSchema(
+				Attribute(":book/title").Type(String).Cardinality(Many),
+				Attribute(":book/genre").Type(Ref)))
+			Ω(err).Should(test.HaveMessage(ErrIncompatibleSchema))
+			Ω(err.Error()).Should(ContainSubstring(":book/title"))
+			Ω(report).Should(Equal(SchemaReport{}))
+			Ω(transacted).Should(BeEmpty())
+		})
+
+		It("should remember the schema ensured for the tenant", func() {
+			schema := NewSchema(Attribute(":book/genre").Type(Ref))
+			_, err := EnsureSchema(conn, schema)
+			Ω(err).Should(BeNil())
+
+			report, err := EnsureSchema(conn, schema)
+			Ω(err).Should(BeNil())
+			Ω(report.Unchanged).Should(Equal([]string{":book/genre"}))
+			Ω(source.asked).Should(HaveLen(1))
+			Ω(transacted).Should(HaveLen(1))
+
+			_, err = EnsureSchema(conn, NewSchema(Attribute(":book/genre").Type(Ref).Doc("Genre of a book")))
+			Ω(err).Should(BeNil())
+			Ω(source.asked).Should(HaveLen(2))
+
+			source.tenant, err = NewTenant("other")
+			Ω(err).Should(BeNil())
+			_, err = EnsureSchema(conn, schema)
+			Ω(err).Should(BeNil())
+			Ω(source.asked).Should(HaveLen(3))
+		})
+
+		It("should not record a failed installation", func() {
+			source.installed = `[{:db/doc "No ident"}]`
+			schema := NewSchema(Attribute(":book/genre").Type(Ref))
+
+			_, err := EnsureSchema(conn, schema)
+			Ω(err).Should(test.HaveMessage(ErrInvalidSchema))
+			_, err = EnsureSchema(conn, schema)
+			Ω(err).Should(test.HaveMessage(ErrInvalidSchema))
+			Ω(source.asked).Should(HaveLen(2))
+			Ω(transacted).Should(BeEmpty())
+		})
+
+		It("should not record the transactions the service rejects", func() {
+			rejected = true
+			schema := NewSchema(Attribute(":book/genre").Type(Ref))
+
+			report, err := EnsureSchema(conn, schema)
+			Ω(err).Should(test.HaveMessage(ErrSourceError))
+			Ω(report).Should(Equal(SchemaReport{}))
+
+			rejected = false
+			report, err = EnsureSchema(conn, schema)
+			Ω(err).Should(BeNil())
+			Ω(report.Installed).Should(Equal([]string{":book/genre"}))
+			Ω(transacted).Should(HaveLen(2))
+		})
+
+		It("should reject the invalid schemas", func() {
+			_, err := EnsureSchema(conn, nil)
+			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+
+			_, err = EnsureSchema(conn, NewSchema(Attribute(":book/title")))
+			Ω(err).Should(test.HaveMessage(ErrInvalidSchema))
+			Ω(source.asked).Should(BeEmpty())
+		})
+	})
+})
@@ -15,12 +15,25 @@
 package eva
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
 	"github.com/Workiva/eva-client-go/edn"
 )
 
 const (
 	// SnapshotReferenceType defines a new snapshot reference.
 	SnapshotReferenceType ChannelType = "eva.client.service/snapshot-ref"
+
+	// ErrUnsupportedOperation defines the error for an operation the binding does not implement.
+	ErrUnsupportedOperation = edn.ErrorMessage("Unsupported operation")
+
+	// ErrUnknownBasis defines the error for a snapshot whose basis t is not known to the client.
+	ErrUnknownBasis = edn.ErrorMessage("Unknown basis")
 )
 
 // SnapshotChannel defines the channel to a particular eva snapshot
@@ -30,23 +43,85 @@ type SnapshotChannel interface {
 	// Pull from the snapshot.
 	Pull(pattern interface{}, ids interface{}, parameters ...interface{}) (Result, error)
 
+	// PullContext pulls from the snapshot, giving up when the context is done.
+	PullContext(ctx context.Context, pattern interface{}, ids interface{}, parameters ...interface{}) (Result, error)
+
+	// PullMany pulls the entities of the ids, the result is the vector of the entities in the order of the ids.
+	PullMany(pattern interface{}, ids []interface{}) (Result, error)
+
+	// PullManyContext pulls the entities of the ids, giving up when the context is done.
+	PullManyContext(ctx context.Context, pattern interface{}, ids []interface{}) (Result, error)
+
+	// PullInto pulls the entity of the id into the struct v points to, with the pattern of its tagged fields. When v
+	// points to a slice, ids is a slice of ids and their entities are pulled into the slice.
+	PullInto(ids interface{}, v interface{}) error
+
+	// PullIntoContext pulls into the struct v points to, giving up when the context is done.
+	PullIntoContext(ctx context.Context, ids interface{}, v interface{}) error
+
 	// Invoke from the snapshot
 	Invoke(function interface{}, parameters ...interface{}) (Result, error)
 
+	// InvokeContext invokes from the snapshot, giving up when the context is done.
+	InvokeContext(ctx context.Context, function interface{}, parameters ...interface{}) (Result, error)
+
+	// Query the snapshot, `$` is bound to this snapshot and the named data sources to the DataSources parameters.
+	Query(query interface{}, parameters ...interface{}) (QueryResult, error)
+
+	// QueryContext queries the snapshot, giving up when the context is done.
+	QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (QueryResult, error)
+
 	// AsOf the time specified.
 	AsOf() *int
+
+	// AsOfTime returns the wall clock time the snapshot is as of, if it is as of a time.
+	AsOfTime() *time.Time
+
+	// BasisT returns the basis t of the database value the snapshot sees, the t it is as of or else the basis t of the
+	// last transaction the process committed to the database. It fails with ErrUnknownBasis when neither is known.
+	BasisT() (int64, error)
+
+	// SinceT returns the basis of a since snapshot, if it is a t or the entity id of a transaction.
+	SinceT() *int
+
+	// SinceTime returns the basis of a since snapshot, if it is a time.
+	SinceTime() *time.Time
+
+	// IsHistory checks if the snapshot sees the history of the database.
+	IsHistory() bool
+
+	// History returns the snapshot of the same database value that sees all the facts ever asserted or retracted.
+	History() (SnapshotChannel, error)
+
+	// Since returns the snapshot of the same database value restricted to the facts added after the basis, which can
+	// be a t, the entity id of a transaction or a time.Time.
+	Since(basis interface{}) (SnapshotChannel, error)
+
+	// Entity returns the entity of the id, ident or lookup ref in the snapshot, its attributes are pulled lazily.
+	Entity(id interface{}) (Entity, error)
+
+	// Schema returns the attributes installed in the snapshot, read once per basis t.
+	Schema() (*InstalledSchema, error)
+
+	// SchemaContext returns the attributes installed in the snapshot, giving up when the context is done.
+	SchemaContext(ctx context.Context) (*InstalledSchema, error)
 }
 
-type PullImplementation func(pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (result Result, err error)
-type InvokeImplementation func(function edn.Serializable, parameters ...interface{}) (result Result, err error)
+type PullImplementation func(ctx context.Context, pattern edn.Serializable, ids edn.Serializable, params ...interface{}) (result Result, err error)
+type InvokeImplementation func(ctx context.Context, function edn.Serializable, parameters ...interface{}) (result Result, err error)
 
 type BaseSnapshotChannel struct {
 	*BaseChannel
 	pullImpl   PullImplementation
 	invokeImpl InvokeImplementation
+	entities   *entityCache
+	schemaLock sync.Mutex
+	schema     *InstalledSchema
 }
 
-func NewBaseSnapshotChannel(label edn.Serializable, source Source, pullImpl PullImplementation, invokeImpl InvokeImplementation, asOf interface{}) (channel *BaseSnapshotChannel, err error) {
+// NewBaseSnapshotChannel creates the snapshot channel as of a t, the entity id of a transaction or a time.Time, the
+// options set the view of the snapshot.
+func NewBaseSnapshotChannel(label edn.Serializable, source Source, pullImpl PullImplementation, invokeImpl InvokeImplementation, asOf interface{}, options ...SnapshotOption) (channel *BaseSnapshotChannel, err error) {
 
 	var asOfSer edn.Serializable
 	if asOfSer, err = decodeSerializable(asOf); err == nil {
@@ -57,10 +132,13 @@ func NewBaseSnapshotChannel(label edn.Serializable, source Source, pullImpl Pull
 				LabelReferenceProperty: label,
 				AsOfReferenceProperty:  asOfSer,
 			}); err == nil {
-			channel = &BaseSnapshotChannel{
-				BaseChannel: base,
-				pullImpl:    pullImpl,
-				invokeImpl:  invokeImpl,
+			if err = applySnapshotOptions(base.Reference(), options...); err == nil {
+				channel = &BaseSnapshotChannel{
+					BaseChannel: base,
+					pullImpl:    pullImpl,
+					invokeImpl:  invokeImpl,
+					entities:    newEntityCache(),
+				}
 			}
 		}
 	}
@@ -74,26 +152,237 @@ func (channel *BaseSnapshotChannel) Label() string {
 }
 
 // AsOf the time specified.
-func (channel *BaseSnapshotChannel) AsOf() (asOf *int) {
+func (channel *BaseSnapshotChannel) AsOf() *int {
+	return basisT(channel.Reference().GetProperty(AsOfReferenceProperty))
+}
 
-	if asOfSer := channel.Reference().GetProperty(AsOfReferenceProperty); asOfSer != nil {
-		switch val := asOfSer.(type) {
-		case edn.Element:
-			if val.ElementType() == edn.IntegerType {
-				v := int(val.Value().(int64))
-				asOf = &v
+// AsOfTime returns the wall clock time the snapshot is as of, if it is as of a time.
+func (channel *BaseSnapshotChannel) AsOfTime() *time.Time {
+	return basisTime(channel.Reference().GetProperty(AsOfReferenceProperty))
+}
+
+// BasisT returns the basis t of the database value the snapshot sees. A snapshot as of a t is at that t, as it was
+// given. A snapshot of the latest database is at the basis t of the last transaction committed to the database through
+// a connection of the process, the transactions of the other writers are not seen. A snapshot as of a time has no basis
+// t the client knows of, and neither has a snapshot of the latest database nothing was committed to yet.
+func (channel *BaseSnapshotChannel) BasisT() (t int64, err error) {
+
+	ref := channel.Reference()
+	if asOf := channel.AsOf(); asOf != nil {
+		t = int64(*asOf)
+	} else if asOfSer := ref.GetProperty(AsOfReferenceProperty); asOfSer != nil {
+		err = edn.MakeErrorWithFormat(ErrUnknownBasis, "Snapshot as of: %v", asOfSer)
+	} else {
+		var observed bool
+		if t, observed = bases.get(databaseKey(channel)); !observed {
+			err = edn.MakeErrorWithFormat(ErrUnknownBasis, "No transaction observed on: %s", channel.Label())
+		}
+	}
+
+	return t, err
+}
+
+// SinceT returns the basis of a since snapshot, if it is a t or the entity id of a transaction.
+func (channel *BaseSnapshotChannel) SinceT() *int {
+	return basisT(channel.Reference().GetProperty(SinceReferenceProperty))
+}
+
+// SinceTime returns the basis of a since snapshot, if it is a time.
+func (channel *BaseSnapshotChannel) SinceTime() *time.Time {
+	return basisTime(channel.Reference().GetProperty(SinceReferenceProperty))
+}
+
+// IsHistory checks if the snapshot sees the history of the database.
+func (channel *BaseSnapshotChannel) IsHistory() (is bool) {
+	if elem, isElem := channel.Reference().GetProperty(HistoryReferenceProperty).(edn.Element); isElem {
+		is = elem.ElementType() == edn.BooleanType && elem.Value().(bool)
+	}
+	return is
+}
+
+// History returns the snapshot of the same database value that sees all the facts ever asserted or retracted.
+func (channel *BaseSnapshotChannel) History() (SnapshotChannel, error) {
+	return channel.view(WithHistory())
+}
+
+// Since returns the snapshot of the same database value restricted to the facts added after the basis.
+func (channel *BaseSnapshotChannel) Since(basis interface{}) (SnapshotChannel, error) {
+	return channel.view(WithSince(basis))
+}
+
+// Entity returns the entity of the id, ident or lookup ref in the snapshot, its attributes are pulled lazily and
+// cached by the snapshot.
+func (channel *BaseSnapshotChannel) Entity(id interface{}) (Entity, error) {
+	return newEntity(channel, channel.entities, id)
+}
+
+// Schema returns the attributes installed in the snapshot.
+func (channel *BaseSnapshotChannel) Schema() (*InstalledSchema, error) {
+	return channel.SchemaContext(context.Background())
+}
+
+// SchemaContext returns the attributes installed in the snapshot, giving up when the context is done. The schema is
+// cached by the snapshot, so that a snapshot of the latest database keeps the schema it read first, and by the process
+// for the basis t of the snapshot when it is known, see BasisT. The attributes of history and since snapshots are those
+// of the database they view.
+func (channel *BaseSnapshotChannel) SchemaContext(ctx context.Context) (schema *InstalledSchema, err error) {
+
+	channel.schemaLock.Lock()
+	schema = channel.schema
+	channel.schemaLock.Unlock()
+
+	views := channel.IsHistory() || channel.Reference().GetProperty(SinceReferenceProperty) != nil
+	if schema == nil {
+		var t int64
+		var key string
+		if t, err = channel.BasisT(); err == nil {
+			key = schemaKey(channel, t)
+			schema = schemas.get(key)
+		} else if errors.Is(err, ErrUnknownBasis) {
+			err = nil
+		}
+
+		if err == nil && schema == nil {
+			var snap SnapshotChannel = channel
+			if key != "" && (views || channel.AsOf() == nil) {
+				snap, err = channel.databaseAsOf(t)
+			} else if views {
+				var asOf interface{}
+				if asOfSer := channel.Reference().GetProperty(AsOfReferenceProperty); asOfSer != nil {
+					asOf = asOfSer
+				}
+				snap, err = channel.databaseAsOf(asOf)
+			}
+
+			if err == nil {
+				if schema, err = loadSchema(ctx, snap); err == nil && key != "" {
+					schemas.put(key, schema)
+				}
 			}
-		case rawIntImpl:
-			v := int(val.Int())
-			asOf = &v
 		}
 	}
 
-	return asOf
+	if err == nil {
+		channel.schemaLock.Lock()
+		channel.schema = schema
+		channel.schemaLock.Unlock()
+	}
+
+	return schema, err
+}
+
+// view returns the snapshot of the same database value, with the options applied on top of the view of this one. The
+// snapshot is made by the connection so that it is one of the same binding.
+func (channel *BaseSnapshotChannel) view(options ...SnapshotOption) (SnapshotChannel, error) {
+	var asOf interface{}
+	if asOfSer := channel.Reference().GetProperty(AsOfReferenceProperty); asOfSer != nil {
+		asOf = asOfSer
+	}
+	return channel.viewAsOf(asOf, options...)
+}
+
+// viewAsOf returns the snapshot with the view of this one as of another basis, with the options applied on top.
+func (channel *BaseSnapshotChannel) viewAsOf(asOf interface{}, options ...SnapshotOption) (snap SnapshotChannel, err error) {
+
+	ref := channel.Reference()
+
+	var current []SnapshotOption
+	if since := ref.GetProperty(SinceReferenceProperty); since != nil {
+		current = append(current, WithSince(since))
+	}
+
+	if channel.IsHistory() {
+		current = append(current, WithHistory())
+	}
+
+	var conn ConnectionChannel
+	if conn, err = channel.Source().Connection(ref.GetProperty(LabelReferenceProperty)); err == nil {
+		snap, err = conn.AsOfSnapshot(asOf, append(current, options...)...)
+	}
+
+	return snap, err
+}
+
+// databaseAsOf returns the snapshot of the database as of the basis.
+func (channel *BaseSnapshotChannel) databaseAsOf(asOf interface{}) (snap SnapshotChannel, err error) {
+
+	var conn ConnectionChannel
+	if conn, err = channel.Source().Connection(channel.Reference().GetProperty(LabelReferenceProperty)); err == nil {
+		snap, err = conn.AsOfSnapshot(asOf)
+	}
+
+	return snap, err
+}
+
+// basisT reads a basis property which is a t or the entity id of a transaction.
+func basisT(ser edn.Serializable) (t *int) {
+	switch val := ser.(type) {
+	case edn.Element:
+		if val.ElementType() == edn.IntegerType {
+			v := int(val.Value().(int64))
+			t = &v
+		}
+	case rawIntImpl:
+		v := int(val.Int())
+		t = &v
+	}
+
+	return t
+}
+
+// basisTracker keeps the basis t of the last transaction observed by the process, by database.
+type basisTracker struct {
+	lock  sync.Mutex
+	bases map[string]int64
+}
+
+// bases are the basis t observed in the reports of the transactions committed by the process.
+var bases = newBasisTracker()
+
+// newBasisTracker creates the tracker, no basis t is observed yet.
+func newBasisTracker() *basisTracker {
+	return &basisTracker{bases: make(map[string]int64)}
+}
+
+// get returns the basis t observed for the key, if any.
+func (tracker *basisTracker) get(key string) (t int64, has bool) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	t, has = tracker.bases[key]
+	return t, has
+}
+
+// observe records the basis t for the key, unless a later one was observed already.
+func (tracker *basisTracker) observe(key string, t int64) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	if current, has := tracker.bases[key]; !has || t > current {
+		tracker.bases[key] = t
+	}
+}
+
+// databaseKey returns the key of the database of the channel, by tenant and label.
+func databaseKey(channel Channel) string {
+	return fmt.Sprintf("%s %s", tenantNameOf(channel), channel.Label())
+}
+
+// basisTime reads a basis property which is a time.
+func basisTime(ser edn.Serializable) (t *time.Time) {
+	if elem, is := ser.(edn.Element); is && elem.ElementType() == edn.InstantType {
+		v := elem.Value().(time.Time)
+		t = &v
+	}
+
+	return t
 }
 
 // Pull from the snapshot.
-func (channel *BaseSnapshotChannel) Pull(pattern interface{}, ids interface{}, parameters ...interface{}) (result Result, err error) {
+func (channel *BaseSnapshotChannel) Pull(pattern interface{}, ids interface{}, parameters ...interface{}) (Result, error) {
+	return channel.PullContext(context.Background(), pattern, ids, parameters...)
+}
+
+// PullContext pulls from the snapshot, giving up when the context is done.
+func (channel *BaseSnapshotChannel) PullContext(ctx context.Context, pattern interface{}, ids interface{}, parameters ...interface{}) (result Result, err error) {
 
 	var ptrn edn.Serializable
 	var idSer edn.Serializable
@@ -103,20 +392,138 @@ func (channel *BaseSnapshotChannel) Pull(pattern interface{}, ids interface{}, p
 	}
 
 	if err == nil {
-		result, err = channel.pullImpl(ptrn, idSer, parameters...)
+		result, err = channel.pullImpl(ctx, ptrn, idSer, parameters...)
 	}
 
 	return result, err
 }
 
+// PullMany pulls the entities of the ids, the result is the vector of the entities in the order of the ids.
+func (channel *BaseSnapshotChannel) PullMany(pattern interface{}, ids []interface{}) (Result, error) {
+	return channel.PullManyContext(context.Background(), pattern, ids)
+}
+
+// PullManyContext pulls the entities of the ids, giving up when the context is done. The ids are entity ids, idents
+// and lookup refs, given as elements or as slices. Each id is pulled on its own so that a lookup ref is never read as
+// two ids, the first pull that fails being the result.
+func (channel *BaseSnapshotChannel) PullManyContext(ctx context.Context, pattern interface{}, ids []interface{}) (result Result, err error) {
+
+	var ptrn edn.Serializable
+	if ptrn, err = decodeSerializable(pattern); err == nil {
+		entities := make([]edn.Element, 0, len(ids))
+		for _, id := range ids {
+			var idElem edn.Element
+			var pulled Result
+			if idElem, err = idElementOf(id); err == nil {
+				if pulled, err = channel.PullContext(ctx, ptrn, idElem); err == nil {
+					if _, failed := pulled.Error(); failed {
+						result = pulled
+					} else {
+						var entity edn.Element
+						if entity, err = pulled.Element(); err == nil {
+							entities = append(entities, entity)
+						}
+					}
+				}
+			}
+
+			if err != nil || result != nil {
+				break
+			}
+		}
+
+		if err == nil && result == nil {
+			var vector edn.Element
+			if vector, err = edn.NewVector(entities...); err == nil {
+				result, err = newElementResult(vector)
+			}
+		}
+	}
+
+	return result, err
+}
+
+// PullInto pulls the entity of the id into the struct v points to, with the pattern of its tagged fields.
+func (channel *BaseSnapshotChannel) PullInto(ids interface{}, v interface{}) error {
+	return channel.PullIntoContext(context.Background(), ids, v)
+}
+
+// PullIntoContext pulls into the struct v points to, giving up when the context is done, see PatternOf.
+func (channel *BaseSnapshotChannel) PullIntoContext(ctx context.Context, ids interface{}, v interface{}) (err error) {
+
+	var pattern *PullPattern
+	if pattern, err = PatternOf(v); err == nil {
+		var result Result
+		target := reflect.TypeOf(v)
+		if many, is := ids.([]interface{}); is && target.Kind() == reflect.Ptr && target.Elem().Kind() == reflect.Slice {
+			result, err = channel.PullManyContext(ctx, pattern, many)
+		} else {
+			result, err = channel.PullContext(ctx, pattern, ids)
+		}
+
+		if err == nil {
+			if resultErr, failed := result.Error(); failed {
+				err = resultErr
+			} else {
+				err = result.Decode(v)
+			}
+		}
+	}
+
+	return err
+}
+
+// idElementOf reads the ids, slices becoming vectors so that lookup refs can be given as `[]interface{}{attr, value}`.
+func idElementOf(id interface{}) (elem edn.Element, err error) {
+	if ids, is := id.([]interface{}); is {
+		elems := make([]edn.Element, 0, len(ids))
+		for _, child := range ids {
+			var childElem edn.Element
+			if childElem, err = idElementOf(child); err != nil {
+				break
+			}
+			elems = append(elems, childElem)
+		}
+
+		if err == nil {
+			elem, err = edn.NewVector(elems...)
+		}
+	} else if elem, err = edn.NewPrimitiveElement(id); err != nil {
+		err = edn.MakeErrorWithFormat(edn.ErrInvalidInput, "Unsupported id: %#v", id)
+	}
+
+	return elem, err
+}
+
 // Invoke from the snapshot
-func (channel *BaseSnapshotChannel) Invoke(function interface{}, parameters ...interface{}) (result Result, err error) {
+func (channel *BaseSnapshotChannel) Invoke(function interface{}, parameters ...interface{}) (Result, error) {
+	return channel.InvokeContext(context.Background(), function, parameters...)
+}
+
+// InvokeContext invokes from the snapshot, giving up when the context is done.
+func (channel *BaseSnapshotChannel) InvokeContext(ctx context.Context, function interface{}, parameters ...interface{}) (result Result, err error) {
 
 	var funcElem edn.Serializable
 	funcElem, err = decodeSerializable(function)
 
 	if err == nil {
-		result, err = channel.invokeImpl(funcElem, parameters...)
+		result, err = channel.invokeImpl(ctx, funcElem, parameters...)
+	}
+
+	return result, err
+}
+
+// Query the snapshot, `$` is bound to this snapshot and the named data sources to the DataSources parameters.
+func (channel *BaseSnapshotChannel) Query(query interface{}, parameters ...interface{}) (QueryResult, error) {
+	return channel.QueryContext(context.Background(), query, parameters...)
+}
+
+// QueryContext queries the snapshot, giving up when the context is done.
+func (channel *BaseSnapshotChannel) QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (result QueryResult, err error) {
+
+	var args []interface{}
+	if args, err = bindInputs(query, channel, parameters); err == nil {
+		result, err = channel.Source().QueryContext(ctx, query, args...)
 	}
 
 	return result, err
@@ -0,0 +1,85 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrInvalidSnapshotOption defines the error for an option that cannot be applied to a snapshot.
+	ErrInvalidSnapshotOption = edn.ErrorMessage("Invalid snapshot option")
+)
+
+// SnapshotOption sets the view a snapshot has of the database on the reference of the snapshot.
+type SnapshotOption func(ref Reference) error
+
+// WithSince restricts the snapshot to the facts added after the basis, which can be a t, the entity id of a
+// transaction or a time.Time.
+func WithSince(basis interface{}) SnapshotOption {
+	return func(ref Reference) (err error) {
+		if basis != nil {
+			var ser edn.Serializable
+			if ser, err = decodeSerializable(basis); err == nil {
+				err = ref.AddProperty(SinceReferenceProperty, ser)
+			}
+		} else {
+			err = edn.MakeError(ErrInvalidSnapshotOption, "No since basis")
+		}
+		return err
+	}
+}
+
+// WithHistory makes the snapshot see all the facts ever asserted or retracted instead of the current ones.
+func WithHistory() SnapshotOption {
+	return func(ref Reference) error {
+		return ref.AddProperty(HistoryReferenceProperty, edn.NewBooleanElement(true))
+	}
+}
+
+// transactionElement reads the transaction as an element, so that it can be held by a reference.
+func transactionElement(transaction edn.Serializable) (elem edn.Element, err error) {
+	switch trx := transaction.(type) {
+	case edn.Element:
+		elem = trx
+	case nil:
+		err = edn.MakeError(edn.ErrInvalidInput, "No transaction")
+	default:
+		var str string
+		if str, err = trx.Serialize(edn.EvaEdnMimeType); err == nil {
+			elem, err = edn.Parse(str)
+		}
+	}
+
+	return elem, err
+}
+
+// applySnapshotOptions applies the options to the reference.
+func applySnapshotOptions(ref Reference, options ...SnapshotOption) (err error) {
+	for _, option := range options {
+		if option == nil {
+			err = edn.MakeError(ErrInvalidSnapshotOption, "nil option")
+		} else {
+			err = option(ref)
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	return err
+}
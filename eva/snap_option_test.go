@@ -0,0 +1,161 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("snapshot option test", func() {
+
+	at := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	Context("making references", func() {
+		It("should make the reference as of a time", func() {
+			ref, err := NewSnapshotAsOfReference("label", at)
+			Ω(err).Should(BeNil())
+			Ω(ref.String()).Should(ContainSubstring(`:as-of #inst "2019-01-02T03:04:05Z"`))
+		})
+
+		It("should make the references of the views", func() {
+			ref, err := NewSnapshotAsOfReference("label", 12, WithSince(10), WithHistory())
+			Ω(err).Should(BeNil())
+
+			str := ref.String()
+			Ω(str).Should(ContainSubstring(":as-of 12"))
+			Ω(str).Should(ContainSubstring(":since 10"))
+			Ω(str).Should(ContainSubstring(":history true"))
+
+			ref, err = NewSnapshotAsOfReference("label", nil, WithSince(at))
+			Ω(err).Should(BeNil())
+			Ω(ref.String()).Should(ContainSubstring(`:since #inst "2019-01-02T03:04:05Z"`))
+			Ω(ref.String()).ShouldNot(ContainSubstring(":as-of"))
+		})
+
+		It("should reject the bad options", func() {
+			ref, err := NewSnapshotAsOfReference("label", nil, WithSince(nil))
+			Ω(err).Should(test.HaveMessage(ErrInvalidSnapshotOption))
+			Ω(ref).Should(BeNil())
+
+			_, err = NewSnapshotAsOfReference("label", nil, nil)
+			Ω(err).Should(test.HaveMessage(ErrInvalidSnapshotOption))
+
+			_, err = NewSnapshotAsOfReference("label", nil, WithSince(1.5))
+			Ω(err).Should(test.HaveMessage(edn.ErrInvalidInput))
+		})
+	})
+
+	Context("making snapshots", func() {
+
+		var conn ConnectionChannel
+
+		BeforeEach(func() {
+			config, err := NewConfiguration("{\"category\": \"foo\"}")
+			Ω(err).Should(BeNil())
+
+			tenant, err := NewTenant("foo")
+			Ω(err).Should(BeNil())
+
+			source, err := NewBaseSource(config, tenant, &mockSource{}, makeMockConnChannel, mockQuery)
+			Ω(err).Should(BeNil())
+
+			conn, err = source.Connection("label")
+			Ω(err).Should(BeNil())
+		})
+
+		It("should be as of a time", func() {
+			snap, err := conn.AsOfSnapshot(at)
+			Ω(err).Should(BeNil())
+			Ω(snap.AsOf()).Should(BeNil())
+			Ω(snap.AsOfTime()).ShouldNot(BeNil())
+			Ω(snap.AsOfTime().Equal(at)).Should(BeTrue())
+			Ω(snap.IsHistory()).Should(BeFalse())
+			Ω(snap.SinceT()).Should(BeNil())
+			Ω(snap.SinceTime()).Should(BeNil())
+		})
+
+		It("should see the history", func() {
+			snap, err := conn.HistorySnapshot()
+			Ω(err).Should(BeNil())
+			Ω(snap.IsHistory()).Should(BeTrue())
+			Ω(snap.AsOf()).Should(BeNil())
+		})
+
+		It("should see the facts since a basis", func() {
+			snap, err := conn.SinceSnapshot(4398046511106)
+			Ω(err).Should(BeNil())
+			Ω(snap.SinceT()).ShouldNot(BeNil())
+			Ω(*snap.SinceT()).Should(BeEquivalentTo(4398046511106))
+			Ω(snap.IsHistory()).Should(BeFalse())
+
+			snap, err = conn.SinceSnapshot(at)
+			Ω(err).Should(BeNil())
+			Ω(snap.SinceTime()).ShouldNot(BeNil())
+			Ω(snap.SinceTime().Equal(at)).Should(BeTrue())
+		})
+
+		It("should keep the view of the snapshot it comes from", func() {
+			snap, err := conn.AsOfSnapshot(5)
+			Ω(err).Should(BeNil())
+
+			since, err := snap.Since(2)
+			Ω(err).Should(BeNil())
+			Ω(*since.AsOf()).Should(BeEquivalentTo(5))
+			Ω(*since.SinceT()).Should(BeEquivalentTo(2))
+			Ω(since.IsHistory()).Should(BeFalse())
+
+			history, err := since.History()
+			Ω(err).Should(BeNil())
+			Ω(*history.AsOf()).Should(BeEquivalentTo(5))
+			Ω(*history.SinceT()).Should(BeEquivalentTo(2))
+			Ω(history.IsHistory()).Should(BeTrue())
+			Ω(history.Label()).Should(BeEquivalentTo("label"))
+
+			Ω(snap.IsHistory()).Should(BeFalse())
+			Ω(snap.SinceT()).Should(BeNil())
+		})
+
+		It("should read the basis t from the reference or the transactions committed", func() {
+			bases = newBasisTracker()
+
+			snap, err := conn.AsOfSnapshot(5, WithHistory())
+			Ω(err).Should(BeNil())
+			Ω(snap.BasisT()).Should(BeEquivalentTo(5))
+
+			snap, err = conn.AsOfSnapshot(at)
+			Ω(err).Should(BeNil())
+			_, err = snap.BasisT()
+			Ω(err).Should(test.HaveMessage(ErrUnknownBasis))
+
+			latest, err := conn.LatestSnapshot()
+			Ω(err).Should(BeNil())
+			_, err = latest.BasisT()
+			Ω(err).Should(test.HaveMessage(ErrUnknownBasis))
+
+			_, err = conn.Transact("[]")
+			Ω(err).Should(BeNil())
+			Ω(latest.BasisT()).Should(BeEquivalentTo(2))
+
+			history, err := conn.HistorySnapshot()
+			Ω(err).Should(BeNil())
+			Ω(history.BasisT()).Should(BeEquivalentTo(2))
+		})
+	})
+})
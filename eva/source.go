@@ -15,6 +15,8 @@
 package eva
 
 import (
+	"context"
+
 	"github.com/Workiva/eva-client-go/edn"
 )
 
@@ -42,11 +44,15 @@ type Source interface {
 	// LatestSnapshot returns the latest snapshot channel for the specified category of data.
 	LatestSnapshot(label interface{}) (SnapshotChannel, error)
 
-	// AsOfSnapshot returns the snapshot channel of the specified category as of the rules provided.
-	AsOfSnapshot(label interface{}, asOf interface{}) (SnapshotChannel, error)
+	// AsOfSnapshot returns the snapshot channel of the specified category as of a t, the entity id of a transaction or
+	// a time.Time, the options set the view of the snapshot.
+	AsOfSnapshot(label interface{}, asOf interface{}, options ...SnapshotOption) (SnapshotChannel, error)
 
 	// Query the source for data.
-	Query(query interface{}, parameters ...interface{}) (Result, error)
+	Query(query interface{}, parameters ...interface{}) (QueryResult, error)
+
+	// QueryContext queries the source for data, giving up when the context is done.
+	QueryContext(ctx context.Context, query interface{}, parameters ...interface{}) (QueryResult, error)
 }
 
 // sourceFactory defines the mechanism for creating a source.
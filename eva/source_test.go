@@ -15,6 +15,8 @@
 package eva
 
 import (
+	"context"
+
 	"github.com/Workiva/eva-client-go/edn"
 	"github.com/Workiva/eva-client-go/test"
 	. "github.com/onsi/ginkgo"
@@ -162,7 +164,7 @@ var _ = Describe("Source test", func() {
 		clean()
 	})
 
-	queryImpl := func(interface{}, ...interface{}) (Result, error) {
+	queryImpl := func(context.Context, interface{}, ...interface{}) (Result, error) {
 		return nil, nil
 	}
 
@@ -0,0 +1,202 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidSubscription defines the error for a subscription that cannot be started.
+	ErrInvalidSubscription = edn.ErrorMessage("Invalid subscription")
+
+	// DefaultPollInterval defines how long a subscription waits between two polls of the latest snapshot.
+	DefaultPollInterval = time.Second
+)
+
+// TxEntry is a basis t of the database observed by a subscription, the transactions committed since the entry before
+// are all seen by its snapshot.
+type TxEntry struct {
+
+	// T is the basis t of the database after the transactions.
+	T int64
+
+	// Snapshot is the snapshot of the database as of T.
+	Snapshot SnapshotChannel
+}
+
+// Subscription streams the basis t of the database of a connection as the transactions are committed.
+type Subscription interface {
+
+	// Entries returns the basis t observed, in the order they were committed. The channel is closed once the
+	// subscription stops.
+	Entries() <-chan TxEntry
+
+	// Err returns the error that stopped the subscription once the entries are closed, the error of the context when
+	// it is done.
+	Err() error
+
+	// Checkpoint returns the t to resume the subscription from, the one after the last basis t handed to the receiver,
+	// or the t it was started from until a first basis t is.
+	Checkpoint() int64
+}
+
+// SubscribeOption sets how a subscription polls the latest snapshot.
+type SubscribeOption func(sub *subscriptionImpl) error
+
+// WithPollInterval sets how long the subscription waits between two polls of the latest snapshot.
+func WithPollInterval(interval time.Duration) SubscribeOption {
+	return func(sub *subscriptionImpl) (err error) {
+		if interval > 0 {
+			sub.interval = interval
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidSubscription, "Poll interval must be positive, got: %s", interval)
+		}
+		return err
+	}
+}
+
+// WithBuffer sets how many entries the subscription polls ahead of the receiver, by default none are and the latest
+// snapshot is only polled as fast as the entries are received.
+func WithBuffer(size int) SubscribeOption {
+	return func(sub *subscriptionImpl) (err error) {
+		if size >= 0 {
+			sub.buffer = size
+		} else {
+			err = edn.MakeErrorWithFormat(ErrInvalidSubscription, "Buffer must not be negative, got: %d", size)
+		}
+		return err
+	}
+}
+
+// subscriptionImpl polls the latest snapshot of a connection.
+type subscriptionImpl struct {
+	conn       ConnectionChannel
+	interval   time.Duration
+	buffer     int
+	checkpoint int64
+	entries    chan TxEntry
+	lock       sync.Mutex
+	err        error
+}
+
+// newSubscription starts polling the latest snapshot of the connection for the basis t from the t on, until the
+// context is done or the snapshot cannot be made.
+func newSubscription(ctx context.Context, conn ConnectionChannel, fromT int64, options ...SubscribeOption) (subscription Subscription, err error) {
+
+	sub := &subscriptionImpl{
+		conn:       conn,
+		interval:   DefaultPollInterval,
+		checkpoint: fromT,
+	}
+
+	for _, option := range options {
+		if err == nil {
+			err = option(sub)
+		}
+	}
+
+	if err == nil {
+		sub.entries = make(chan TxEntry, sub.buffer)
+		go sub.run(ctx)
+		subscription = sub
+	}
+
+	return subscription, err
+}
+
+// Entries returns the basis t observed, in the order they were committed.
+func (sub *subscriptionImpl) Entries() <-chan TxEntry {
+	return sub.entries
+}
+
+// Err returns the error that stopped the subscription.
+func (sub *subscriptionImpl) Err() error {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+	return sub.err
+}
+
+// Checkpoint returns the t to resume the subscription from.
+func (sub *subscriptionImpl) Checkpoint() int64 {
+	return atomic.LoadInt64(&sub.checkpoint)
+}
+
+// run polls the latest snapshot until the context is done or an error that cannot be retried occurs. The retryable
+// errors are left to the next poll, the source having already retried the request with its own settings.
+func (sub *subscriptionImpl) run(ctx context.Context) {
+
+	var err error
+	for err == nil {
+		if err = sub.poll(ctx); err != nil && ctx.Err() == nil && IsRetryable(err) {
+			err = nil
+		}
+
+		if err == nil {
+			err = sub.wait(ctx)
+		}
+	}
+
+	sub.lock.Lock()
+	sub.err = err
+	sub.lock.Unlock()
+
+	close(sub.entries)
+}
+
+// poll sends the basis t of the latest snapshot when it is at or after the checkpoint, blocking while the receiver is
+// not ready for it. A latest snapshot whose basis t is not known yet is left to the next poll.
+func (sub *subscriptionImpl) poll(ctx context.Context) (err error) {
+
+	var latest SnapshotChannel
+	if latest, err = sub.conn.LatestSnapshot(); err == nil {
+		var t int64
+		if t, err = latest.BasisT(); err == nil && t >= sub.Checkpoint() {
+			var snap SnapshotChannel
+			if snap, err = sub.conn.AsOfSnapshot(t); err == nil {
+				select {
+				case sub.entries <- TxEntry{T: t, Snapshot: snap}:
+					atomic.StoreInt64(&sub.checkpoint, t+1)
+				case <-ctx.Done():
+					err = ctx.Err()
+				}
+			}
+		} else if errors.Is(err, ErrUnknownBasis) {
+			err = nil
+		}
+	}
+
+	return err
+}
+
+// wait for the poll interval, or until the context is done.
+func (sub *subscriptionImpl) wait(ctx context.Context) (err error) {
+	timer := time.NewTimer(sub.interval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return err
+}
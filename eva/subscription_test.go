@@ -0,0 +1,208 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// mockDatabase commits the transactions at the next t and makes the snapshots of the database, failing with its
+// failures first.
+type mockDatabase struct {
+	lock     sync.Mutex
+	t        int64
+	failures []error
+}
+
+// transact commits the transaction at the next t.
+func (db *mockDatabase) transact(ctx context.Context, transaction edn.Serializable) (Result, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	t := db.t
+	db.t++
+	return newMockPayloadResult(fmt.Sprintf(`{:db-after #eva.client.service/snapshot-ref {:label "label" :as-of %d}}`, t)), nil
+}
+
+// fail makes the next snapshots fail with the errors.
+func (db *mockDatabase) fail(errs ...error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+	db.failures = append(db.failures, errs...)
+}
+
+// connection to the database, its snapshots are made from the source.
+func (db *mockDatabase) connection(source Source) (ConnectionChannel, error) {
+	return NewBaseConnectionChannel(
+		edn.NewStringElement("label"),
+		source,
+		db.transact,
+		func(asOf edn.Serializable, options ...SnapshotOption) (snap SnapshotChannel, err error) {
+			db.lock.Lock()
+			if len(db.failures) > 0 {
+				err = db.failures[0]
+				db.failures = db.failures[1:]
+			}
+			db.lock.Unlock()
+
+			if err == nil {
+				snap, err = NewBaseSnapshotChannel(edn.NewStringElement("label"), source, nil, nil, asOf, options...)
+			}
+			return snap, err
+		})
+}
+
+var _ = Describe("subscription test", func() {
+
+	var db *mockDatabase
+	var conn ConnectionChannel
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	BeforeEach(func() {
+		bases = newBasisTracker()
+		db = &mockDatabase{t: 1000}
+		ctx, cancel = context.WithCancel(context.Background())
+
+		var err error
+		conn, err = db.connection(&mockSource{})
+		Ω(err).Should(BeNil())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	commit := func(count int) {
+		for i := 0; i < count; i++ {
+			_, err := conn.Transact("[]")
+			Ω(err).Should(BeNil())
+		}
+	}
+
+	receive := func(sub Subscription, count int) (ts []int64) {
+		for i := 0; i < count; i++ {
+			var entry TxEntry
+			Eventually(sub.Entries()).Should(Receive(&entry))
+			Ω(entry.Snapshot.BasisT()).Should(Equal(entry.T))
+			ts = append(ts, entry.T)
+		}
+		return ts
+	}
+
+	It("should stream the basis t as the transactions are committed", func() {
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		Consistently(sub.Entries()).ShouldNot(Receive())
+		Ω(sub.Checkpoint()).Should(BeEquivalentTo(1000))
+
+		commit(1)
+		Ω(receive(sub, 1)).Should(Equal([]int64{1000}))
+		Eventually(sub.Checkpoint).Should(BeEquivalentTo(1001))
+
+		commit(1)
+		Ω(receive(sub, 1)).Should(Equal([]int64{1001}))
+		Eventually(sub.Checkpoint).Should(BeEquivalentTo(1002))
+	})
+
+	It("should resume from the checkpoint", func() {
+		commit(3)
+
+		sub, err := conn.Subscribe(ctx, 1003, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+		Consistently(sub.Entries()).ShouldNot(Receive())
+
+		commit(1)
+		Ω(receive(sub, 1)).Should(Equal([]int64{1003}))
+	})
+
+	It("should stream the latest basis t once for the transactions committed between two polls", func() {
+		commit(3)
+
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		Ω(receive(sub, 1)).Should(Equal([]int64{1002}))
+		Consistently(sub.Entries()).ShouldNot(Receive())
+		Ω(sub.Checkpoint()).Should(BeEquivalentTo(1003))
+	})
+
+	It("should not poll ahead of the receiver", func() {
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond), WithBuffer(1))
+		Ω(err).Should(BeNil())
+
+		commit(1)
+		Eventually(sub.Checkpoint).Should(BeEquivalentTo(1001))
+		commit(1)
+		Consistently(sub.Checkpoint).Should(BeEquivalentTo(1001))
+
+		Ω(receive(sub, 2)).Should(Equal([]int64{1000, 1001}))
+	})
+
+	It("should keep polling after the errors that are retryable", func() {
+		db.fail(io.EOF, io.ErrUnexpectedEOF)
+
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		commit(1)
+		Ω(receive(sub, 1)).Should(Equal([]int64{1000}))
+		Ω(sub.Err()).Should(BeNil())
+	})
+
+	It("should stop on the errors that are not retryable", func() {
+		failure := errors.New("failure")
+		db.fail(failure)
+
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		Eventually(sub.Entries()).Should(BeClosed())
+		Ω(sub.Err()).Should(Equal(failure))
+		Ω(sub.Checkpoint()).Should(BeEquivalentTo(1000))
+	})
+
+	It("should stop once the context is done", func() {
+		sub, err := conn.Subscribe(ctx, 1000, WithPollInterval(time.Millisecond))
+		Ω(err).Should(BeNil())
+
+		commit(1)
+		Ω(receive(sub, 1)).Should(Equal([]int64{1000}))
+		cancel()
+
+		Eventually(sub.Entries()).Should(BeClosed())
+		Ω(sub.Err()).Should(Equal(context.Canceled))
+		Ω(sub.Checkpoint()).Should(BeEquivalentTo(1001))
+	})
+
+	It("should reject the bad options", func() {
+		_, err := conn.Subscribe(ctx, 1000, WithPollInterval(0))
+		Ω(err).Should(test.HaveMessage(ErrInvalidSubscription))
+
+		_, err = conn.Subscribe(ctx, 1000, WithBuffer(-1))
+		Ω(err).Should(test.HaveMessage(ErrInvalidSubscription))
+	})
+})
@@ -0,0 +1,264 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+
+	// ErrInvalidTransactReport defines the error for a transaction result that could not be read.
+	ErrInvalidTransactReport = edn.ErrorMessage("Invalid transaction report")
+
+	// TempIdTag defines the tag of the partitioned temporary ids.
+	TempIdTag = "db/id"
+)
+
+// TransactReport is the outcome of a transaction.
+type TransactReport interface {
+	Result
+
+	// TempIds returns the resolved temporary ids, keyed by the raw (negative) id.
+	TempIds() map[int64]int64
+
+	// PartitionedTempIds returns the resolved temporary ids, keyed by the partition (i.e. ":db.part/user") and then by the id.
+	PartitionedTempIds() map[string]map[int64]int64
+
+	// ResolveTempId returns the entity id the temporary id in the partition resolved to.
+	ResolveTempId(partition string, id int64) (int64, bool)
+
+	// BeforeT returns the basis t of the database before the transaction.
+	BeforeT() int64
+
+	// AfterT returns the basis t of the database after the transaction.
+	AfterT() int64
+
+	// Datoms returns the datoms the transaction asserted and retracted.
+	Datoms() []Datom
+
+	// DbBefore returns the snapshot of the database before the transaction.
+	DbBefore() (SnapshotChannel, error)
+
+	// DbAfter returns the snapshot of the database after the transaction.
+	DbAfter() (SnapshotChannel, error)
+}
+
+// transactReportImpl is the transaction report read from the service response.
+type transactReportImpl struct {
+	Result
+	channel            ConnectionChannel
+	tempIds            map[int64]int64
+	partitionedTempIds map[string]map[int64]int64
+	beforeT            int64
+	afterT             int64
+	datoms             []Datom
+}
+
+// newTransactReport reads the report from the result, results that carry an error are wrapped as they are.
+func newTransactReport(channel ConnectionChannel, result Result) (report TransactReport, err error) {
+
+	if result != nil {
+		impl := &transactReportImpl{
+			Result:             result,
+			channel:            channel,
+			tempIds:            make(map[int64]int64),
+			partitionedTempIds: make(map[string]map[int64]int64),
+		}
+
+		if _, failed := result.Error(); !failed {
+			if _, has := result.String(); has {
+				var elem edn.Element
+				if elem, err = result.Element(); err == nil {
+					err = impl.decode(elem)
+				}
+			}
+		}
+
+		if err == nil {
+			report = impl
+		}
+	}
+
+	return report, err
+}
+
+// decode the transaction result map.
+func (report *transactReportImpl) decode(elem edn.Element) (err error) {
+	if elem.ElementType() == edn.MapType {
+		err = elem.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) (e error) {
+			switch key.String() {
+			case ":tempids":
+				e = report.decodeTempIds(value)
+			case ":eva.client.service/tempids":
+				e = report.decodePartitionedTempIds(value)
+			case ":db-before":
+				report.beforeT, e = decodeBasisT(value)
+			case ":db-after":
+				report.afterT, e = decodeBasisT(value)
+			case ":tx-data":
+				e = report.decodeDatoms(value)
+			}
+			return e
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected a map, got: %s", elem.String())
+	}
+
+	return err
+}
+
+// decodeTempIds reads the `{-1 4398046511104}` map.
+func (report *transactReportImpl) decodeTempIds(value edn.Element) (err error) {
+	if value.ElementType() == edn.MapType {
+		err = value.(edn.CollectionElement).IterateChildren(func(key edn.Element, id edn.Element) (e error) {
+			var tempId, entityId int64
+			if tempId, e = decodeInt(key); e == nil {
+				if entityId, e = decodeInt(id); e == nil {
+					report.tempIds[tempId] = entityId
+				}
+			}
+			return e
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected the tempids to be a map, got: %s", value.String())
+	}
+
+	return err
+}
+
+// decodePartitionedTempIds reads the `{#db/id [:db.part/user -1] 4398046511104}` map.
+func (report *transactReportImpl) decodePartitionedTempIds(value edn.Element) (err error) {
+	if value.ElementType() == edn.MapType {
+		err = value.(edn.CollectionElement).IterateChildren(func(key edn.Element, id edn.Element) (e error) {
+			if key.Tag() == TempIdTag && key.ElementType() == edn.VectorType && key.(edn.CollectionElement).Len() == 2 {
+				keyColl := key.(edn.CollectionElement)
+
+				var part, tempIdElem edn.Element
+				if part, e = keyColl.Get(0); e == nil {
+					if tempIdElem, e = keyColl.Get(1); e == nil {
+						var tempId, entityId int64
+						if tempId, e = decodeInt(tempIdElem); e == nil {
+							if entityId, e = decodeInt(id); e == nil {
+								partition := part.String()
+								if _, has := report.partitionedTempIds[partition]; !has {
+									report.partitionedTempIds[partition] = make(map[int64]int64)
+								}
+								report.partitionedTempIds[partition][tempId] = entityId
+							}
+						}
+					}
+				}
+			} else {
+				e = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected a #%s [partition id] key, got: %s", TempIdTag, key.String())
+			}
+			return e
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected the partitioned tempids to be a map, got: %s", value.String())
+	}
+
+	return err
+}
+
+// decodeDatoms reads the transaction data.
+func (report *transactReportImpl) decodeDatoms(value edn.Element) (err error) {
+	if coll, is := value.(edn.CollectionElement); is && value.ElementType() != edn.MapType {
+		err = coll.IterateChildren(func(_ edn.Element, datomElem edn.Element) (e error) {
+			var datom Datom
+			if datom, e = decodeDatom(datomElem); e == nil {
+				report.datoms = append(report.datoms, datom)
+			} else {
+				e = edn.MakeError(ErrInvalidTransactReport, e)
+			}
+			return e
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected the tx-data to be a list, got: %s", value.String())
+	}
+
+	return err
+}
+
+// decodeBasisT reads the `:as-of` of a `#eva.client.service/snapshot-ref {:label "label" :as-of 1}`.
+func decodeBasisT(value edn.Element) (t int64, err error) {
+	if value.Tag() == string(SnapshotReferenceType) && value.ElementType() == edn.MapType {
+		err = value.(edn.CollectionElement).IterateChildren(func(key edn.Element, v edn.Element) (e error) {
+			if key.String() == ":"+AsOfReferenceProperty {
+				t, e = decodeInt(v)
+			}
+			return e
+		})
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransactReport, "Expected a #%s map, got: %s", SnapshotReferenceType, value.String())
+	}
+
+	return t, err
+}
+
+// TempIds returns the resolved temporary ids, keyed by the raw (negative) id.
+func (report *transactReportImpl) TempIds() map[int64]int64 {
+	return report.tempIds
+}
+
+// PartitionedTempIds returns the resolved temporary ids, keyed by the partition and then by the id.
+func (report *transactReportImpl) PartitionedTempIds() map[string]map[int64]int64 {
+	return report.partitionedTempIds
+}
+
+// ResolveTempId returns the entity id the temporary id in the partition resolved to.
+func (report *transactReportImpl) ResolveTempId(partition string, id int64) (entityId int64, has bool) {
+	var ids map[int64]int64
+	if ids, has = report.partitionedTempIds[partition]; has {
+		entityId, has = ids[id]
+	}
+	return entityId, has
+}
+
+// BeforeT returns the basis t of the database before the transaction.
+func (report *transactReportImpl) BeforeT() int64 {
+	return report.beforeT
+}
+
+// AfterT returns the basis t of the database after the transaction.
+func (report *transactReportImpl) AfterT() int64 {
+	return report.afterT
+}
+
+// Datoms returns the datoms the transaction asserted and retracted.
+func (report *transactReportImpl) Datoms() []Datom {
+	return report.datoms
+}
+
+// DbBefore returns the snapshot of the database before the transaction.
+func (report *transactReportImpl) DbBefore() (SnapshotChannel, error) {
+	return report.snapshot(report.beforeT)
+}
+
+// DbAfter returns the snapshot of the database after the transaction.
+func (report *transactReportImpl) DbAfter() (SnapshotChannel, error) {
+	return report.snapshot(report.afterT)
+}
+
+// snapshot returns the snapshot of the connection as of the basis.
+func (report *transactReportImpl) snapshot(t int64) (snap SnapshotChannel, err error) {
+	if report.channel != nil {
+		snap, err = report.channel.AsOfSnapshot(t)
+	} else {
+		err = edn.MakeError(ErrInvalidTransactReport, "No snapshot for the transaction")
+	}
+
+	return snap, err
+}
@@ -0,0 +1,197 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"errors"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type mockFailedResult struct {
+	*BaseResult
+}
+
+func newMockFailedResult() *mockFailedResult {
+	return &mockFailedResult{
+		BaseResult: NewBaseResult([]byte("{:message \"failed\"}")),
+	}
+}
+
+// Error from the call.
+func (mock *mockFailedResult) Error() (error, bool) {
+	return edn.MakeError(ErrSourceError, nil), true
+}
+
+var _ = Describe("transact report test", func() {
+
+	newConnection := func() ConnectionChannel {
+		config, err := NewConfiguration("{\"category\": \"foo\"}")
+		Ω(err).Should(BeNil())
+
+		tenant, err := NewTenant("foo")
+		Ω(err).Should(BeNil())
+
+		source, err := NewBaseSource(config, tenant, &mockSource{}, makeMockConnChannel, mockQuery)
+		Ω(err).Should(BeNil())
+
+		conn, err := source.Connection("label")
+		Ω(err).Should(BeNil())
+		Ω(conn).ShouldNot(BeNil())
+		return conn
+	}
+
+	readReport := func(payload string) (TransactReport, error) {
+		return newTransactReport(newConnection(), newMockPayloadResult(payload))
+	}
+
+	Context("with a transaction result", func() {
+		It("should read the tempids, basis t and datoms", func() {
+			report, err := newConnection().Transact("[]")
+			Ω(err).Should(BeNil())
+			Ω(report).ShouldNot(BeNil())
+
+			Ω(report.TempIds()).Should(BeEquivalentTo(map[int64]int64{
+				-1: 8796093023233,
+				-2: 8796093023234,
+			}))
+			Ω(report.PartitionedTempIds()).Should(BeEquivalentTo(map[string]map[int64]int64{
+				":db.part/user": {
+					-1: 8796093023233,
+					-2: 8796093023234,
+				},
+			}))
+
+			id, has := report.ResolveTempId(":db.part/user", -2)
+			Ω(has).Should(BeTrue())
+			Ω(id).Should(BeEquivalentTo(8796093023234))
+
+			_, has = report.ResolveTempId(":db.part/user", -3)
+			Ω(has).Should(BeFalse())
+
+			_, has = report.ResolveTempId(":db.part/tx", -1)
+			Ω(has).Should(BeFalse())
+
+			Ω(report.BeforeT()).Should(BeEquivalentTo(1))
+			Ω(report.AfterT()).Should(BeEquivalentTo(2))
+
+			datoms := report.Datoms()
+			Ω(datoms).Should(HaveLen(3))
+			Ω(datoms[1].E).Should(BeEquivalentTo(8796093023233))
+			Ω(datoms[1].A).Should(BeEquivalentTo(8))
+			Ω(datoms[1].V.Equals(edn.NewStringElement("First Book"))).Should(BeTrue())
+			Ω(datoms[1].Tx).Should(BeEquivalentTo(4398046511106))
+			Ω(datoms[1].Added).Should(BeTrue())
+			Ω(datoms[2].Added).Should(BeFalse())
+			Ω(datoms[0].V.ElementType()).Should(BeEquivalentTo(edn.InstantType))
+			Ω(datoms[2].String()).Should(BeEquivalentTo("#datom [8796093023234 9 \"James Madison\" 4398046511106 false]"))
+		})
+
+		It("should return the snapshots before and after the transaction", func() {
+			report, err := newConnection().Transact("[]")
+			Ω(err).Should(BeNil())
+
+			before, err := report.DbBefore()
+			Ω(err).Should(BeNil())
+			Ω(before).ShouldNot(BeNil())
+			Ω(before.AsOf()).ShouldNot(BeNil())
+			Ω(*before.AsOf()).Should(BeEquivalentTo(1))
+			Ω(before.Label()).Should(BeEquivalentTo("label"))
+
+			after, err := report.DbAfter()
+			Ω(err).Should(BeNil())
+			Ω(after).ShouldNot(BeNil())
+			Ω(after.AsOf()).ShouldNot(BeNil())
+			Ω(*after.AsOf()).Should(BeEquivalentTo(2))
+		})
+
+		It("should keep the result", func() {
+			report, err := readReport("{:tempids {}}")
+			Ω(err).Should(BeNil())
+
+			str, has := report.String()
+			Ω(has).Should(BeTrue())
+			Ω(str).Should(BeEquivalentTo("{:tempids {}}"))
+
+			_, failed := report.Error()
+			Ω(failed).Should(BeFalse())
+			Ω(report.Datoms()).Should(BeEmpty())
+			Ω(report.TempIds()).Should(BeEmpty())
+		})
+
+		It("should ignore the keys it does not know", func() {
+			report, err := readReport("{:tempids {-1 5} :something-new [1 2 3]}")
+			Ω(err).Should(BeNil())
+			Ω(report.TempIds()).Should(HaveKeyWithValue(int64(-1), int64(5)))
+		})
+
+		It("should not read a result with an error", func() {
+			report, err := newTransactReport(newConnection(), newMockFailedResult())
+			Ω(err).Should(BeNil())
+			Ω(report).ShouldNot(BeNil())
+
+			err, failed := report.Error()
+			Ω(failed).Should(BeTrue())
+			Ω(err).Should(test.HaveMessage(ErrSourceError))
+			Ω(report.TempIds()).Should(BeEmpty())
+		})
+
+		It("should not create a report without a result", func() {
+			report, err := newTransactReport(newConnection(), nil)
+			Ω(err).Should(BeNil())
+			Ω(report).Should(BeNil())
+		})
+	})
+
+	Context("with an invalid transaction result", func() {
+		for name, payload := range map[string]string{
+			"not a map":                 "[]",
+			"tempids not a map":         "{:tempids [1 2]}",
+			"tempids not integers":      "{:tempids {-1 \"a\"}}",
+			"partitioned tempids key":   "{:eva.client.service/tempids {[:db.part/user -1] 1}}",
+			"partitioned tempids value": "{:eva.client.service/tempids {#db/id [:db.part/user -1] :a}}",
+			"db-before tag":             "{:db-before {:label \"label\" :as-of 1}}",
+			"db-after as-of":            "{:db-after #eva.client.service/snapshot-ref {:as-of \"1\"}}",
+			"tx-data not a list":        "{:tx-data {}}",
+			"datom tag":                 "{:tx-data ([1 2 3 4 true])}",
+			"datom length":              "{:tx-data (#datom [1 2 3 4])}",
+			"datom added":               "{:tx-data (#datom [1 2 3 4 5])}",
+			"datom entity":              "{:tx-data (#datom [:a 2 3 4 true])}",
+		} {
+			name, payload := name, payload
+			It("should fail with "+name, func() {
+				report, err := readReport(payload)
+				Ω(err).ShouldNot(BeNil())
+				Ω(err).Should(test.HaveMessage(ErrInvalidTransactReport))
+				Ω(report).Should(BeNil())
+			})
+		}
+
+		It("should keep the error of the datom it could not read", func() {
+			_, err := readReport("{:tx-data (#datom [1 2 3 4 5])}")
+			Ω(errors.Is(err, ErrInvalidTransactReport)).Should(BeTrue())
+			Ω(errors.Is(err, ErrInvalidDatom)).Should(BeTrue())
+		})
+
+		It("should fail with an invalid payload", func() {
+			report, err := readReport("{:tempids")
+			Ω(err).ShouldNot(BeNil())
+			Ω(report).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,383 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrInvalidTransaction defines the error for a transaction the builder cannot build.
+	ErrInvalidTransaction = edn.ErrorMessage("Invalid transaction")
+
+	// UserPartition defines the partition of the user entities.
+	UserPartition = ":db.part/user"
+)
+
+// TempID is the temporary id of an entity a transaction creates, `#db/id [:db.part/user -1]`. The temporary ids with
+// the same partition and number stand for the same entity within a transaction.
+type TempID struct {
+
+	// Partition is the partition of the entity, i.e. `:db.part/user`.
+	Partition string
+
+	// N is the negative number of the temporary id.
+	N int64
+}
+
+// Element returns the temporary id as a `#db/id [partition n]` element.
+func (id TempID) Element() (elem edn.Element, err error) {
+
+	var partition edn.Element
+	if partition, err = keywordOf(id.Partition); err == nil && id.N < 0 {
+		if elem, err = edn.NewVector(partition, edn.NewIntegerElement(id.N)); err == nil {
+			err = elem.SetTag(TempIdTag)
+		}
+	} else {
+		err = edn.MakeErrorWithFormat(ErrInvalidTransaction, "Invalid temporary id: %s %d", id.Partition, id.N)
+	}
+
+	return elem, err
+}
+
+// Serialize the temporary id.
+func (id TempID) Serialize(serializer edn.Serializer) (str string, err error) {
+	var elem edn.Element
+	if elem, err = id.Element(); err == nil {
+		str, err = elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the temporary id.
+func (id TempID) String() string {
+	return fmt.Sprintf("#%s [%s %d]", TempIdTag, id.Partition, id.N)
+}
+
+// Resolve returns the entity id the temporary id resolved to in the transaction of the report.
+func (id TempID) Resolve(report TransactReport) (entityId int64, has bool) {
+	if report != nil {
+		if entityId, has = report.ResolveTempId(id.Partition, id.N); !has {
+			entityId, has = report.TempIds()[id.N]
+		}
+	}
+	return entityId, has
+}
+
+// EntityRef is the value of a reference attribute, which decodes from the `{:db/id 42 :db/ident :book.genre/fiction}`
+// its pull pattern, `[:db/id :db/ident]`, pulls and which a transaction refers to by its ident, or else by its id.
+type EntityRef struct {
+	Id    int64  `edn:"db/id"`
+	Ident string `edn:"db/ident"`
+}
+
+// Element returns the ident of the entity as a keyword, or else its id.
+func (ref EntityRef) Element() (elem edn.Element, err error) {
+	switch {
+	case ref.Ident != "":
+		elem, err = Ident(ref.Ident).Element()
+	case ref.Id != 0:
+		elem = edn.NewIntegerElement(ref.Id)
+	default:
+		err = edn.MakeError(ErrInvalidTransaction, "Empty entity reference")
+	}
+	return elem, err
+}
+
+// Tx is a transaction built operation by operation instead of written as a string. Entity ids are integers, TempIDs,
+// EntityRefs, idents such as `:book/first` or lookup refs given as `[]interface{}{":book/isbn", "123"}`. Attributes
+// are keywords, values are strings, numbers, booleans, times, uuids, TempIDs, EntityRefs, edn elements or the keywords
+// made by Ident, and the strings are always string values. The transaction is handed to Transact or With as it is.
+type Tx struct {
+	ops  []txPart
+	next map[string]int64
+}
+
+// txPart builds a part of a transaction, its errors are reported when the transaction is built.
+type txPart func() (edn.Element, error)
+
+// NewTx starts an empty transaction.
+func NewTx() *Tx {
+	return &Tx{
+		next: make(map[string]int64),
+	}
+}
+
+// TempID returns a temporary id no other TempID of the transaction has, in the user partition when none is given.
+func (tx *Tx) TempID(partition ...string) TempID {
+	part := UserPartition
+	if len(partition) > 0 {
+		part = partition[0]
+	}
+
+	tx.next[part]--
+	return TempID{
+		Partition: part,
+		N:         tx.next[part],
+	}
+}
+
+// Add asserts the value of the attribute of the entity, `[:db/add e attr v]`.
+func (tx *Tx) Add(e interface{}, attr string, v interface{}) *Tx {
+	return tx.op(":db/add", txEntity(e), txAttribute(attr), txValue(v))
+}
+
+// Retract retracts the value of the attribute of the entity, `[:db/retract e attr v]`.
+func (tx *Tx) Retract(e interface{}, attr string, v interface{}) *Tx {
+	return tx.op(":db/retract", txEntity(e), txAttribute(attr), txValue(v))
+}
+
+// RetractEntity retracts the entity and its components, `[:db.fn/retractEntity e]`.
+func (tx *Tx) RetractEntity(e interface{}) *Tx {
+	return tx.op(":db.fn/retractEntity", txEntity(e))
+}
+
+// CAS sets the value of the attribute of the entity only if it is still the old one, `[:db.fn/cas e attr old new]`.
+// A nil old value means the entity must not have the attribute yet.
+func (tx *Tx) CAS(e interface{}, attr string, old interface{}, new interface{}) *Tx {
+	oldValue := txValue(old)
+	if old == nil {
+		oldValue = func() (edn.Element, error) {
+			return edn.NewNilElement(), nil
+		}
+	}
+	return tx.op(":db.fn/cas", txEntity(e), txAttribute(attr), oldValue, txValue(new))
+}
+
+// Entity asserts the attributes of an entity map, `{:db/id #db/id [:db.part/user -1] :book/title "First Book"}`. The
+// id is under `:db/id`, maps nest component entities and slices hold the values of cardinality many attributes.
+func (tx *Tx) Entity(attributes map[string]interface{}) *Tx {
+	tx.ops = append(tx.ops, txEntityMap(attributes))
+	return tx
+}
+
+// Call calls the transaction function with the arguments, `[:my/fn arg ...]`.
+func (tx *Tx) Call(fn string, args ...interface{}) *Tx {
+	parts := make([]txPart, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, txValue(arg))
+	}
+	return tx.op(fn, parts...)
+}
+
+// Len returns the number of operations of the transaction.
+func (tx *Tx) Len() int {
+	return len(tx.ops)
+}
+
+// Element builds the transaction, the vector of its operations.
+func (tx *Tx) Element() (elem edn.Element, err error) {
+
+	ops := make([]edn.Element, 0, len(tx.ops))
+	for _, op := range tx.ops {
+		var opElem edn.Element
+		if opElem, err = op(); err != nil {
+			break
+		}
+		ops = append(ops, opElem)
+	}
+
+	if err == nil {
+		elem, err = edn.NewVector(ops...)
+	}
+
+	return elem, err
+}
+
+// Serialize the transaction.
+func (tx *Tx) Serialize(serializer edn.Serializer) (str string, err error) {
+	var elem edn.Element
+	if elem, err = tx.Element(); err == nil {
+		str, err = elem.Serialize(serializer)
+	}
+	return str, err
+}
+
+// String of the transaction, empty when it cannot be built.
+func (tx *Tx) String() (str string) {
+	if elem, err := tx.Element(); err == nil {
+		str = elem.String()
+	}
+	return str
+}
+
+// Ident makes the keyword of an ident to use as a value, such as an enum `:book.genre/fiction`.
+func Ident(name string) QueryPart {
+	part := &queryPartImpl{}
+	if part.elem, part.err = keywordOf(name); part.err != nil {
+		part.err = edn.MakeErrorWithFormat(ErrInvalidTransaction, "Expected an ident, got: %#v", name)
+	}
+	return part
+}
+
+// op adds the `[op parts...]` operation.
+func (tx *Tx) op(op string, parts ...txPart) *Tx {
+	tx.ops = append(tx.ops, func() (elem edn.Element, err error) {
+		items := make([]edn.Element, 0, len(parts)+1)
+
+		var opElem edn.Element
+		if opElem, err = txAttribute(op)(); err == nil {
+			items = append(items, opElem)
+			for _, part := range parts {
+				var item edn.Element
+				if item, err = part(); err != nil {
+					break
+				}
+				items = append(items, item)
+			}
+		}
+
+		if err == nil {
+			elem, err = edn.NewVector(items...)
+		}
+
+		return elem, err
+	})
+
+	return tx
+}
+
+// txAttribute reads an attribute or a function, which are keywords.
+func txAttribute(attr string) txPart {
+	return func() (elem edn.Element, err error) {
+		if elem, err = keywordOf(attr); err != nil {
+			err = edn.MakeErrorWithFormat(ErrInvalidTransaction, "Expected an attribute, got: %#v", attr)
+		}
+		return elem, err
+	}
+}
+
+// txEntity reads an entity id, a string being an ident and a slice a lookup ref.
+func txEntity(e interface{}) txPart {
+	return func() (elem edn.Element, err error) {
+		switch typed := e.(type) {
+		case string:
+			elem, err = Ident(typed).Element()
+		case []interface{}:
+			if len(typed) == 2 {
+				if attr, is := typed[0].(string); is {
+					var attrElem, value edn.Element
+					if attrElem, err = txAttribute(attr)(); err == nil {
+						if value, err = txValue(typed[1])(); err == nil {
+							elem, err = edn.NewVector(attrElem, value)
+						}
+					}
+				}
+			}
+
+			if elem == nil && err == nil {
+				err = edn.MakeErrorWithFormat(ErrInvalidTransaction, "Expected a lookup ref [attribute value], got: %#v", e)
+			}
+		case nil:
+			err = edn.MakeError(ErrInvalidTransaction, "No entity")
+		default:
+			elem, err = txValue(e)()
+		}
+		return elem, err
+	}
+}
+
+// txValue reads a value, the strings being string values.
+func txValue(v interface{}) txPart {
+	return func() (elem edn.Element, err error) {
+		switch typed := v.(type) {
+		case nil:
+			err = edn.MakeError(ErrInvalidTransaction, "No value")
+		case string:
+			elem = edn.NewStringElement(typed)
+		case TempID:
+			elem, err = typed.Element()
+		case EntityRef:
+			elem, err = typed.Element()
+		case *EntityRef:
+			if typed != nil {
+				elem, err = typed.Element()
+			} else {
+				err = edn.MakeError(ErrInvalidTransaction, "No value")
+			}
+		case edn.Element:
+			elem = typed
+		case QueryPart:
+			elem, err = typed.Element()
+		case map[string]interface{}:
+			elem, err = txEntityMap(typed)()
+		case []interface{}:
+			items := make([]edn.Element, 0, len(typed))
+			for _, item := range typed {
+				var itemElem edn.Element
+				if itemElem, err = txValue(item)(); err != nil {
+					break
+				}
+				items = append(items, itemElem)
+			}
+
+			if err == nil {
+				elem, err = edn.NewVector(items...)
+			}
+		default:
+			if elem, err = edn.NewPrimitiveElement(v); err != nil {
+				err = edn.MakeErrorWithFormat(ErrInvalidTransaction, "Unsupported value: %#v", v)
+			}
+		}
+		return elem, err
+	}
+}
+
+// txEntityMap reads an entity map, its keys sorted so that the transaction is always written the same way.
+func txEntityMap(attributes map[string]interface{}) txPart {
+	return func() (elem edn.Element, err error) {
+
+		keys := make([]string, 0, len(attributes))
+		for key := range attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]edn.Pair, 0, len(keys))
+		for _, key := range keys {
+			var attr, value edn.Element
+			if attr, err = txAttribute(key)(); err == nil {
+				if key == EntityIdAttribute {
+					value, err = txEntity(attributes[key])()
+				} else {
+					value, err = txValue(attributes[key])()
+				}
+			}
+
+			var pair edn.Pair
+			if err == nil {
+				pair, err = edn.NewPair(attr, value)
+			}
+
+			if err != nil {
+				break
+			}
+			pairs = append(pairs, pair)
+		}
+
+		if err == nil && len(pairs) == 0 {
+			err = edn.MakeError(ErrInvalidTransaction, "Empty entity")
+		}
+
+		if err == nil {
+			elem, err = edn.NewMap(pairs...)
+		}
+
+		return elem, err
+	}
+}
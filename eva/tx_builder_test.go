@@ -0,0 +1,197 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"context"
+
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tx builder test", func() {
+
+	build := func(tx *Tx) string {
+		elem, err := tx.Element()
+		Ω(err).Should(BeNil())
+		return elem.String()
+	}
+
+	Context("with temporary ids", func() {
+		It("should write the temporary ids", func() {
+			id := TempID{Partition: UserPartition, N: -1}
+			Ω(id.String()).Should(BeEquivalentTo(`#db/id [:db.part/user -1]`))
+
+			str, err := id.Serialize(edn.EvaEdnMimeType)
+			Ω(err).Should(BeNil())
+			Ω(str).Should(BeEquivalentTo(`#db/id [:db.part/user -1]`))
+
+			_, err = TempID{Partition: "db.part/user", N: -1}.Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidTransaction))
+
+			_, err = TempID{Partition: UserPartition, N: 1}.Element()
+			Ω(err).Should(test.HaveMessage(ErrInvalidTransaction))
+		})
+
+		It("should hand out new temporary ids", func() {
+			tx := NewTx()
+			Ω(tx.TempID()).Should(Equal(TempID{Partition: UserPartition, N: -1}))
+			Ω(tx.TempID()).Should(Equal(TempID{Partition: UserPartition, N: -2}))
+			Ω(tx.TempID(":db.part/tx")).Should(Equal(TempID{Partition: ":db.part/tx", N: -1}))
+		})
+
+		It("should resolve the temporary ids", func() {
+			report, err := newTransactReport(nil, newMockPayloadResult(mockTransactPayload))
+			Ω(err).Should(BeNil())
+
+			id, has := TempID{Partition: UserPartition, N: -2}.Resolve(report)
+			Ω(has).Should(BeTrue())
+			Ω(id).Should(BeEquivalentTo(8796093023234))
+
+			id, has = TempID{Partition: ":db.part/other", N: -1}.Resolve(report)
+			Ω(has).Should(BeTrue())
+			Ω(id).Should(BeEquivalentTo(8796093023233))
+
+			_, has = TempID{Partition: UserPartition, N: -3}.Resolve(report)
+			Ω(has).Should(BeFalse())
+
+			_, has = TempID{Partition: UserPartition, N: -1}.Resolve(nil)
+			Ω(has).Should(BeFalse())
+		})
+	})
+
+	Context("building the operations", func() {
+		It("should build the assertions and retractions", func() {
+			tx := NewTx()
+			book := tx.TempID()
+			tx.Add(book, ":book/title", "First Book").
+				Add(book, ":book/year_published", 2017).
+				Add(book, ":book/genre", Ident(":book.genre/history")).
+				Retract(42, ":book/title", "Old Title").
+				Retract([]interface{}{":book/isbn", "123"}, ":book/year_published", 2000).
+				RetractEntity(":book/first").
+				CAS(42, ":book/year_published", 2016, 2017).
+				CAS(43, ":book/year_published", nil, 2017).
+				Call(":book.fn/publish", book, "now")
+
+			Ω(tx.Len()).Should(BeEquivalentTo(9))
+			Ω(build(tx)).Should(BeEquivalentTo(`[` +
+				`[:db/add #db/id [:db.part/user -1] :book/title "First Book"] ` +
+				`[:db/add #db/id [:db.part/user -1] :book/year_published 2017] ` +
+				`[:db/add #db/id [:db.part/user -1] :book/genre :book.genre/history] ` +
+				`[:db/retract 42 :book/title "Old Title"] ` +
+				`[:db/retract [:book/isbn "123"] :book/year_published 2000] ` +
+				`[:db.fn/retractEntity :book/first] ` +
+				`[:db.fn/cas 42 :book/year_published 2016 2017] ` +
+				`[:db.fn/cas 43 :book/year_published nil 2017] ` +
+				`[:book.fn/publish #db/id [:db.part/user -1] "now"]]`))
+		})
+
+		It("should build the entity maps", func() {
+			tx := NewTx()
+			tx.Entity(map[string]interface{}{
+				":db/id":       tx.TempID(),
+				":book/title":  "First Book",
+				":book/tags":   []interface{}{"history", "politics"},
+				":book/author": map[string]interface{}{":person/name": "James Madison"},
+			})
+
+			expected, err := edn.Parse(`[{:book/author {:person/name "James Madison"} :book/tags ["history" "politics"] :book/title "First Book" :db/id #db/id [:db.part/user -1]}]`)
+			Ω(err).Should(BeNil())
+
+			elem, err := tx.Element()
+			Ω(err).Should(BeNil())
+			Ω(elem.Equals(expected)).Should(BeTrue())
+			Ω(tx.Len()).Should(BeEquivalentTo(1))
+		})
+
+		It("should refer to the entities by their ident or id", func() {
+			tx := NewTx().
+				Add(EntityRef{Id: 42}, ":book/genre", EntityRef{Id: 7, Ident: ":book.genre/fiction"}).
+				Add(42, ":book/author", &EntityRef{Id: 43})
+			Ω(build(tx)).Should(BeEquivalentTo(`[[:db/add 42 :book/genre :book.genre/fiction] [:db/add 42 :book/author 43]]`))
+
+			var nilRef *EntityRef
+			for _, ref := range []interface{}{EntityRef{}, nilRef} {
+				_, err := NewTx().Add(42, ":book/genre", ref).Element()
+				Ω(err).Should(test.HaveMessage(ErrInvalidTransaction))
+			}
+		})
+
+		It("should decode the references pulled", func() {
+			var ref EntityRef
+			Ω(edn.Unmarshal(`{:db/id 7 :db/ident :book.genre/fiction}`, &ref)).Should(BeNil())
+			Ω(ref).Should(BeEquivalentTo(EntityRef{Id: 7, Ident: ":book.genre/fiction"}))
+		})
+
+		It("should keep the strings as values", func() {
+			Ω(build(NewTx().Add(1, ":book/title", ":book/title"))).Should(BeEquivalentTo(`[[:db/add 1 :book/title ":book/title"]]`))
+		})
+
+		It("should reject the operations it cannot build", func() {
+			for _, tx := range []*Tx{
+				NewTx().Add(1, "book/title", "First Book"),
+				NewTx().Add(1, ":book/title", nil),
+				NewTx().Add(nil, ":book/title", "First Book"),
+				NewTx().Add("book", ":book/title", "First Book"),
+				NewTx().Add([]interface{}{":book/isbn"}, ":book/title", "First Book"),
+				NewTx().Add(1, ":book/title", struct{}{}),
+				NewTx().Add(1, ":book/genre", Ident("history")),
+				NewTx().Entity(map[string]interface{}{}),
+				NewTx().Call("book.fn/publish"),
+			} {
+				_, err := tx.Element()
+				Ω(err).Should(test.HaveMessage(ErrInvalidTransaction))
+				Ω(tx.String()).Should(BeEmpty())
+
+				_, err = tx.Serialize(edn.EvaEdnMimeType)
+				Ω(err).Should(test.HaveMessage(ErrInvalidTransaction))
+			}
+		})
+	})
+
+	Context("transacting", func() {
+		It("should hand the transaction to the connection", func() {
+			var transacted []string
+			conn, err := NewBaseConnectionChannel(
+				edn.NewStringElement("label"),
+				&mockSource{},
+				func(ctx context.Context, transaction edn.Serializable) (Result, error) {
+					str, e := transaction.Serialize(edn.EvaEdnMimeType)
+					Ω(e).Should(BeNil())
+					transacted = append(transacted, str)
+					return newMockPayloadResult(mockTransactPayload), nil
+				},
+				func(asOf edn.Serializable, options ...SnapshotOption) (SnapshotChannel, error) {
+					return nil, nil
+				})
+			Ω(err).Should(BeNil())
+
+			tx := NewTx()
+			book := tx.TempID()
+			tx.Add(book, ":book/title", "First Book")
+
+			report, err := conn.Transact(tx)
+			Ω(err).Should(BeNil())
+			Ω(transacted).Should(Equal([]string{`[[:db/add #db/id [:db.part/user -1] :book/title "First Book"]]`}))
+
+			id, has := book.Resolve(report)
+			Ω(has).Should(BeTrue())
+			Ω(id).Should(BeEquivalentTo(8796093023233))
+		})
+	})
+})
@@ -0,0 +1,262 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"strings"
+
+	"github.com/Workiva/eva-client-go/edn"
+)
+
+const (
+	// ErrSchemaViolation defines the error for transaction data that does not match the schema.
+	ErrSchemaViolation = edn.ErrorMessage("Transaction does not match the schema")
+)
+
+// acceptedTypes are the element types an attribute accepts besides the one of its own type, which is named after it
+// in edn/type.go. The references are checked apart.
+var acceptedTypes = map[ValueType][]edn.ElementType{
+	BigInt: {edn.IntegerType},
+	Double: {edn.FloatType},
+	URI:    {edn.StringType},
+	Bytes:  {edn.StringType},
+}
+
+// ValueTypeOf returns the value type of the element, for the elements which are values of an attribute type.
+func ValueTypeOf(elem edn.Element) (valueType ValueType, is bool) {
+	if elem != nil {
+		valueType = ValueType(elem.ElementType())
+		is = valueTypes[valueType]
+	}
+	return valueType, is
+}
+
+// Validate checks the transaction data, a string, an edn element, a Tx or any other serializable transaction, against
+// the attributes of the schema before it is sent: the attributes must be installed, the values must have their type,
+// or be entity ids, idents, temporary ids, lookup refs or nested entities for the references, and the attributes of
+// the lookup refs must be unique. All the problems found are reported by one ErrSchemaViolation. The attributes of the
+// `db` namespaces, the attributes given by their entity id and the transaction functions are not checked.
+func (schema *InstalledSchema) Validate(data interface{}) (err error) {
+
+	var transactions []edn.Serializable
+	var elem edn.Element
+	if transactions, err = transactionsOf([]interface{}{data}); err == nil {
+		elem, err = transactionElement(transactions[0])
+	}
+
+	if err == nil {
+		validator := &txValidator{
+			schema: schema,
+		}
+		validator.transaction(elem)
+
+		if len(validator.problems) > 0 {
+			err = edn.MakeError(ErrSchemaViolation, strings.Join(validator.problems, "; "))
+		}
+	}
+
+	return err
+}
+
+// txValidator collects the problems of a transaction.
+type txValidator struct {
+	schema   *InstalledSchema
+	problems []string
+}
+
+// report a problem.
+func (validator *txValidator) report(problem string, elem edn.Element) {
+	validator.problems = append(validator.problems, problem+": "+elem.String())
+}
+
+// transaction checks the operations of the transaction.
+func (validator *txValidator) transaction(elem edn.Element) {
+	if ops, is := sequenceOf(elem); is {
+		for _, op := range ops {
+			switch op.ElementType() {
+			case edn.MapType:
+				validator.entity(op)
+			case edn.VectorType, edn.ListType:
+				validator.operation(op)
+			default:
+				validator.report("Expected an operation", op)
+			}
+		}
+	} else {
+		validator.report("Expected a vector of operations", elem)
+	}
+}
+
+// operation checks an operation, `[:db/add e attr v]`.
+func (validator *txValidator) operation(op edn.Element) {
+	items, _ := sequenceOf(op)
+
+	fn := ""
+	if len(items) > 0 {
+		fn = items[0].String()
+	}
+
+	switch {
+	case fn == ":db/add" || fn == ":db/retract":
+		if len(items) == 4 {
+			validator.entityId(items[1])
+			if def, known := validator.attribute(items[2]); known {
+				validator.value(def, items[3], false)
+			}
+		} else {
+			validator.report("Expected an entity, an attribute and a value", op)
+		}
+	case fn == ":db.fn/cas":
+		if len(items) == 5 {
+			validator.entityId(items[1])
+			if def, known := validator.attribute(items[2]); known {
+				if items[3].ElementType() != edn.NilType {
+					validator.value(def, items[3], false)
+				}
+				validator.value(def, items[4], false)
+			}
+		} else {
+			validator.report("Expected an entity, an attribute, an old and a new value", op)
+		}
+	case fn == ":db.fn/retractEntity":
+		if len(items) == 2 {
+			validator.entityId(items[1])
+		} else {
+			validator.report("Expected an entity", op)
+		}
+	case len(items) == 0 || items[0].ElementType() != edn.KeywordType:
+		validator.report("Expected an operation", op)
+	}
+}
+
+// entity checks an entity map, `{:db/id e attr v ...}`.
+func (validator *txValidator) entity(entity edn.Element) {
+	_ = entity.(edn.CollectionElement).IterateChildren(func(key edn.Element, value edn.Element) error {
+		attr := key.String()
+		switch {
+		case key.ElementType() != edn.KeywordType:
+			validator.report("Expected an attribute", key)
+		case attr == EntityIdAttribute:
+			validator.entityId(value)
+		case isReverseAttribute(attr) && isSystemAttribute(attr):
+		case isReverseAttribute(attr):
+			forward := strings.Replace(attr, "/_", "/", 1)
+			if def, known := validator.schema.Attribute(forward); !known || def.ValueType != Ref {
+				validator.report("Expected the reverse of a reference", key)
+			} else {
+				def.Cardinality = Many
+				validator.values(def, value)
+			}
+		default:
+			if def, known := validator.attribute(key); known {
+				validator.values(def, value)
+			}
+		}
+		return nil
+	})
+}
+
+// values checks the value of an attribute of an entity map, which is a collection of values for the attributes of
+// cardinality many and for the reverse references. A vector of a keyword and a value is a lookup ref for a reference.
+func (validator *txValidator) values(def AttributeDef, value edn.Element) {
+	items, is := sequenceOf(value)
+	single := isTempId(value) || (def.ValueType == Ref && len(items) == 2 && items[0].ElementType() == edn.KeywordType)
+	if is && def.Cardinality == Many && !single {
+		for _, item := range items {
+			validator.value(def, item, true)
+		}
+	} else {
+		validator.value(def, value, true)
+	}
+}
+
+// attribute returns the definition of the attribute, reporting the attributes which are not installed.
+func (validator *txValidator) attribute(attr edn.Element) (def AttributeDef, known bool) {
+	switch attr.ElementType() {
+	case edn.KeywordType:
+		ident := attr.String()
+		if def, known = validator.schema.Attribute(ident); !known && !isSystemAttribute(ident) {
+			validator.report("Unknown attribute", attr)
+		}
+	case edn.IntegerType:
+		def, known = validator.schema.Attribute(attr.Value())
+	default:
+		validator.report("Expected an attribute", attr)
+	}
+	return def, known
+}
+
+// value checks a value of the attribute, nested entities being allowed in entity maps.
+func (validator *txValidator) value(def AttributeDef, value edn.Element, nested bool) {
+	switch {
+	case value.ElementType() == edn.NilType:
+		validator.report(def.Ident+" has no value", value)
+	case def.ValueType == Ref && value.ElementType() == edn.MapType && nested:
+		validator.entity(value)
+	case def.ValueType == Ref:
+		if !validator.reference(value) {
+			validator.report(def.Ident+" expects a reference", value)
+		}
+	default:
+		valueType, _ := ValueTypeOf(value)
+		accepted := valueType == def.ValueType
+		for _, elemType := range acceptedTypes[def.ValueType] {
+			accepted = accepted || value.ElementType() == elemType
+		}
+
+		if !accepted {
+			validator.report(def.Ident+" expects a "+string(def.ValueType), value)
+		}
+	}
+}
+
+// entityId checks the entity of an operation.
+func (validator *txValidator) entityId(e edn.Element) {
+	if !validator.reference(e) {
+		validator.report("Expected an entity id, an ident, a temporary id or a lookup ref", e)
+	}
+}
+
+// reference checks if the element refers to an entity: an entity id, an ident, a temporary id or a lookup ref, whose
+// attribute must be unique.
+func (validator *txValidator) reference(ref edn.Element) (is bool) {
+	switch ref.ElementType() {
+	case edn.IntegerType, edn.KeywordType:
+		is = true
+	case edn.VectorType:
+		if is = isTempId(ref); !is {
+			if items, _ := sequenceOf(ref); len(items) == 2 && items[0].ElementType() == edn.KeywordType {
+				is = true
+				if def, known := validator.attribute(items[0]); known {
+					if def.Unique == "" {
+						validator.report(def.Ident+" is not unique, it cannot be in a lookup ref", ref)
+					}
+					validator.value(def, items[1], false)
+				}
+			}
+		}
+	}
+	return is
+}
+
+// isTempId checks if the element is a temporary id, `#db/id [:db.part/user -1]`.
+func isTempId(elem edn.Element) bool {
+	return elem.Tag() == TempIdTag && elem.ElementType() == edn.VectorType
+}
+
+// isSystemAttribute checks if the attribute belongs to the `db` namespaces, `:db/ident` or `:db.install/_attribute`.
+func isSystemAttribute(ident string) bool {
+	return strings.HasPrefix(ident, ":db/") || strings.HasPrefix(ident, ":db.")
+}
@@ -0,0 +1,146 @@
+// Copyright 2018-2019 Workiva Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eva
+
+import (
+	"github.com/Workiva/eva-client-go/edn"
+	"github.com/Workiva/eva-client-go/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("tx validator test", func() {
+
+	var schema *InstalledSchema
+
+	BeforeEach(func() {
+		elem, err := edn.Parse(bookAttributes)
+		Ω(err).Should(BeNil())
+
+		var defs []AttributeDef
+		values, _ := sequenceOf(elem)
+		for _, value := range values {
+			def, err := installedAttributeOf(value)
+			Ω(err).Should(BeNil())
+			defs = append(defs, def)
+		}
+		schema = NewInstalledSchema(defs...)
+	})
+
+	It("should map the element types to the value types", func() {
+		for elem, valueType := range map[edn.Element]ValueType{
+			edn.NewStringElement("First Book"): String,
+			edn.NewIntegerElement(2017):        Long,
+			edn.NewFloatElement(4.5):           Float,
+			edn.NewBooleanElement(true):        Boolean,
+			keywordOrNil(":book.genre/poetry"): Keyword,
+		} {
+			actual, is := ValueTypeOf(elem)
+			Ω(is).Should(BeTrue())
+			Ω(actual).Should(BeEquivalentTo(valueType))
+		}
+
+		_, is := ValueTypeOf(symbolOrNil("?b"))
+		Ω(is).Should(BeFalse())
+		_, is = ValueTypeOf(nil)
+		Ω(is).Should(BeFalse())
+	})
+
+	It("should accept the transactions matching the schema", func() {
+		for _, data := range []interface{}{
+			`[[:db/add #db/id [:db.part/user -1] :book/title "First Book"]
+			  [:db/add [:book/isbn "123"] :book/year_published 2017]
+			  [:db/retract 42 :book/tags :history]
+			  [:db/add 42 :book/author :person/madison]
+			  [:db/add 42 :book/author [:book/isbn "456"]]
+			  [:db/add 42 12 2018]
+			  [:db/add 42 :book/published_at #inst "2017-01-01T00:00:00.000-00:00"]
+			  [:db/add 42 :book/rating 4.5]
+			  [:db.fn/cas 42 :book/year_published nil 2017]
+			  [:db.fn/retractEntity [:book/isbn "456"]]
+			  [:book.fn/publish 42 "now"]
+			  {:db/id #db/id [:db.part/user -2]
+			   :book/title "Second Book"
+			   :book/tags [:history :politics]
+			   :book/author {:person/name "James Madison"}}
+			  {:db/id #db/id [:db.part/user -3] :person/name "Alexander Hamilton" :book/_author [42 43]}
+			  {:db/id #db/id [:db.part/user -4] :person/name "John Jay" :book/_author [:book/isbn "123"]}]`,
+			NewTx().Add(NewTx().TempID(), ":book/title", "First Book").CAS(42, ":book/year_published", 2016, 2017),
+			NewSchema(Attribute(":book/subtitle").Type(String).Doc("Subtitle of a book")),
+			RawString(`[[:db/add 42 :db/doc "A book"]]`),
+		} {
+			Ω(schema.Validate(data)).Should(BeNil())
+		}
+	})
+
+	It("should report the unknown attributes", func() {
+		err := schema.Validate(`[[:db/add 42 :book/titel "First Book"] {:db/id 43 :book/autor 44}]`)
+		Ω(err).Should(test.HaveMessage(ErrSchemaViolation))
+		Ω(err.Error()).Should(ContainSubstring("Unknown attribute: :book/titel"))
+		Ω(err.Error()).Should(ContainSubstring("Unknown attribute: :book/autor"))
+	})
+
+	It("should report the values of the wrong type", func() {
+		for data, problem := range map[string]string{
+			`[[:db/add 42 :book/year_published "2017"]]`:         `:book/year_published expects a :db.type/long: "2017"`,
+			`[[:db/add 42 :book/title 2017]]`:                    `:book/title expects a :db.type/string: 2017`,
+			`[[:db/add 42 :book/tags "history"]]`:                `:book/tags expects a :db.type/keyword: "history"`,
+			`[[:db/add 42 :book/author "James Madison"]]`:        `:book/author expects a reference: "James Madison"`,
+			`[[:db/add 42 :book/author {:person/name "James"}]]`: `:book/author expects a reference`,
+			`[[:db/add 42 :book/title nil]]`:                     `:book/title has no value: nil`,
+			`[[:db.fn/cas 42 :book/year_published 2016 "2017"]]`: `:book/year_published expects a :db.type/long: "2017"`,
+			`[{:db/id 42 :book/title ["First" "Book"]}]`:         `:book/title expects a :db.type/string`,
+			`[{:db/id 42 :book/tags [:history "politics"]}]`:     `:book/tags expects a :db.type/keyword: "politics"`,
+			`[{:db/id 42 :book/author {:person/name 1}}]`:        `:person/name expects a :db.type/string: 1`,
+			`[{:db/id 42 :person/_name 43}]`:                     `Expected the reverse of a reference: :person/_name`,
+		} {
+			err := schema.Validate(data)
+			Ω(err).Should(test.HaveMessage(ErrSchemaViolation), data)
+			Ω(err.Error()).Should(ContainSubstring(problem), data)
+		}
+	})
+
+	It("should report the invalid entities and operations", func() {
+		for data, problem := range map[string]string{
+			`[[:db/add "42" :book/title "First Book"]]`:                        `Expected an entity id, an ident, a temporary id or a lookup ref: "42"`,
+			`[[:db/add [:book/title "First Book"] :book/year_published 2017]]`: `:book/title is not unique`,
+			`[[:db/add 42 :book/title]]`:                                       `Expected an entity, an attribute and a value`,
+			`[[:db.fn/cas 42 :book/title "First Book"]]`:                       `Expected an entity, an attribute, an old and a new value`,
+			`[[:db.fn/retractEntity]]`:                                         `Expected an entity`,
+			`[["add" 42 :book/title "First Book"]]`:                            `Expected an operation`,
+			`[42]`:                                                             `Expected an operation: 42`,
+			`{:db/id 42}`:                                                      `Expected a vector of operations`,
+			`[[:db/add 42 "title" "First Book"]]`:                              `Expected an attribute: "title"`,
+		} {
+			err := schema.Validate(data)
+			Ω(err).Should(test.HaveMessage(ErrSchemaViolation), data)
+			Ω(err.Error()).Should(ContainSubstring(problem), data)
+		}
+	})
+
+	It("should report all the problems at once", func() {
+		err := schema.Validate(NewTx().
+			Add(42, ":book/titel", "First Book").
+			Add(42, ":book/year_published", "2017"))
+		Ω(err).Should(test.HaveMessage(ErrSchemaViolation))
+		Ω(err.Error()).Should(ContainSubstring(`Unknown attribute: :book/titel; :book/year_published expects a :db.type/long: "2017"`))
+	})
+
+	It("should reject the data it cannot read", func() {
+		Ω(schema.Validate(`[[:db/add 42`)).ShouldNot(BeNil())
+		Ω(schema.Validate(42)).Should(test.HaveMessage(edn.ErrInvalidInput))
+		Ω(schema.Validate(NewTx().Add(nil, ":book/title", "First Book"))).Should(test.HaveMessage(ErrInvalidTransaction))
+	})
+})
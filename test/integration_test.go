@@ -15,6 +15,7 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"github.com/Workiva/eva-client-go/edn"
 	. "github.com/onsi/ginkgo"
@@ -70,166 +71,17 @@ func (tester *httpTester) transact(format string, args ...interface{}) (r *trans
 	Ω(err).Should(BeNil())
 	Ω(conn).ShouldNot(BeNil())
 
-	result, err := conn.Transact(trx)
+	report, err := conn.Transact(trx)
 	Ω(err).Should(BeNil())
-	Ω(result).ShouldNot(BeNil())
+	Ω(report).ShouldNot(BeNil())
 
-	v, h := result.String()
+	v, h := report.String()
 	Ω(v).ShouldNot(HaveLen(0))
 	Ω(h).Should(BeTrue())
 
-	var elem edn.Element
-	if elem, err = edn.Parse(v); err == nil {
-
-		r = &transactResult{
-			rawResult:          v,
-			partitionedTempIds: make(map[string]map[int64]int64),
-			rawTempIds:         make(map[int64]int64),
-		}
-
-		if collElem, is := elem.(edn.CollectionElement); is {
-			err = collElem.IterateChildren(func(key edn.Element, value edn.Element) (e error) {
-
-				if key.ElementType() == edn.KeywordType {
-					switch keyElem := key.(edn.SymbolElement); keyElem.Name() {
-					case "tempids":
-						if keyElem.Prefix() == "eva.client.service" {
-							e = value.(edn.CollectionElement).IterateChildren(func(tempIdKey edn.Element, tempIdValue edn.Element) (e2 error) {
-								if tempIdKey.Tag() == "db/id" && tempIdKey.ElementType() == edn.VectorType && tempIdValue.ElementType() == edn.IntegerType {
-
-									keyColl := tempIdKey.(edn.CollectionElement)
-
-									var part edn.Element
-									if part, e2 = keyColl.Get(0); e2 == nil {
-										p := part.String()
-										var id edn.Element
-										if id, e2 = keyColl.Get(1); e2 == nil {
-
-											if _, has := r.partitionedTempIds[p]; !has {
-												r.partitionedTempIds[p] = make(map[int64]int64)
-											}
-											r.partitionedTempIds[p][id.Value().(int64)] = tempIdValue.Value().(int64)
-										}
-									}
-								} else {
-									e2 = fmt.Errorf("unexpected `eva.client.service/tempids` key or value: `%+v` : `%+v`", tempIdKey, tempIdValue)
-								}
-								return e2
-							})
-						} else {
-							if value.ElementType() == edn.MapType {
-								e = value.(edn.CollectionElement).IterateChildren(func(tempIdKey edn.Element, tempIdValue edn.Element) (e2 error) {
-									if tempIdKey.ElementType() == edn.IntegerType && tempIdValue.ElementType() == edn.IntegerType {
-										r.rawTempIds[tempIdKey.Value().(int64)] = tempIdValue.Value().(int64)
-									} else {
-										e2 = fmt.Errorf("unexpected `tempids` key or value: `%+v` : `%+v`", tempIdKey, tempIdValue)
-									}
-									return e2
-								})
-							} else {
-								e = fmt.Errorf("unexpected `tempids` to be a map")
-							}
-						}
-					case "db-before":
-						if value.Tag() == "eva.client.service/snapshot-ref" && value.ElementType() == edn.MapType {
-							e = value.(edn.CollectionElement).IterateChildren(func(k edn.Element, v edn.Element) (e2 error) {
-
-								if key.ElementType() == edn.KeywordType {
-									switch ke := k.(edn.SymbolElement); ke.Name() {
-									case "label":
-										r.beforeLabel = v.String()
-									case "as-of":
-										r.beforeT = v.Value().(int64)
-									default:
-										e2 = fmt.Errorf("unexpected keys in the `db-before` map, got: %s", k.String())
-									}
-								} else {
-									e2 = fmt.Errorf("expected only keyword keys in the `db-before` map, got: %s", k.String())
-								}
-								return e2
-							})
-						} else {
-							e = fmt.Errorf("unexpected `db-before` to be a map with the `eva.client.service/snapshot-ref` tag")
-						}
-					case "db-after":
-						if value.Tag() == "eva.client.service/snapshot-ref" && value.ElementType() == edn.MapType {
-							e = value.(edn.CollectionElement).IterateChildren(func(k edn.Element, v edn.Element) (e2 error) {
-
-								if key.ElementType() == edn.KeywordType {
-									switch ke := k.(edn.SymbolElement); ke.Name() {
-									case "label":
-										r.afterLabel = v.String()
-									case "as-of":
-										r.afterT = v.Value().(int64)
-									default:
-										e2 = fmt.Errorf("unexpected keys in the `db-after` map, got: %s", k.String())
-									}
-								} else {
-									e2 = fmt.Errorf("expected only keyword keys in the `db-after` map, got: %s", k.String())
-								}
-								return e2
-							})
-						} else {
-							e = fmt.Errorf("unexpected `db-before` to be a map with the `eva.client.service/snapshot-ref` tag")
-						}
-					case "tx-data":
-						if value.ElementType() == edn.ListType {
-							e = value.(edn.CollectionElement).IterateChildren(func(_ edn.Element, v edn.Element) (e2 error) {
-
-								if v.Tag() == "datom" && v.ElementType() == edn.VectorType {
-
-									vAsColl := v.(edn.CollectionElement)
-
-									var datomE edn.Element
-									var datomA edn.Element
-									var datomV edn.Element
-									var datomT edn.Element
-									var datomR edn.Element
-
-									if datomE, e2 = vAsColl.Get(0); e2 == nil {
-										if datomA, e2 = vAsColl.Get(1); e2 == nil {
-											if datomV, e2 = vAsColl.Get(2); e2 == nil {
-												if datomT, e2 = vAsColl.Get(3); e2 == nil {
-													if datomR, e2 = vAsColl.Get(4); e2 == nil {
-														r.datoms = append(r.datoms, &datom{
-															e: datomE.Value().(int64),
-															a: datomA.Value().(int64),
-															v: datomV,
-															t: datomT.Value().(int64),
-															r: datomR.Value().(bool),
-														})
-													}
-												}
-											}
-										}
-									}
-
-								} else {
-									e2 = fmt.Errorf("expected vectors with `datom` tags in `tx-data`, Got: %s", v.String())
-								}
-
-								return e2
-							})
-						} else {
-							e = fmt.Errorf("unexpected `tx-data` to be a list")
-						}
-					default:
-						e = fmt.Errorf("unexpected key value: %s", key.String())
-					}
-				} else {
-					e = fmt.Errorf("expected only keyword keys in the map, got: %s", key.String())
-				}
-
-				return e
-			})
-		} else {
-			err = fmt.Errorf("expected a collection, got: %s", v)
-		}
+	return &transactResult{
+		TransactReport: report,
 	}
-
-	Ω(err).Should(BeNil())
-	Ω(r).ShouldNot(BeNil())
-	return r
 }
 
 func (tester *httpTester) query(query string, items ...interface{}) string {
@@ -275,41 +127,26 @@ func (tester *httpTester) pullAt(t interface{}, pattern string, ids interface{},
 	return v
 }
 
-type datom struct {
-	e int64
-	a int64
-	v edn.Element
-	t int64
-	r bool
-}
-
 type transactResult struct {
-	rawResult          string
-	partitionedTempIds map[string]map[int64]int64
-	rawTempIds         map[int64]int64
-	beforeLabel        string
-	beforeT            int64
-	afterLabel         string
-	afterT             int64
-	datoms             []*datom
+	eva.TransactReport
 }
 
 func (d *transactResult) mayHaveValue(value string) {
 
 	has := false
 	var values []interface{}
-	for _, datom := range d.datoms {
-		if datom.v.ElementType() == edn.StringType {
-			if value == datom.v.Value().(string) {
+	for _, datom := range d.Datoms() {
+		if datom.V.ElementType() == edn.StringType {
+			if value == datom.V.Value().(string) {
 				has = true
 			}
 		}
 
-		values = append(values, datom.v.Value())
+		values = append(values, datom.V.Value())
 	}
 
 	if !has {
-		fmt.Printf(fmt.Sprintf("\n[WARN] Didn't have value in datoms: `%s` instead has: %v\n", value, values))
+		fmt.Printf("\n[WARN] Didn't have value in datoms: `%s` instead has: %v\n", value, values)
 	}
 }
 
@@ -317,14 +154,14 @@ func (d *transactResult) mustHaveValue(value string) {
 
 	has := false
 	var values []interface{}
-	for _, datom := range d.datoms {
-		if datom.v.ElementType() == edn.StringType {
-			if value == datom.v.Value().(string) {
+	for _, datom := range d.Datoms() {
+		if datom.V.ElementType() == edn.StringType {
+			if value == datom.V.Value().(string) {
 				has = true
 			}
 		}
 
-		values = append(values, datom.v.Value())
+		values = append(values, datom.V.Value())
 	}
 
 	if !has {
@@ -333,7 +170,7 @@ func (d *transactResult) mustHaveValue(value string) {
 }
 
 func (d *transactResult) resultTemp(partition string, id int64) (out int64) {
-	if coll, has := d.partitionedTempIds[partition]; has {
+	if coll, has := d.PartitionedTempIds()[partition]; has {
 		if out, has = coll[id]; !has {
 			Fail(fmt.Sprintf("Expected to have a temp result is [%d] in partition: `%s` but didn't", id, partition))
 		}
@@ -345,7 +182,7 @@ func (d *transactResult) resultTemp(partition string, id int64) (out int64) {
 }
 
 func (d *transactResult) dbAfterT() int64 {
-	return d.afterT
+	return d.AfterT()
 }
 
 var _ = Describe("General integration tests", func() {
@@ -500,5 +337,31 @@ var _ = Describe("General integration tests", func() {
 
 			})
 		})
+
+		Context("subscriptions", func() {
+			It("should follow the basis t of the transactions committed", func() {
+				t := newTester(host, port, generateCategoryName(), label, tenant)
+				t.transact(BookSchema)
+				book := t.transact(AddFirstBook)
+
+				conn, err := t.source.Connection(label)
+				Ω(err).Should(BeNil())
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+
+				sub, err := conn.Subscribe(ctx, book.dbAfterT()+1, eva.WithPollInterval(100*time.Millisecond))
+				Ω(err).Should(BeNil())
+
+				next := t.transact(AddJsonJasonBook)
+
+				var entry eva.TxEntry
+				Eventually(sub.Entries(), 30*time.Second).Should(Receive(&entry))
+				Ω(entry.T).Should(Equal(next.dbAfterT()))
+
+				_, err = strconv.Atoi(t.query(QueryForBookFromTitle, entry.Snapshot, "\"Json Book\""))
+				Ω(err).Should(BeNil())
+			})
+		})
 	}
 })